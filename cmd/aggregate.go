@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// aggregateFormats are the output formats supported by the aggregate output
+var aggregateFormats = []stream.Format{
+	stream.FormatText,
+	stream.FormatJSON,
+	stream.FormatCSV,
+}
+
+// writeAggregate writes the aggregate statistics of multiple
+// simulation runs to the output writer, in the given output format
+func writeAggregate(
+	writer stream.OutputWriter,
+	aggregate game.AggregateResult,
+	format stream.Format,
+) error {
+	var (
+		lines []string
+		err   error
+	)
+
+	switch format {
+	case stream.FormatJSON:
+		lines, err = encodeAggregateJSON(aggregate)
+	case stream.FormatCSV:
+		lines = encodeAggregateCSV(aggregate)
+	default:
+		lines = encodeAggregateText(aggregate)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if err := writer.Write(line); err != nil {
+			return fmt.Errorf("unable to write to output stream, %w", err)
+		}
+	}
+
+	return writer.Flush()
+}
+
+// encodeAggregateText encodes the aggregate statistics as a
+// human-readable table, with the counts and cities sorted
+func encodeAggregateText(aggregate game.AggregateResult) []string {
+	lines := []string{
+		fmt.Sprintf("Runs: %d\n", aggregate.Runs),
+		fmt.Sprintf("Average cities destroyed: %.2f\n", aggregate.AverageDestroyed),
+		fmt.Sprintf("Cities destroyed variance: %.2f\n", aggregate.DestroyedVariance),
+		fmt.Sprintf("Average surviving aliens: %.2f\n", aggregate.AverageSurvivingAliens),
+		"Cities destroyed distribution:\n",
+	}
+
+	for _, count := range sortedCounts(aggregate.DestroyedDistribution) {
+		lines = append(
+			lines,
+			fmt.Sprintf("%d %d\n", count, aggregate.DestroyedDistribution[count]),
+		)
+	}
+
+	lines = append(lines, "City survival frequency:\n")
+
+	for _, name := range sortedCities(aggregate.SurvivalRate) {
+		lines = append(lines, fmt.Sprintf("%s %.2f%%\n", name, aggregate.SurvivalRate[name]*100))
+	}
+
+	return lines
+}
+
+// encodeAggregateJSON encodes the aggregate statistics as an indented JSON document
+func encodeAggregateJSON(aggregate game.AggregateResult) ([]string, error) {
+	encoded, err := json.MarshalIndent(aggregate, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the aggregate, %w", err)
+	}
+
+	return []string{string(encoded) + "\n"}, nil
+}
+
+// encodeAggregateCSV encodes the aggregate statistics as CSV rows
+// of the metric, the metric key (destroyed city count or city name) and the value
+func encodeAggregateCSV(aggregate game.AggregateResult) []string {
+	lines := []string{
+		"metric,key,value\n",
+		fmt.Sprintf("runs,,%d\n", aggregate.Runs),
+		fmt.Sprintf("average_destroyed,,%s\n", formatFloat(aggregate.AverageDestroyed)),
+		fmt.Sprintf("destroyed_variance,,%s\n", formatFloat(aggregate.DestroyedVariance)),
+		fmt.Sprintf("average_surviving_aliens,,%s\n", formatFloat(aggregate.AverageSurvivingAliens)),
+	}
+
+	for _, count := range sortedCounts(aggregate.DestroyedDistribution) {
+		lines = append(
+			lines,
+			fmt.Sprintf("destroyed_distribution,%d,%d\n", count, aggregate.DestroyedDistribution[count]),
+		)
+	}
+
+	for _, name := range sortedCities(aggregate.SurvivalRate) {
+		lines = append(
+			lines,
+			fmt.Sprintf("survival_rate,%s,%s\n", name, formatFloat(aggregate.SurvivalRate[name])),
+		)
+	}
+
+	return lines
+}
+
+// formatFloat formats the float with the minimal precision
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// sortedCounts returns the destroyed city counts in ascending order
+func sortedCounts(distribution map[int]int) []int {
+	counts := make([]int, 0, len(distribution))
+	for count := range distribution {
+		counts = append(counts, count)
+	}
+
+	sort.Ints(counts)
+
+	return counts
+}
+
+// sortedCities returns the city names in alphabetical order
+func sortedCities(survivalRate map[string]float64) []string {
+	cities := make([]string, 0, len(survivalRate))
+	for name := range survivalRate {
+		cities = append(cities, name)
+	}
+
+	sort.Strings(cities)
+
+	return cities
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// builtinMapExtension is the extension of the
+// built-in map files, which are in the text format
+const builtinMapExtension = ".txt"
+
+var (
+	errUnknownBuiltinMap     = errors.New("unknown built-in map")
+	errBuiltinMapConflict    = errors.New("the built-in map can't be combined with the map path or the scenario")
+	errBuiltinFormatConflict = errors.New("the built-in maps are in the text format, which conflicts with the input format")
+)
+
+// builtinMapFS holds the demo maps embedded in the binary,
+// which are run with the built-in map flag, without a map file
+//
+//go:embed maps
+var builtinMapFS embed.FS
+
+// builtinMaps returns the sorted names of the built-in maps
+func builtinMaps() []string {
+	entries, _ := fs.ReadDir(builtinMapFS, "maps")
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), builtinMapExtension))
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// checkBuiltinMap makes sure the built-in map with the given name exists
+func checkBuiltinMap(name string) error {
+	for _, builtin := range builtinMaps() {
+		if name == builtin {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s (available: %s)", errUnknownBuiltinMap, name, strings.Join(builtinMaps(), ", "))
+}
+
+// openBuiltinMap returns the reader of the built-in map with the given name
+func openBuiltinMap(name string) (stream.InputReader, error) {
+	if err := checkBuiltinMap(name); err != nil {
+		return nil, err
+	}
+
+	reader, err := stream.NewFSReader(builtinMapFS, path.Join("maps", name+builtinMapExtension))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the built-in map, %w", err)
+	}
+
+	return reader, nil
+}
+
+// applyBuiltinMap makes the built-in map the map source, if it's set,
+// so the map path is not required. The built-in map can't be combined
+// with the other map sources, since it's unclear which one is run.
+// The conflicting values set outside of the command line are named by their source
+func applyBuiltinMap(cmd *cobra.Command) error {
+	if params.builtinMap == "" {
+		return nil
+	}
+
+	// The scenario sets the map path itself, so it's checked first
+	if params.scenario != nil {
+		return fmt.Errorf("%w: scenario set with %s", errBuiltinMapConflict, flagSource(cmd, scenarioFlag))
+	}
+
+	if cmd.Flags().Changed(mapPathFlag) {
+		return fmt.Errorf("%w: map path set with %s", errBuiltinMapConflict, flagSource(cmd, mapPathFlag))
+	}
+
+	if cmd.Flags().Changed(inputFormatFlag) && params.inputFormat != stream.FormatText {
+		return fmt.Errorf(
+			"%w: %s set with %s",
+			errBuiltinFormatConflict,
+			params.inputFormat,
+			flagSource(cmd, inputFormatFlag),
+		)
+	}
+
+	if err := checkBuiltinMap(params.builtinMap); err != nil {
+		return err
+	}
+
+	// The built-in maps are in the text format
+	params.inputFormat = stream.FormatText
+
+	_ = cmd.Flags().SetAnnotation(mapPathFlag, cobra.BashCompOneRequiredFlag, []string{"false"})
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// TestBuiltinMaps makes sure the built-in maps are listed,
+// and each one is a valid map without any issues
+func TestBuiltinMaps(t *testing.T) {
+	assert.Equal(t, []string{"europe", "grid"}, builtinMaps())
+
+	for _, name := range builtinMaps() {
+		reader, err := openBuiltinMap(name)
+		if err != nil {
+			t.Fatalf("unable to open the %s built-in map, %v", name, err)
+		}
+
+		earthMap := game.NewEarthMap(hclog.NewNullLogger(), game.WithStrictParsing())
+
+		assert.NoError(t, earthMap.InitMap(reader))
+		assert.Empty(t, earthMap.Validate().Issues, name)
+		assert.NoError(t, reader.Close())
+	}
+}
+
+// TestRoot_BuiltinMap makes sure the built-in map is run without a map path,
+// and the unknown or conflicting built-in maps are rejected
+func TestRoot_BuiltinMap(t *testing.T) {
+	t.Run("built-in map run", func(t *testing.T) {
+		stdout, _, err := executeRootCommand(t, "4", "--builtin-map", "europe", "--seed", "42")
+
+		assert.NotEqual(t, exitCodeError, exitCode(err))
+		assert.Contains(t, stdout, "cities were destroyed\n")
+	})
+
+	t.Run("unknown built-in map", func(t *testing.T) {
+		_, _, err := executeRootCommand(t, "4", "--builtin-map", "atlantis")
+
+		assert.ErrorIs(t, err, errUnknownBuiltinMap)
+		assert.ErrorContains(t, err, "europe, grid")
+	})
+
+	t.Run("map path conflict", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"4",
+			"--builtin-map", "europe",
+			"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+		)
+
+		assert.ErrorIs(t, err, errBuiltinMapConflict)
+		assert.ErrorContains(t, err, "map path set with the --map-path flag")
+	})
+
+	t.Run("map path conflict from the environment", func(t *testing.T) {
+		t.Setenv("ALIEN_INVASION_MAP_PATH", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"))
+
+		_, _, err := executeRootCommand(t, "4", "--builtin-map", "europe")
+
+		assert.ErrorIs(t, err, errBuiltinMapConflict)
+		assert.ErrorContains(t, err, "the ALIEN_INVASION_MAP_PATH environment variable")
+	})
+
+	t.Run("map path conflict from the config file", func(t *testing.T) {
+		configPath := writeTempConfig(
+			t,
+			"config.yaml",
+			"map-path: "+writeTempMap(t, "Foo north=Bar", "Bar south=Foo")+"\n",
+		)
+
+		_, _, err := executeRootCommand(t, "4", "--builtin-map", "europe", "--config", configPath)
+
+		assert.ErrorIs(t, err, errBuiltinMapConflict)
+		assert.ErrorContains(t, err, "the map-path key of the config file")
+	})
+
+	t.Run("scenario conflict", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"--builtin-map", "europe",
+			"--scenario", filepath.Join("..", "scenario", "testdata", "siege.yaml"),
+		)
+
+		assert.ErrorIs(t, err, errBuiltinMapConflict)
+		assert.ErrorContains(t, err, "scenario set with the --scenario flag")
+	})
+
+	t.Run("input format conflict", func(t *testing.T) {
+		_, _, err := executeRootCommand(t, "4", "--builtin-map", "europe", "--input-format", "json")
+
+		assert.ErrorIs(t, err, errBuiltinFormatConflict)
+		assert.ErrorContains(t, err, "json set with the --input-format flag")
+
+		// The text format is the format of the built-in maps
+		_, _, err = executeRootCommand(t, "4", "--builtin-map", "europe", "--input-format", "text", "--seed", "42")
+
+		assert.NotEqual(t, exitCodeError, exitCode(err))
+	})
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// Define the supported completion shells
+const (
+	shellBash       = "bash"
+	shellZsh        = "zsh"
+	shellFish       = "fish"
+	shellPowershell = "powershell"
+)
+
+var errUnsupportedShell = errors.New("unsupported shell provided")
+
+// logLevels are the log level names accepted by the logger
+var logLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR"}
+
+// newCompletionCommand creates the command that generates
+// the shell completion script for the program
+func newCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   fmt.Sprintf("completion [%s|%s|%s|%s]", shellBash, shellZsh, shellFish, shellPowershell),
+		Short: "Generates the shell completion script for the program",
+		Long: "Generates the shell completion script for the program.\n\n" +
+			"To load the completions in the current bash session, run:\n" +
+			"  source <(alien-invasion completion bash)",
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             []string{shellBash, shellZsh, shellFish, shellPowershell},
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				root   = cmd.Root()
+				output = cmd.OutOrStdout()
+			)
+
+			switch args[0] {
+			case shellBash:
+				return root.GenBashCompletionV2(output, true)
+			case shellZsh:
+				return root.GenZshCompletion(output)
+			case shellFish:
+				return root.GenFishCompletion(output, true)
+			case shellPowershell:
+				return root.GenPowerShellCompletionWithDesc(output)
+			default:
+				return fmt.Errorf("%w: %s", errUnsupportedShell, args[0])
+			}
+		},
+	}
+}
+
+// completionFunc suggests the values of a flag being completed
+type completionFunc func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)
+
+// flagCompletions maps the flags to their value completions,
+// regardless of which command they are defined on
+var flagCompletions = map[string]completionFunc{
+	mapPathFlag:    completeFilenames,
+	outputPathFlag: completeFilenames,
+	replayFileFlag: completeFilenames,
+	inFlag:         completeFilenames,
+	outFlag:        completeFilenames,
+	configFlag:     completeFilenames,
+	logLevelFlag:   completeValues(logLevels...),
+	logOutputFlag:  completeLogOutput,
+	logFormatFlag:  completeValues(logFormatText, logFormatJSON),
+	topologyFlag:   completeValues(topologyRandom, topologyGrid),
+	strategyFlag:   completeStrategies,
+
+	collisionFlag:    completeValues(game.CollisionStrategies()...),
+	builtinMapFlag:   completeValues(builtinMaps()...),
+	checksumFileFlag: completeFilenames,
+	outputFormatFlag: completeFormats(stream.OutputFormats()),
+	inputFormatFlag:  completeFormats(stream.InputFormats()),
+	outFormatFlag:    completeFormats(stream.OutputFormats()),
+	inFormatFlag:     completeFormats(stream.InputFormats()),
+}
+
+// registerFlagCompletions registers the value completions for the flags
+// of the command and all of its subcommands
+func registerFlagCompletions(cmd *cobra.Command) {
+	for name, completion := range flagCompletions {
+		if cmd.Flags().Lookup(name) == nil {
+			continue
+		}
+
+		_ = cmd.RegisterFlagCompletionFunc(name, completion)
+	}
+
+	for _, subCmd := range cmd.Commands() {
+		registerFlagCompletions(subCmd)
+	}
+}
+
+// completeFilenames defers to the shell's file name completion
+func completeFilenames(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveDefault
+}
+
+// completeValues suggests the enumerated values that match the completed prefix
+func completeValues(values ...string) completionFunc {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return matchPrefix(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeFormats suggests the supported formats that match the completed prefix
+func completeFormats(formats []stream.Format) completionFunc {
+	names := make([]string, len(formats))
+
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+
+	return completeValues(names...)
+}
+
+// completeLogOutput suggests the special log output destinations,
+// while also allowing the shell to complete a file path
+func completeLogOutput(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return matchPrefix([]string{logOutputStdout, logOutputStderr}, toComplete), cobra.ShellCompDirectiveDefault
+}
+
+// completeStrategies suggests the registered movement strategies that match the completed prefix.
+// The strategies are listed on completion, so the ones registered by other packages are included
+func completeStrategies(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeValues(game.Strategies()...)(cmd, args, toComplete)
+}
+
+// matchPrefix returns the values that start with the prefix, ignoring the case
+func matchPrefix(values []string, prefix string) []string {
+	matches := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if strings.HasPrefix(strings.ToLower(value), strings.ToLower(prefix)) {
+			matches = append(matches, value)
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompletion_Values makes sure the completion functions
+// suggest the expected flag values
+func TestCompletion_Values(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name                string
+		completion          completionFunc
+		toComplete          string
+		expectedValues      []string
+		expectedShellAction cobra.ShellCompDirective
+	}{
+		{
+			"all log levels",
+			flagCompletions[logLevelFlag],
+			"",
+			[]string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR"},
+			cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			"log level prefix ignores case",
+			flagCompletions[logLevelFlag],
+			"de",
+			[]string{"DEBUG"},
+			cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			"log formats",
+			flagCompletions[logFormatFlag],
+			"",
+			[]string{logFormatText, logFormatJSON},
+			cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			"topologies",
+			flagCompletions[topologyFlag],
+			"gr",
+			[]string{topologyGrid},
+			cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			"unknown value",
+			flagCompletions[topologyFlag],
+			"ring",
+			[]string{},
+			cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			"log outputs allow files",
+			flagCompletions[logOutputFlag],
+			"std",
+			[]string{logOutputStdout, logOutputStderr},
+			cobra.ShellCompDirectiveDefault,
+		},
+		{
+			"map path",
+			flagCompletions[mapPathFlag],
+			"",
+			nil,
+			cobra.ShellCompDirectiveDefault,
+		},
+		{
+			"output path",
+			flagCompletions[outputPathFlag],
+			"",
+			nil,
+			cobra.ShellCompDirectiveDefault,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			values, directive := testCase.completion(nil, nil, testCase.toComplete)
+
+			assert.Equal(t, testCase.expectedValues, values)
+			assert.Equal(t, testCase.expectedShellAction, directive)
+		})
+	}
+}
+
+// TestCompletion_Registered makes sure the flag completions
+// are registered on the subcommands as well
+func TestCompletion_Registered(t *testing.T) {
+	stdout, _, err := executeRootCommand(t, cobra.ShellCompRequestCmd, "generate", "--topology", "")
+	if err != nil {
+		t.Fatalf("unable to request the completions, %v", err)
+	}
+
+	assert.Equal(t, []string{topologyRandom, topologyGrid, ":4"}, strings.Split(strings.TrimSpace(stdout), "\n"))
+}
+
+// TestCompletion_Script makes sure the completion
+// scripts are generated for the supported shells
+func TestCompletion_Script(t *testing.T) {
+	for _, shell := range []string{shellBash, shellZsh, shellFish, shellPowershell} {
+		stdout, _, err := executeRootCommand(t, "completion", shell)
+		if err != nil {
+			t.Fatalf("unable to generate the %s completion, %v", shell, err)
+		}
+
+		assert.NotEmpty(t, stdout)
+	}
+
+	_, _, err := executeRootCommand(t, "completion", "tcsh")
+	assert.Error(t, err)
+}
+
+// TestCompletion_ScriptProgramName makes sure the completion
+// scripts are registered for the program name
+func TestCompletion_ScriptProgramName(t *testing.T) {
+	testTable := []struct {
+		shell            string
+		expectedContents []string
+	}{
+		{
+			shellBash,
+			[]string{"__start_alien-invasion", "complete -o default -F __start_alien-invasion alien-invasion"},
+		},
+		{
+			shellZsh,
+			[]string{"#compdef alien-invasion", "_alien-invasion()"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.shell, func(t *testing.T) {
+			stdout, _, err := executeRootCommand(t, "completion", testCase.shell)
+			if err != nil {
+				t.Fatalf("unable to generate the %s completion, %v", testCase.shell, err)
+			}
+
+			for _, expectedContent := range testCase.expectedContents {
+				assert.Contains(t, stdout, expectedContent)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errUnknownConfigKeys  = errors.New("unknown config file keys")
+	errInvalidConfigValue = errors.New("invalid config file value")
+)
+
+// nonConfigFlags are the root command flags that can't be set in the config file
+var nonConfigFlags = map[string]struct{}{
+	configFlag:      {},
+	printConfigFlag: {},
+	scenarioFlag:    {},
+	"help":          {},
+	"version":       {},
+}
+
+// isConfigFlag checks if the flag can be set in the config file
+func isConfigFlag(flag *pflag.Flag) bool {
+	_, excluded := nonConfigFlags[flag.Name]
+
+	return !excluded
+}
+
+// loadConfig reads the config file, which holds the flag values keyed by the flag names.
+// The config file is in the YAML format, which also accepts JSON
+func loadConfig(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the config file, %w", err)
+	}
+
+	values := make(map[string]interface{})
+
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("unable to decode the config file, %w", err)
+	}
+
+	return values, nil
+}
+
+// applyConfig sets the flags to the config file values. The flags set on the
+// command line (or with the environment variables) take precedence over the config file,
+// which takes precedence over the defaults. The positional number of aliens also takes precedence.
+// Returns an error listing every unknown key, so typos don't go unnoticed
+func applyConfig(cmd *cobra.Command, args []string, values map[string]interface{}) error {
+	keys := make([]string, 0, len(values))
+	unknown := make([]string, 0)
+
+	for key := range values {
+		keys = append(keys, key)
+
+		if flag := cmd.Flags().Lookup(key); flag == nil || !isConfigFlag(flag) {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+
+		return fmt.Errorf("%w: %s", errUnknownConfigKeys, strings.Join(unknown, ", "))
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		flag := cmd.Flags().Lookup(key)
+
+		if flag.Changed || (flag.Name == aliensFlag && len(args) > 0) {
+			continue
+		}
+
+		value, err := configValue(values[key])
+		if err != nil {
+			return fmt.Errorf("%w: %s, %v", errInvalidConfigValue, key, err)
+		}
+
+		if err := cmd.Flags().Set(flag.Name, value); err != nil {
+			return fmt.Errorf("%w: %s, %v", errInvalidConfigValue, key, err)
+		}
+
+		setFlagSource(cmd, flag.Name, fmt.Sprintf("the %s key of the config file", key))
+	}
+
+	return nil
+}
+
+// configValue converts the config file value into the flag value
+func configValue(value interface{}) (string, error) {
+	switch value.(type) {
+	case string, bool, int, float64:
+		return fmt.Sprint(value), nil
+	default:
+		return "", fmt.Errorf("expected a scalar value, got %v", value)
+	}
+}
+
+// writeEffectiveConfig writes out the resolved values of the flags that can be set
+// in the config file, in the config file format. The random seed is only picked
+// when the invasion is simulated, so the seed is left out unless it's set.
+// The format alias is left out, since the output format holds its value
+func writeEffectiveConfig(w io.Writer, cmd *cobra.Command) error {
+	values := make(map[string]interface{})
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		switch {
+		case !isConfigFlag(flag):
+		case flag.Name == aliensFlag:
+			values[flag.Name] = params.n
+		case flag.Name == seedFlag && !flag.Changed:
+		case flag.Name == formatFlag:
+		default:
+			values[flag.Name] = typedFlagValue(flag)
+		}
+	})
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(values); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// typedFlagValue returns the flag value, typed
+// as a number or a boolean when applicable
+func typedFlagValue(flag *pflag.Flag) interface{} {
+	value := flag.Value.String()
+
+	switch flag.Value.Type() {
+	case "int", "int64":
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number
+		}
+	case "bool":
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+
+	return value
+}
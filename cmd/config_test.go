@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// writeTempConfig writes the config file contents to a temporary file
+func writeTempConfig(t *testing.T, name, contents string) string {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(configPath, []byte(contents), 0o600); err != nil {
+		t.Fatalf("unable to write config file, %v", err)
+	}
+
+	return configPath
+}
+
+// printConfig prints the resolved configuration for the given arguments
+func printConfig(t *testing.T, args ...string) map[string]interface{} {
+	t.Helper()
+
+	stdout, _, err := executeRootCommand(t, append(args, "--print-config")...)
+	if err != nil {
+		t.Fatalf("unable to print the config, %v", err)
+	}
+
+	config := make(map[string]interface{})
+
+	if err := yaml.Unmarshal([]byte(stdout), &config); err != nil {
+		t.Fatalf("unable to decode the printed config, %v", err)
+	}
+
+	return config
+}
+
+// TestRoot_Config makes sure the flags take precedence over
+// the config file, which takes precedence over the defaults
+func TestRoot_Config(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	testTable := []struct {
+		name     string
+		file     string
+		contents string
+		args     []string
+		expected map[string]interface{}
+	}{
+		{
+			"config file over defaults",
+			"scenario.yaml",
+			"map-path: " + mapPath + "\naliens: 3\nseed: 7\nmax-moves: 20\nformat: json\n",
+			nil,
+			map[string]interface{}{
+				"aliens":        3,
+				"seed":          7,
+				"max-moves":     20,
+				"output-format": "json",
+				"runs":          1,
+			},
+		},
+		{
+			"flags over config file",
+			"scenario.yaml",
+			"map-path: " + mapPath + "\naliens: 3\nmax-moves: 20\nruns: 5\n",
+			[]string{"--max-moves", "8", "--aliens", "4"},
+			map[string]interface{}{
+				"aliens":    4,
+				"max-moves": 8,
+				"runs":      5,
+			},
+		},
+		{
+			"argument over config file",
+			"scenario.yaml",
+			"map-path: " + mapPath + "\naliens: 3\n",
+			[]string{"6"},
+			map[string]interface{}{
+				"aliens": 6,
+			},
+		},
+		{
+			"JSON config file",
+			"scenario.json",
+			`{"map-path": "` + mapPath + `", "aliens": 2, "quiet": true, "timeout": "30s"}`,
+			nil,
+			map[string]interface{}{
+				"aliens":    2,
+				"quiet":     true,
+				"log-level": "error",
+				"timeout":   "30s",
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			configPath := writeTempConfig(t, testCase.file, testCase.contents)
+
+			config := printConfig(t, append(testCase.args, "--config", configPath)...)
+
+			for key, value := range testCase.expected {
+				assert.Equal(t, value, config[key], key)
+			}
+
+			assert.Equal(t, mapPath, config[mapPathFlag])
+		})
+	}
+}
+
+// TestRoot_Config_PrintedRoundTrip makes sure the printed
+// configuration can be used as the config file
+func TestRoot_Config_PrintedRoundTrip(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	stdout, _, err := executeRootCommand(t, "5", "--map-path", mapPath, "--seed", "3", "--print-config")
+	if err != nil {
+		t.Fatalf("unable to print the config, %v", err)
+	}
+
+	config := printConfig(t, "--config", writeTempConfig(t, "printed.yaml", stdout))
+
+	assert.Equal(t, 5, config[aliensFlag])
+	assert.Equal(t, 3, config[seedFlag])
+}
+
+// TestRoot_Config_Invalid makes sure invalid config files are rejected,
+// and every unknown key is reported
+func TestRoot_Config_Invalid(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	testTable := []struct {
+		name          string
+		contents      string
+		expectedErr   error
+		expectedCause string
+	}{
+		{
+			"unknown keys",
+			"aliens: 3\nmapPath: " + mapPath + "\nmax_moves: 5\nversion: true\n",
+			errUnknownConfigKeys,
+			"mapPath, max_moves, version",
+		},
+		{
+			"invalid value",
+			"aliens: 3\nmap-path: " + mapPath + "\nmax-moves: lots\n",
+			errInvalidConfigValue,
+			"max-moves",
+		},
+		{
+			"nested value",
+			"aliens: 3\nmap-path: " + mapPath + "\nruns: [1, 2]\n",
+			errInvalidConfigValue,
+			"expected a scalar value",
+		},
+		{
+			"malformed file",
+			"aliens: [3",
+			nil,
+			"unable to decode the config file",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			_, _, err := executeRootCommand(t, "--config", writeTempConfig(t, "scenario.yaml", testCase.contents))
+
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+			}
+
+			assert.ErrorContains(t, err, testCase.expectedCause)
+		})
+	}
+
+	t.Run("missing aliens", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"--config", writeTempConfig(t, "scenario.yaml", "map-path: "+mapPath+"\n"),
+		)
+
+		assert.ErrorIs(t, err, errAlienNumberMissing)
+	})
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// Define the present flags for the convert command
+const (
+	inFlag        = "in"
+	inFormatFlag  = "in-format"
+	outFlag       = "out"
+	outFormatFlag = "out-format"
+)
+
+// convertParams defines the storage for
+// the convert command arguments
+type convertParams struct {
+	inPath    string
+	inFormat  stream.Format
+	outPath   string
+	outFormat stream.Format
+}
+
+// newConvertCommand creates the command that converts
+// a map between formats, without simulating the invasion
+func newConvertCommand() *cobra.Command {
+	convertParams := &convertParams{
+		inFormat:  stream.FormatText,
+		outFormat: stream.FormatText,
+	}
+
+	convertCmd := &cobra.Command{
+		Use:          "convert",
+		Short:        "Converts the map file to another format, without simulating the invasion",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			convertParams.detectFormats(cmd)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConvert(cmd, convertParams)
+		},
+	}
+
+	convertCmd.Flags().StringVar(
+		&convertParams.inPath,
+		inFlag,
+		"",
+		fmt.Sprintf("The path (or http(s) URL) to the input map file of the Earth, or %q for the standard input", stdinPath),
+	)
+
+	convertCmd.Flags().Var(
+		newFormatValue(&convertParams.inFormat, stream.InputFormats()),
+		inFormatFlag,
+		fmt.Sprintf(
+			"The format of the input map (%s). If omitted, the format is detected from the input path extension",
+			joinFormats(stream.InputFormats()),
+		),
+	)
+
+	convertCmd.Flags().StringVar(
+		&convertParams.outPath,
+		outFlag,
+		"",
+		"The path to output the converted map. If omitted, the output is directed to the console",
+	)
+
+	convertCmd.Flags().Var(
+		newFormatValue(&convertParams.outFormat, stream.OutputFormats()),
+		outFormatFlag,
+		fmt.Sprintf(
+			"The format of the converted map (%s). If omitted, the format is inferred from the output path extension",
+			joinFormats(stream.OutputFormats()),
+		),
+	)
+
+	_ = convertCmd.MarkFlagRequired(inFlag)
+
+	return convertCmd
+}
+
+// detectFormats detects the omitted formats from the path extensions
+func (c *convertParams) detectFormats(cmd *cobra.Command) {
+	if !cmd.Flags().Changed(inFormatFlag) && c.inPath != stdinPath {
+		c.inFormat = stream.DetectInputFormat(c.inPath)
+	}
+
+	if !cmd.Flags().Changed(outFormatFlag) && c.outPath != "" {
+		c.outFormat = stream.FormatFromPath(c.outPath)
+	}
+}
+
+// runConvert runs the convert command
+func runConvert(cmd *cobra.Command, convertParams *convertParams) error {
+	reader, err := getInputReader(cmd, convertParams.inPath, convertParams.inFormat)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	// Malformed lines fail the conversion instead of being dropped,
+	// and the neighbors keep the order they were declared in
+	earthMap := game.NewEarthMap(
+		hclog.NewNullLogger(),
+		game.WithStrictParsing(),
+		game.WithPreservedOrder(),
+	)
+
+	if err := initMap(reader, earthMap); err != nil {
+		return err
+	}
+
+	writer, err := getDestinationWriter(cmd, convertParams.outPath, stream.FileWriterOptions{})
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(writer, func(writer stream.OutputWriter) error {
+		return earthMap.WriteOutputFormat(writer, convertParams.outFormat)
+	})
+}
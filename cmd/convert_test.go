@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConvert_JSON makes sure a text map is
+// converted into a valid JSON map
+func TestConvert_JSON(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar west=Baz", "Bar south=Foo")
+
+	stdout, _, err := executeRootCommand(t, "convert", "--in", mapPath, "--out-format", "json")
+	if err != nil {
+		t.Fatalf("unable to convert the map, %v", err)
+	}
+
+	assert.True(t, json.Valid([]byte(stdout)))
+
+	var converted struct {
+		Cities []struct {
+			Name      string            `json:"name"`
+			Neighbors map[string]string `json:"neighbors"`
+		} `json:"cities"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &converted))
+	assert.Len(t, converted.Cities, 3)
+
+	for _, city := range converted.Cities {
+		if city.Name == "Foo" {
+			assert.Equal(t, map[string]string{"north": "Bar", "west": "Baz"}, city.Neighbors)
+		}
+	}
+}
+
+// TestConvert_DetectedFormats makes sure the formats are detected
+// from the path extensions, so the map survives a round trip
+func TestConvert_DetectedFormats(t *testing.T) {
+	var (
+		mapPath = writeTempMap(t, "Bar south=Foo", "Foo north=Bar")
+		dotPath = filepath.Join(t.TempDir(), "world.dot")
+	)
+
+	_, _, err := executeRootCommand(t, "convert", "--in", mapPath, "--out", dotPath)
+	if err != nil {
+		t.Fatalf("unable to convert the map to DOT, %v", err)
+	}
+
+	dot, err := os.ReadFile(dotPath)
+	if err != nil {
+		t.Fatalf("unable to read the DOT map, %v", err)
+	}
+
+	assert.Contains(t, string(dot), "graph earth {")
+
+	stdout, _, err := executeRootCommand(t, "convert", "--in", dotPath)
+	if err != nil {
+		t.Fatalf("unable to convert the map back to text, %v", err)
+	}
+
+	assert.Equal(t, "Bar south=Foo\nFoo north=Bar\n", stdout)
+}
+
+// TestConvert_InvalidMap makes sure malformed map
+// lines fail the conversion, instead of being dropped
+func TestConvert_InvalidMap(t *testing.T) {
+	_, _, err := executeRootCommand(t, "convert", "--in", writeTempMap(t, "Foo north="))
+
+	assert.ErrorContains(t, err, "unable to initialize the map")
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+const (
+	dashboardInterval = 250 * time.Millisecond // the interval between the dashboard updates
+	dashboardFeedSize = 10                     // the number of the latest destructions the dashboard lists
+	dashboardBarWidth = 40                     // the width of the destroyed cities bar
+)
+
+// clearScreen moves the cursor to the top left corner
+// of the terminal, and clears the screen
+const clearScreen = "\x1b[H\x1b[2J"
+
+// dashboard renders a live view of the running simulation on the terminal:
+// the wandering aliens, the destroyed cities, and the feed of the latest destructions.
+// The whole view is redrawn on each update
+type dashboard struct {
+	output io.Writer        // the terminal the dashboard is rendered to
+	now    func() time.Time // the clock the elapsed time is measured with
+	start  time.Time        // the start of the simulation
+
+	progress    func() game.Progress // the source of the simulation progress
+	totalCities int                  // the number of cities on the map
+	totalAliens int                  // the number of aliens placed on the map
+
+	mux  sync.Mutex
+	feed []string // the latest destructions, the oldest first
+}
+
+// newDashboard creates a new dashboard, with the elapsed time
+// measured from the moment of creation
+func newDashboard(output io.Writer, now func() time.Time) *dashboard {
+	return &dashboard{
+		output: output,
+		now:    now,
+		start:  now(),
+		progress: func() game.Progress {
+			return game.Progress{}
+		},
+		feed: make([]string, 0, dashboardFeedSize),
+	}
+}
+
+// track sets the source of the simulation progress, and the totals the
+// progress is measured against. It needs to be called before the simulation starts
+func (d *dashboard) track(progress func() game.Progress, totalCities, totalAliens int) {
+	d.progress = progress
+	d.totalCities = totalCities
+	d.totalAliens = totalAliens
+}
+
+// onDestruction adds the destruction to the feed. It is the destruction
+// listener of the simulation, so it's called from the alien routines concurrently
+func (d *dashboard) onDestruction(destruction game.Destruction) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if len(d.feed) == dashboardFeedSize {
+		d.feed = d.feed[1:]
+	}
+
+	d.feed = append(d.feed, fmt.Sprintf(
+		"%8s  %s",
+		d.now().Sub(d.start).Truncate(time.Second),
+		destructionMessage(destruction),
+	))
+}
+
+// render redraws the dashboard with the current progress
+func (d *dashboard) render() {
+	var (
+		sb       strings.Builder
+		progress = d.progress()
+	)
+
+	sb.WriteString(clearScreen)
+	sb.WriteString("Alien invasion\n\n")
+	sb.WriteString(fmt.Sprintf("Elapsed:           %s\n", d.now().Sub(d.start).Truncate(time.Second)))
+	sb.WriteString(fmt.Sprintf("Aliens wandering:  %d / %d\n", progress.AliensRemaining, d.totalAliens))
+	sb.WriteString(fmt.Sprintf("Cities destroyed:  %d / %d\n", progress.CitiesDestroyed, d.totalCities))
+	sb.WriteString(destroyedBar(progress.CitiesDestroyed, d.totalCities))
+	sb.WriteString("\n\nLatest destructions:\n")
+
+	d.mux.Lock()
+
+	if len(d.feed) == 0 {
+		sb.WriteString("  none yet\n")
+	}
+
+	for _, line := range d.feed {
+		sb.WriteString(fmt.Sprintf("  %s\n", line))
+	}
+
+	d.mux.Unlock()
+
+	_, _ = io.WriteString(d.output, sb.String())
+}
+
+// finish renders the final state of the dashboard, which is left on the terminal
+func (d *dashboard) finish() {
+	d.render()
+
+	_, _ = fmt.Fprintln(d.output)
+}
+
+// destroyedBar renders the share of the destroyed cities as a bar
+func destroyedBar(destroyed, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = destroyed * dashboardBarWidth / total
+	}
+
+	percentage := 0
+	if total > 0 {
+		percentage = destroyed * 100 / total
+	}
+
+	return fmt.Sprintf(
+		"[%s%s] %d%%",
+		strings.Repeat("#", filled),
+		strings.Repeat(".", dashboardBarWidth-filled),
+		percentage,
+	)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// TestDashboard_Render makes sure the dashboard is redrawn with the
+// current progress, and only lists the latest destructions
+func TestDashboard_Render(t *testing.T) {
+	var (
+		output   bytes.Buffer
+		clock    = &fakeClock{current: time.Date(2022, 10, 29, 21, 58, 14, 0, time.UTC)}
+		progress = game.Progress{AliensRemaining: 100, CitiesDestroyed: 0}
+	)
+
+	d := newDashboard(&output, clock.now)
+	d.track(func() game.Progress {
+		return progress
+	}, 40, 100)
+
+	d.render()
+
+	assert.True(t, strings.HasPrefix(output.String(), clearScreen))
+	assert.Contains(t, output.String(), "Aliens wandering:  100 / 100\n")
+	assert.Contains(t, output.String(), "Cities destroyed:  0 / 40\n")
+	assert.Contains(t, output.String(), "none yet\n")
+
+	// Feed a stream of destructions, more than the dashboard lists
+	for i := 0; i < dashboardFeedSize+5; i++ {
+		clock.advance(time.Second)
+
+		d.onDestruction(game.Destruction{
+			City:   fmt.Sprintf("City%d", i),
+			Aliens: []int{2 * i, 2*i + 1},
+		})
+	}
+
+	progress = game.Progress{AliensRemaining: 70, CitiesDestroyed: 15}
+
+	output.Reset()
+	d.render()
+
+	frame := output.String()
+
+	assert.True(t, strings.HasPrefix(frame, clearScreen))
+	assert.Contains(t, frame, "Elapsed:           15s\n")
+	assert.Contains(t, frame, "Aliens wandering:  70 / 100\n")
+	assert.Contains(t, frame, "Cities destroyed:  15 / 40\n")
+	assert.Contains(t, frame, "] 37%")
+	assert.NotContains(t, frame, "none yet")
+
+	// Only the latest destructions are listed
+	for i := 0; i < 5; i++ {
+		assert.NotContains(t, frame, fmt.Sprintf("City%d has", i))
+	}
+
+	for i := 5; i < dashboardFeedSize+5; i++ {
+		assert.Contains(t, frame, fmt.Sprintf("City%d has been destroyed by alien %d and alien %d!", i, 2*i, 2*i+1))
+	}
+
+	assert.Contains(t, frame, "     15s  City14 has been destroyed")
+}
+
+// TestDashboard_Finish makes sure the final state
+// of the dashboard is left on the terminal
+func TestDashboard_Finish(t *testing.T) {
+	var (
+		output bytes.Buffer
+		clock  = &fakeClock{current: time.Date(2022, 10, 29, 21, 58, 14, 0, time.UTC)}
+	)
+
+	d := newDashboard(&output, clock.now)
+	d.track(func() game.Progress {
+		return game.Progress{CitiesDestroyed: 2}
+	}, 2, 4)
+
+	d.finish()
+
+	assert.Contains(t, output.String(), "Cities destroyed:  2 / 2\n")
+	assert.Contains(t, output.String(), "] 100%")
+	assert.True(t, strings.HasSuffix(output.String(), "\n\n"))
+}
+
+// TestDestroyedBar makes sure the bar is filled
+// with the share of the destroyed cities
+func TestDestroyedBar(t *testing.T) {
+	assert.Equal(t, "["+strings.Repeat(".", dashboardBarWidth)+"] 0%", destroyedBar(0, 0))
+	assert.Equal(t, "["+strings.Repeat(".", dashboardBarWidth)+"] 0%", destroyedBar(0, 10))
+	assert.Equal(
+		t,
+		"["+strings.Repeat("#", dashboardBarWidth/2)+strings.Repeat(".", dashboardBarWidth/2)+"] 50%",
+		destroyedBar(5, 10),
+	)
+	assert.Equal(t, "["+strings.Repeat("#", dashboardBarWidth)+"] 100%", destroyedBar(10, 10))
+}
+
+// TestRoot_TUI makes sure the dashboard falls back to the regular
+// output when the standard output is not a terminal, and only
+// supports a single, non-interactive run
+func TestRoot_TUI(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	t.Run("not a terminal", func(t *testing.T) {
+		stdout, stderr, err := executeRootCommand(t, "2", "--map-path", mapPath, "--tui", "--seed", "1")
+
+		assert.NoError(t, err)
+		assert.NotContains(t, stdout, clearScreen)
+		assert.Contains(t, stdout, "has been destroyed by alien 0 and alien 1!\n")
+		assert.Contains(t, stderr, "The dashboard is disabled, since the standard output is not a terminal")
+	})
+
+	t.Run("multiple runs", func(t *testing.T) {
+		_, _, err := executeRootCommand(t, "2", "--map-path", mapPath, "--tui", "--runs", "2")
+
+		assert.ErrorIs(t, err, errTUIUnsupported)
+	})
+}
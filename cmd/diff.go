@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// Define the present flags for the diff command
+const (
+	beforeFlag = "before"
+	afterFlag  = "after"
+)
+
+// diffParams defines the storage for
+// the diff command arguments
+type diffParams struct {
+	beforePath string
+	afterPath  string
+	json       bool
+}
+
+// newDiffCommand creates the command that compares
+// the input map to the map left after the invasion
+func newDiffCommand() *cobra.Command {
+	diffParams := &diffParams{}
+
+	diffCmd := &cobra.Command{
+		Use:          "diff",
+		Short:        "Prints the cities destroyed and the roads severed between the input map and the output map",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDiff(cmd, diffParams)
+		},
+	}
+
+	diffCmd.Flags().StringVar(
+		&diffParams.beforePath,
+		beforeFlag,
+		"",
+		"The path to the input map file, before the invasion",
+	)
+
+	diffCmd.Flags().StringVar(
+		&diffParams.afterPath,
+		afterFlag,
+		"",
+		"The path to the output map file, after the invasion",
+	)
+
+	diffCmd.Flags().BoolVar(
+		&diffParams.json,
+		jsonFlag,
+		false,
+		"Output the map diff in JSON format",
+	)
+
+	_ = diffCmd.MarkFlagRequired(beforeFlag)
+	_ = diffCmd.MarkFlagRequired(afterFlag)
+
+	return diffCmd
+}
+
+// runDiff runs the diff command
+func runDiff(cmd *cobra.Command, diffParams *diffParams) error {
+	var (
+		before = game.NewEarthMap(hclog.NewNullLogger())
+		after  = game.NewEarthMap(hclog.NewNullLogger())
+	)
+
+	if err := loadMap(diffParams.beforePath, before); err != nil {
+		return fmt.Errorf("unable to load the map before the invasion, %w", err)
+	}
+
+	if err := loadMap(diffParams.afterPath, after); err != nil {
+		return fmt.Errorf("unable to load the map after the invasion, %w", err)
+	}
+
+	var (
+		diff = game.DiffMaps(before, after)
+		err  error
+	)
+
+	if diffParams.json {
+		err = writeJSONDiff(cmd.OutOrStdout(), diff)
+	} else {
+		err = writeTextDiff(cmd.OutOrStdout(), diff)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to write the map diff, %w", err)
+	}
+
+	return nil
+}
+
+// writeTextDiff writes out the map diff as a human-readable summary,
+// with the severed roads in the map file format
+func writeTextDiff(w io.Writer, diff game.MapDiff) error {
+	if _, err := fmt.Fprintf(w, "Destroyed cities: %d\n", len(diff.DestroyedCities)); err != nil {
+		return err
+	}
+
+	for _, city := range diff.DestroyedCities {
+		if _, err := fmt.Fprintf(w, "  %s\n", city); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "Severed roads: %d\n", len(diff.SeveredRoads)); err != nil {
+		return err
+	}
+
+	for _, road := range diff.SeveredRoads {
+		if _, err := fmt.Fprintf(w, "  %s %s=%s\n", road.City, road.Direction, road.Neighbor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJSONDiff writes out the map diff in JSON format
+func writeJSONDiff(w io.Writer, diff game.MapDiff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(diff)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiff_Table makes sure the map diff is
+// printed out as a summary by default
+func TestDiff_Table(t *testing.T) {
+	var (
+		beforePath = writeTempMap(t, "Foo north=Bar east=Baz", "Bar east=Bee", "Baz")
+		afterPath  = writeTempMap(t, "Foo east=Baz", "Baz", "Bee")
+	)
+
+	stdout, _, err := executeRootCommand(t, "diff", "--before", beforePath, "--after", afterPath)
+	if err != nil {
+		t.Fatalf("unable to run the diff command, %v", err)
+	}
+
+	expectedOutput := "Destroyed cities: 1\n" +
+		"  Bar\n" +
+		"Severed roads: 2\n" +
+		"  Bee west=Bar\n" +
+		"  Foo north=Bar\n"
+
+	assert.Equal(t, expectedOutput, stdout)
+}
+
+// TestDiff_JSON makes sure the map diff is
+// properly output in JSON format
+func TestDiff_JSON(t *testing.T) {
+	var (
+		beforePath = writeTempMap(t, "Foo north=Bar", "Baz")
+		afterPath  = writeTempMap(t, "Foo", "Baz")
+	)
+
+	stdout, _, err := executeRootCommand(t, "diff", "--before", beforePath, "--after", afterPath, "--json")
+	if err != nil {
+		t.Fatalf("unable to run the diff command, %v", err)
+	}
+
+	var fields map[string]interface{}
+
+	if err := json.Unmarshal([]byte(stdout), &fields); err != nil {
+		t.Fatalf("unable to unmarshal the diff, %v", err)
+	}
+
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"destroyedCities": []interface{}{"Bar"},
+			"severedRoads": []interface{}{
+				map[string]interface{}{"city": "Foo", "direction": "north", "neighbor": "Bar"},
+			},
+		},
+		fields,
+	)
+}
+
+// TestDiff_Simulation makes sure the output map of the simulation
+// can be compared to the input map, reporting the cities no longer on it
+func TestDiff_Simulation(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo", "Baz")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	_, _, err := executeRootCommand(t, "6", "--map-path", mapPath, "--output-path", outputPath, "--seed", "1")
+	assert.NoError(t, err)
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	stdout, _, err := executeRootCommand(t, "diff", "--before", mapPath, "--after", outputPath)
+	assert.NoError(t, err)
+
+	for _, city := range []string{"Foo", "Bar", "Baz"} {
+		if strings.Contains(string(output), city) {
+			assert.NotContains(t, stdout, "  "+city+"\n")
+		} else {
+			assert.Contains(t, stdout, "  "+city+"\n")
+		}
+	}
+}
+
+// TestDiff_MissingMap makes sure a map that
+// can't be loaded is reported
+func TestDiff_MissingMap(t *testing.T) {
+	beforePath := writeTempMap(t, "Foo north=Bar")
+
+	_, _, err := executeRootCommand(
+		t,
+		"diff",
+		"--before", beforePath,
+		"--after", filepath.Join(t.TempDir(), "missing.txt"),
+	)
+
+	assert.ErrorContains(t, err, "unable to load the map after the invasion")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// runDryRun loads and validates the map, and reports the effective parameters
+// along with the map stats and issues, without simulating the invasion.
+// Nothing is written to the output path.
+// Returns an error if the map is not valid
+func runDryRun(
+	cmd *cobra.Command,
+	reader stream.InputReader,
+	earthMap *game.EarthMap,
+	seed int64,
+	randomSeed bool,
+) error {
+	report := loadAndValidate(reader, earthMap)
+	output := cmd.OutOrStdout()
+
+	if err := writeDryRunParams(output, seed, randomSeed); err != nil {
+		return fmt.Errorf("unable to write the dry run report, %w", err)
+	}
+
+	// A map that failed to load has no stats to report
+	if report.Cities > 0 {
+		if err := writeTextStats(output, earthMap.Stats()); err != nil {
+			return fmt.Errorf("unable to write the dry run report, %w", err)
+		}
+	}
+
+	if err := writeTextIssues(output, report); err != nil {
+		return fmt.Errorf("unable to write the dry run report, %w", err)
+	}
+
+	// The parameters are valid at this point, so the usage is not printed
+	if report.HasErrors() {
+		cmd.SilenceUsage = true
+
+		return errMapInvalid
+	}
+
+	return nil
+}
+
+// writeDryRunParams writes out the effective parameters of the invasion
+func writeDryRunParams(w io.Writer, seed int64, randomSeed bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	seedSource := "provided"
+	if randomSeed {
+		seedSource = "random"
+	}
+
+	output := params.outputPath
+	if output == "" {
+		output = "console"
+	}
+
+	timeout := "none"
+	if params.timeout > 0 {
+		timeout = params.timeout.String()
+	}
+
+	_, _ = fmt.Fprintf(tw, "Dry run, the invasion is not simulated\n")
+
+	if params.scenario != nil {
+		_, _ = fmt.Fprintf(tw, "Scenario\t%s (sha256 %s)\n", params.scenario.Name, params.scenario.Hash)
+	}
+
+	_, _ = fmt.Fprintf(tw, "Aliens\t%d\n", params.n)
+	_, _ = fmt.Fprintf(tw, "Seed\t%d (%s)\n", seed, seedSource)
+	_, _ = fmt.Fprintf(tw, "Max moves\t%d\n", params.maxMoves)
+	_, _ = fmt.Fprintf(tw, "Strategy\t%s\n", params.strategy)
+	_, _ = fmt.Fprintf(tw, "Collision\t%s\n", params.collision)
+	_, _ = fmt.Fprintf(tw, "Destruction threshold\t%d aliens\n", game.DestructionThreshold)
+	_, _ = fmt.Fprintf(tw, "Runs\t%d\n", params.runs)
+	_, _ = fmt.Fprintf(tw, "Timeout\t%s\n", timeout)
+	_, _ = fmt.Fprintf(tw, "Travel costs\t%t\n", params.travelCosts)
+	_, _ = fmt.Fprintf(tw, "Input format\t%s\n", params.inputFormat)
+	_, _ = fmt.Fprintf(tw, "Output format\t%s\n", params.outputFormat)
+	_, _ = fmt.Fprintf(tw, "Output\t%s\n", output)
+
+	return tw.Flush()
+}
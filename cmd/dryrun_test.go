@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRoot_DryRun makes sure the dry run reports the effective
+// parameters and the map checks, without writing the output
+func TestRoot_DryRun(t *testing.T) {
+	testTable := []struct {
+		name            string
+		lines           []string
+		expectedErr     error
+		expectedReports []string
+	}{
+		{
+			"valid map",
+			[]string{"Foo north=Bar", "Bar south=Foo", "Baz"},
+			nil,
+			[]string{
+				"Isolated cities      1 (Baz)\n",
+				"Issues: 0 errors, 2 warnings\n",
+				"warning: city Baz has no roads\n",
+			},
+		},
+		{
+			"broken map",
+			[]string{"Foo north=Bar", "Bar south="},
+			errMapInvalid,
+			[]string{
+				"Issues: 1 errors, 0 warnings\n",
+				"error: unable to parse line 2",
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "output.json")
+
+			stdout, _, err := executeRootCommand(
+				t,
+				"4",
+				"--map-path", writeTempMap(t, testCase.lines...),
+				"--output-path", outputPath,
+				"--seed", "42",
+				"--max-moves", "50",
+				"--dry-run",
+			)
+
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+				assert.Equal(t, exitCodeError, exitCode(err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Make sure the effective parameters are echoed
+			for _, param := range []string{
+				"Aliens                 4\n",
+				"Seed                   42 (provided)\n",
+				"Max moves              50\n",
+				"Destruction threshold  2 aliens\n",
+				"Output format          json\n",
+				"Output                 " + outputPath + "\n",
+			} {
+				assert.Contains(t, stdout, param)
+			}
+
+			for _, report := range testCase.expectedReports {
+				assert.Contains(t, stdout, report)
+			}
+
+			// Make sure the invasion is not simulated, and nothing is written
+			assert.NotContains(t, stdout, "cities were destroyed")
+			assert.NoFileExists(t, outputPath)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// envPrefix is the prefix of the environment variables that set the flags
+const envPrefix = "ALIEN_INVASION_"
+
+// envHelp documents the environment variables in the command help
+var envHelp = fmt.Sprintf(`Every flag can also be set with its %s<FLAG> environment variable
+(ex. %s for --%s), unless it's set on the command line.`,
+	envPrefix,
+	envName(mapPathFlag),
+	mapPathFlag,
+)
+
+var errInvalidEnvValue = errors.New("invalid environment variable value")
+
+// nonEnvFlags are the flags that can't be set using the environment variables
+var nonEnvFlags = map[string]struct{}{
+	"help":    {},
+	"version": {},
+}
+
+// flagSourceAnnotation is the flag annotation naming where a flag set
+// outside of the command line (the environment or the config file) got its value from
+const flagSourceAnnotation = "alien-invasion_flag_source"
+
+// setFlagSource records where the flag value outside of the command line comes from
+func setFlagSource(cmd *cobra.Command, flagName, source string) {
+	_ = cmd.Flags().SetAnnotation(flagName, flagSourceAnnotation, []string{source})
+}
+
+// flagSource names where the set flag got its value from,
+// which is the command line flag, unless the value comes from elsewhere
+func flagSource(cmd *cobra.Command, flagName string) string {
+	if flag := cmd.Flags().Lookup(flagName); flag != nil {
+		if source, ok := flag.Annotations[flagSourceAnnotation]; ok && len(source) > 0 {
+			return source[0]
+		}
+	}
+
+	return fmt.Sprintf("the --%s flag", flagName)
+}
+
+// envName returns the name of the environment variable that sets the flag
+// (ex. ALIEN_INVASION_MAP_PATH for the map-path flag)
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyEnv sets the flags of the command to the values of their environment variables.
+// The flags set on the command line take precedence over the environment variables,
+// which take precedence over the config file. The positional number of aliens also takes precedence
+func applyEnv(cmd *cobra.Command, args []string) error {
+	var err error
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if _, excluded := nonEnvFlags[flag.Name]; excluded || err != nil || flag.Changed {
+			return
+		}
+
+		if flag.Name == aliensFlag && len(args) > 0 {
+			return
+		}
+
+		name := envName(flag.Name)
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+
+		if setErr := cmd.Flags().Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("%w: %s=%q for the --%s flag, %v", errInvalidEnvValue, name, value, flag.Name, setErr)
+
+			return
+		}
+
+		setFlagSource(cmd, flag.Name, fmt.Sprintf("the %s environment variable", name))
+	})
+
+	return err
+}
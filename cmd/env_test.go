@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// TestEnvName makes sure the environment variable
+// names are derived from the flag names
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "ALIEN_INVASION_MAP_PATH", envName(mapPathFlag))
+	assert.Equal(t, "ALIEN_INVASION_ALIENS", envName(aliensFlag))
+	assert.Equal(t, "ALIEN_INVASION_PER_ALIEN_RAND", envName(perAlienRandFlag))
+}
+
+// TestRoot_Env makes sure the environment variables set the flags,
+// with the flags taking precedence, and the config file coming last
+func TestRoot_Env(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	t.Run("environment over defaults", func(t *testing.T) {
+		// The map path requirement is satisfied by the environment variable
+		t.Setenv("ALIEN_INVASION_MAP_PATH", mapPath)
+		t.Setenv("ALIEN_INVASION_ALIENS", "7")
+		t.Setenv("ALIEN_INVASION_SEED", "42")
+		t.Setenv("ALIEN_INVASION_TRAVEL_COSTS", "true")
+
+		config := printConfig(t)
+
+		assert.Equal(t, mapPath, config[mapPathFlag])
+		assert.Equal(t, 7, config[aliensFlag])
+		assert.Equal(t, 42, config[seedFlag])
+		assert.Equal(t, true, config[travelCostsFlag])
+		assert.Equal(t, game.DefaultMaxMoves, config[maxMovesFlag])
+	})
+
+	t.Run("flags over environment", func(t *testing.T) {
+		t.Setenv("ALIEN_INVASION_MAP_PATH", mapPath)
+		t.Setenv("ALIEN_INVASION_ALIENS", "7")
+		t.Setenv("ALIEN_INVASION_MAX_MOVES", "50")
+
+		config := printConfig(t, "3", "--max-moves", "10")
+
+		assert.Equal(t, 3, config[aliensFlag])
+		assert.Equal(t, 10, config[maxMovesFlag])
+	})
+
+	t.Run("environment over config file", func(t *testing.T) {
+		t.Setenv("ALIEN_INVASION_MAX_MOVES", "50")
+		t.Setenv("ALIEN_INVASION_CONFIG", writeTempConfig(
+			t,
+			"scenario.yaml",
+			"map-path: "+mapPath+"\naliens: 2\nmax-moves: 20\nruns: 4\n",
+		))
+
+		config := printConfig(t)
+
+		assert.Equal(t, 50, config[maxMovesFlag])
+		assert.Equal(t, 4, config[runsFlag])
+	})
+
+	t.Run("subcommand flags", func(t *testing.T) {
+		t.Setenv("ALIEN_INVASION_MAP_PATH", mapPath)
+
+		stdout, _, err := executeRootCommand(t, "validate")
+
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "Cities: 2\n")
+	})
+}
+
+// TestRoot_Env_Invalid makes sure invalid environment variable
+// values are rejected, naming the environment variable
+func TestRoot_Env_Invalid(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	testTable := []struct {
+		name          string
+		variable      string
+		value         string
+		expectedCause string
+	}{
+		{
+			"invalid number",
+			"ALIEN_INVASION_MAX_MOVES",
+			"lots",
+			`ALIEN_INVASION_MAX_MOVES="lots" for the --max-moves flag`,
+		},
+		{
+			"invalid boolean",
+			"ALIEN_INVASION_QUIET",
+			"maybe",
+			`ALIEN_INVASION_QUIET="maybe" for the --quiet flag`,
+		},
+		{
+			"invalid format",
+			"ALIEN_INVASION_OUTPUT_FORMAT",
+			"yaml",
+			"invalid format provided: yaml",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Setenv(testCase.variable, testCase.value)
+
+			_, _, err := executeRootCommand(t, "3", "--map-path", mapPath)
+
+			assert.ErrorIs(t, err, errInvalidEnvValue)
+			assert.ErrorContains(t, err, testCase.expectedCause)
+		})
+	}
+}
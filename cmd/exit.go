@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Define the process exit codes for the invasion outcomes
+const (
+	exitCodeSurvived     = 0 // the invasion completed, and at least one city survived
+	exitCodeError        = 1 // the program failed, due to a usage or I/O error
+	exitCodeAllDestroyed = 2 // the invasion completed, and every city was destroyed
+	exitCodeInterrupted  = 3 // the invasion was interrupted by a signal or the timeout
+	exitCodeForced       = 4 // the exit was forced by a second signal, without waiting for the invasion to stop
+)
+
+// exitCodesHelp documents the exit codes in the command help
+var exitCodesHelp = fmt.Sprintf(`Exit codes:
+  %d  the invasion completed, and at least one city survived
+  %d  the program failed (invalid usage, unreadable map, unwritable output...)
+  %d  the invasion completed, and every city was destroyed
+  %d  the invasion was interrupted by a termination signal or the timeout
+  %d  the exit was forced by a second termination signal, or the force exit duration`,
+	exitCodeSurvived,
+	exitCodeError,
+	exitCodeAllDestroyed,
+	exitCodeInterrupted,
+	exitCodeForced,
+)
+
+// outcomeError is an invasion outcome that is reported with a distinct exit code.
+// The outcome is not a failure, so the output is still written
+type outcomeError struct {
+	code    int
+	message string
+}
+
+func (e *outcomeError) Error() string {
+	return e.message
+}
+
+var (
+	errAllDestroyed = &outcomeError{
+		code:    exitCodeAllDestroyed,
+		message: "every city was destroyed",
+	}
+	errInterrupted = &outcomeError{
+		code:    exitCodeInterrupted,
+		message: "the invasion was interrupted",
+	}
+	errForcedExit = &outcomeError{
+		code:    exitCodeForced,
+		message: "the exit was forced",
+	}
+)
+
+// exitCode returns the process exit code for the command error
+func exitCode(err error) int {
+	if err == nil {
+		return exitCodeSurvived
+	}
+
+	var outcome *outcomeError
+	if errors.As(err, &outcome) {
+		return outcome.code
+	}
+
+	return exitCodeError
+}
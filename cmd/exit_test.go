@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExitCode makes sure each invasion outcome
+// is mapped to its own exit code
+func TestExitCode(t *testing.T) {
+	testTable := []struct {
+		name         string
+		args         []string
+		expectedCode int
+	}{
+		{
+			"cities survived",
+			[]string{"1", "--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo")},
+			exitCodeSurvived,
+		},
+		{
+			// Both aliens land in the only city, and destroy it
+			"every city destroyed",
+			[]string{"2", "--map-path", writeTempMap(t, "Foo")},
+			exitCodeAllDestroyed,
+		},
+		{
+			"timeout",
+			[]string{
+				"10",
+				"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+				"--timeout", "1ns",
+			},
+			exitCodeInterrupted,
+		},
+		{
+			"missing map",
+			[]string{"1", "--map-path", filepath.Join(t.TempDir(), "missing.txt")},
+			exitCodeError,
+		},
+		{
+			"invalid usage",
+			[]string{"abc", "--map-path", writeTempMap(t, "Foo")},
+			exitCodeError,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			_, stderr, err := executeRootCommand(t, testCase.args...)
+
+			assert.Equal(t, testCase.expectedCode, exitCode(err))
+
+			// The outcomes are not reported as errors
+			if testCase.expectedCode != exitCodeError {
+				assert.NotContains(t, stderr, "Error:")
+			}
+		})
+	}
+}
+
+// TestExitCode_Wrapped makes sure wrapped
+// outcomes keep their exit code
+func TestExitCode_Wrapped(t *testing.T) {
+	assert.Equal(t, exitCodeInterrupted, exitCode(fmt.Errorf("run failed, %w", errInterrupted)))
+	assert.Equal(t, exitCodeError, exitCode(errors.New("no space left on device")))
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+var errInvalidFormat = errors.New("invalid format provided")
+
+// formatValue is a format flag value,
+// which only accepts the supported formats
+type formatValue struct {
+	format    *stream.Format
+	supported []stream.Format
+}
+
+// newFormatValue creates a format flag value that
+// stores the format, if it's one of the supported formats
+func newFormatValue(format *stream.Format, supported []stream.Format) *formatValue {
+	return &formatValue{
+		format:    format,
+		supported: supported,
+	}
+}
+
+func (f *formatValue) String() string {
+	return string(*f.format)
+}
+
+func (f *formatValue) Set(value string) error {
+	format := stream.Format(value)
+
+	if !isSupportedFormat(format, f.supported) {
+		return fmt.Errorf(
+			"%w: %s, supported formats are %s",
+			errInvalidFormat,
+			value,
+			joinFormats(f.supported),
+		)
+	}
+
+	*f.format = format
+
+	return nil
+}
+
+func (f *formatValue) Type() string {
+	return "string"
+}
+
+// isSupportedFormat checks if the format is one of the supported formats
+func isSupportedFormat(format stream.Format, supported []stream.Format) bool {
+	for _, supportedFormat := range supported {
+		if format == supportedFormat {
+			return true
+		}
+	}
+
+	return false
+}
+
+// joinFormats joins the formats into a human-readable list
+func joinFormats(formats []stream.Format) string {
+	names := make([]string, len(formats))
+
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// Define the present flags for the generate command
+const (
+	citiesFlag    = "cities"
+	densityFlag   = "density"
+	topologyFlag  = "topology"
+	connectedFlag = "connected"
+)
+
+// Define the supported generated map topologies
+const (
+	topologyRandom = "random"
+	topologyGrid   = "grid"
+)
+
+var (
+	errInvalidCityCount = errors.New("number of cities must be a positive number")
+	errInvalidDensity   = errors.New("density must be between 0 and 1")
+	errInvalidTopology  = errors.New("invalid map topology provided")
+)
+
+// generateParams defines the storage for
+// the generate command arguments
+type generateParams struct {
+	cities     int
+	density    float64
+	seed       int64
+	topology   string
+	connected  bool
+	outputPath string
+}
+
+// newGenerateCommand creates the command that generates random maps
+func newGenerateCommand() *cobra.Command {
+	generateParams := &generateParams{}
+
+	generateCmd := &cobra.Command{
+		Use:          "generate",
+		Short:        "Generates a random map of the Earth",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return generateParams.validate()
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runGenerate(cmd, generateParams)
+		},
+	}
+
+	generateCmd.Flags().IntVar(
+		&generateParams.cities,
+		citiesFlag,
+		100,
+		"The number of cities on the generated map",
+	)
+
+	generateCmd.Flags().Float64Var(
+		&generateParams.density,
+		densityFlag,
+		0.5,
+		"The probability of each road between adjacent cities being kept, for the random topology",
+	)
+
+	generateCmd.Flags().Int64Var(
+		&generateParams.seed,
+		seedFlag,
+		0,
+		"The seed for the map generation. If omitted, a random seed is generated",
+	)
+
+	generateCmd.Flags().StringVar(
+		&generateParams.topology,
+		topologyFlag,
+		topologyRandom,
+		fmt.Sprintf(
+			"The topology of the generated map (%s or %s)",
+			topologyRandom,
+			topologyGrid,
+		),
+	)
+
+	generateCmd.Flags().BoolVar(
+		&generateParams.connected,
+		connectedFlag,
+		false,
+		"Guarantee that every city can be reached from every other city",
+	)
+
+	generateCmd.Flags().StringVar(
+		&generateParams.outputPath,
+		outputPathFlag,
+		"",
+		"The path to output the generated map. If omitted, the output is directed to the console",
+	)
+
+	return generateCmd
+}
+
+// validate makes sure the generate command arguments are valid
+func (g *generateParams) validate() error {
+	if g.cities <= 0 {
+		return fmt.Errorf("%w: %d", errInvalidCityCount, g.cities)
+	}
+
+	if g.density < 0 || g.density > 1 {
+		return fmt.Errorf("%w: %v", errInvalidDensity, g.density)
+	}
+
+	if g.topology != topologyRandom && g.topology != topologyGrid {
+		return fmt.Errorf("%w: %s", errInvalidTopology, g.topology)
+	}
+
+	return nil
+}
+
+// runGenerate runs the generate command
+func runGenerate(cmd *cobra.Command, generateParams *generateParams) error {
+	// Pick the seed for the generation, and
+	// report it so the map can be regenerated
+	seed := generateParams.seed
+	if !cmd.Flags().Changed(seedFlag) {
+		seed = time.Now().UnixNano()
+
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Using random seed %d\n", seed)
+	}
+
+	// A grid is a lattice map with every road kept
+	density := generateParams.density
+	if generateParams.topology == topologyGrid {
+		density = 1
+	}
+
+	earthMap := game.GenerateRandomMap(
+		generateParams.cities,
+		density,
+		seed,
+		generateParams.connected,
+	)
+
+	// Set up the output writer
+	writer, err := getDestinationWriter(cmd, generateParams.outputPath, stream.FileWriterOptions{})
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(writer, earthMap.WriteOutput)
+}
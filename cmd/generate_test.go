@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// readMapFile initializes a new Earth map from the given map file
+func readMapFile(t *testing.T, mapPath string) *game.EarthMap {
+	t.Helper()
+
+	reader, err := stream.NewFileReader(mapPath)
+	if err != nil {
+		t.Fatalf("unable to create a file reader, %v", err)
+	}
+
+	defer reader.Close()
+
+	earthMap := game.NewEarthMap(hclog.NewNullLogger(), game.WithStrictParsing())
+
+	if err := earthMap.InitMap(reader); err != nil {
+		t.Fatalf("unable to initialize the map, %v", err)
+	}
+
+	return earthMap
+}
+
+// TestGenerate_Map makes sure the generated maps
+// can be read back, and are valid
+func TestGenerate_Map(t *testing.T) {
+	testTable := []struct {
+		name string
+		args []string
+
+		expectedComponents int
+	}{
+		{
+			"connected random map",
+			[]string{"--cities", "200", "--density", "0.3", "--connected"},
+			1,
+		},
+		{
+			"grid map",
+			[]string{"--cities", "100", "--topology", "grid"},
+			1,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "world.txt")
+
+			args := append([]string{"generate", "--seed", "42", "--output-path", outputPath}, testCase.args...)
+
+			_, _, err := executeRootCommand(t, args...)
+			if err != nil {
+				t.Fatalf("unable to generate the map, %v", err)
+			}
+
+			report := readMapFile(t, outputPath).Validate()
+
+			assert.Equal(t, testCase.expectedComponents, report.Components)
+			assert.False(t, report.HasErrors())
+		})
+	}
+}
+
+// TestGenerate_CityCount makes sure the generated map
+// contains the requested number of cities
+func TestGenerate_CityCount(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "world.txt")
+
+	_, stderr, err := executeRootCommand(
+		t,
+		"generate",
+		"--cities", "37",
+		"--connected",
+		"--output-path", outputPath,
+	)
+	if err != nil {
+		t.Fatalf("unable to generate the map, %v", err)
+	}
+
+	assert.Contains(t, stderr, "Using random seed")
+	assert.Len(t, readMapFile(t, outputPath).Cities(), 37)
+}
+
+// TestGenerate_InvalidParams makes sure invalid
+// generation parameters are rejected
+func TestGenerate_InvalidParams(t *testing.T) {
+	testTable := []struct {
+		name string
+		args []string
+
+		expectedErr error
+	}{
+		{
+			"invalid city count",
+			[]string{"--cities", "0"},
+			errInvalidCityCount,
+		},
+		{
+			"invalid density",
+			[]string{"--density", "1.5"},
+			errInvalidDensity,
+		},
+		{
+			"invalid topology",
+			[]string{"--topology", "ring"},
+			errInvalidTopology,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			_, _, err := executeRootCommand(t, append([]string{"generate"}, testCase.args...)...)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
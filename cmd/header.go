@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+var errInvalidMapHeader = errors.New("invalid map header value")
+
+// headerFlags are the flags that can be set in the map header,
+// keyed by the map header keys
+var headerFlags = map[string]string{
+	"aliens": aliensFlag,
+	"seed":   seedFlag,
+}
+
+// applyMapHeader sets the flags to the values in the metadata header of the map file
+// (ex. "# aliens: 10"). The header values are the defaults of the map, so the flags set on
+// the command line, with the environment variables or in the config file take precedence.
+// The map header can't be read ahead from the standard input, and isn't downloaded twice
+// from the map URL, so it only applies to map files
+func applyMapHeader(cmd *cobra.Command, args []string, mapPath string) error {
+	if mapPath == "" || mapPath == stdinPath || stream.IsHTTPURL(mapPath) {
+		return nil
+	}
+
+	mapFile, err := stream.OpenMapFile(mapPath)
+	if err != nil {
+		// The missing map file is reported when the map is read
+		return nil
+	}
+
+	defer func() {
+		_ = mapFile.Close()
+	}()
+
+	header, err := stream.ReadHeader(mapFile)
+	if err != nil {
+		return err
+	}
+
+	for key, flagName := range headerFlags {
+		value, ok := header[key]
+		if !ok {
+			continue
+		}
+
+		flag := cmd.Flags().Lookup(flagName)
+
+		if flag.Changed || (flag.Name == aliensFlag && len(args) > 0) {
+			continue
+		}
+
+		if err := cmd.Flags().Set(flag.Name, value); err != nil {
+			return fmt.Errorf("%w: %s, %v", errInvalidMapHeader, key, err)
+		}
+	}
+
+	return nil
+}
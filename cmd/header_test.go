@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRoot_MapHeader makes sure the map header values are used as
+// the defaults, with the flags and the config file taking precedence
+func TestRoot_MapHeader(t *testing.T) {
+	mapPath := writeTempMap(t, "# aliens: 10", "# seed: 42", "Foo north=Bar", "Bar south=Foo")
+
+	t.Run("header over defaults", func(t *testing.T) {
+		config := printConfig(t, "--map-path", mapPath)
+
+		assert.Equal(t, 10, config[aliensFlag])
+		assert.Equal(t, 42, config[seedFlag])
+	})
+
+	t.Run("flags over header", func(t *testing.T) {
+		config := printConfig(t, "3", "--map-path", mapPath, "--seed", "7")
+
+		assert.Equal(t, 3, config[aliensFlag])
+		assert.Equal(t, 7, config[seedFlag])
+	})
+
+	t.Run("config file over header", func(t *testing.T) {
+		config := printConfig(t, "--config", writeTempConfig(
+			t,
+			"scenario.yaml",
+			"map-path: "+mapPath+"\naliens: 4\n",
+		))
+
+		assert.Equal(t, 4, config[aliensFlag])
+		assert.Equal(t, 42, config[seedFlag])
+	})
+
+	t.Run("header comments are not cities", func(t *testing.T) {
+		stdout, _, err := executeRootCommand(t, "--map-path", mapPath, "--dry-run")
+
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "42 (provided)")
+	})
+}
+
+// TestRoot_MapHeader_Invalid makes sure invalid
+// map header values are rejected
+func TestRoot_MapHeader_Invalid(t *testing.T) {
+	_, _, err := executeRootCommand(t, "--map-path", writeTempMap(t, "# aliens: many", "Foo north=Bar"))
+
+	assert.ErrorIs(t, err, errInvalidMapHeader)
+	assert.ErrorContains(t, err, "aliens")
+}
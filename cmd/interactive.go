@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// interactivePrompt is printed when the session waits for a command
+const interactivePrompt = "> "
+
+// interactiveHelp lists the commands of the interactive session
+const interactiveHelp = `Commands:
+  step [n]     advance the invasion by n ticks (1 if omitted)
+  show <city>  print the city roads and invaders
+  map          print the map of the cities that are still standing
+  run          finish the invasion, and write the output
+  quit         stop the invasion, and write the output
+  help         print this list of commands
+`
+
+// interactiveSession steps through the invasion with the commands
+// read from the input, and prints the command results to the output
+type interactiveSession struct {
+	input    io.Reader
+	output   io.Writer
+	earthMap *game.EarthMap
+}
+
+// newInteractiveSession creates an interactive session on the Earth map
+func newInteractiveSession(input io.Reader, output io.Writer, earthMap *game.EarthMap) *interactiveSession {
+	return &interactiveSession{
+		input:    input,
+		output:   output,
+		earthMap: earthMap,
+	}
+}
+
+// run places the aliens, and executes the commands until the session is over.
+// The session is over once the invasion is run to completion, or stopped
+// with the quit command, the end of the input or the context cancellation.
+// Returns the outcome of the invasion, which is interrupted if aliens are still wandering
+func (s *interactiveSession) run(ctx context.Context, numAliens int) (game.SimulationResult, error) {
+	stepper, err := s.earthMap.NewStepper(numAliens)
+	if err != nil {
+		return game.SimulationResult{}, err
+	}
+
+	s.printf("Placed the aliens, type help for the list of commands\n")
+
+	lineCh := readLines(ctx, s.input)
+
+	for {
+		s.printf(interactivePrompt)
+
+		select {
+		case <-ctx.Done():
+			s.printf("\n")
+
+			return stepper.Conclude(), nil
+		case line, ok := <-lineCh:
+			if !ok {
+				// The input is exhausted, which is the same as quitting
+				s.printf("\n")
+
+				return stepper.Conclude(), nil
+			}
+
+			if s.execute(ctx, stepper, line) {
+				return stepper.Conclude(), nil
+			}
+		}
+	}
+}
+
+// execute executes the single command line. The commands advancing the invasion
+// stop early once the context is cancelled, which then ends the session.
+// Returns true if the command ends the session
+func (s *interactiveSession) execute(ctx context.Context, stepper *game.Stepper, line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+
+	command, args := fields[0], fields[1:]
+
+	switch command {
+	case "step":
+		s.step(ctx, stepper, args)
+	case "show":
+		s.show(args)
+	case "map":
+		if err := stepper.WriteMap(stream.NewConsoleWriterTo(s.output)); err != nil {
+			s.printf("Unable to print the map, %v\n", err)
+		}
+	case "run":
+		for ctx.Err() == nil && stepper.Step() {
+		}
+
+		s.printTick(stepper)
+
+		return true
+	case "quit":
+		return true
+	case "help":
+		s.printf(interactiveHelp)
+	default:
+		s.printf("Unknown command %q, type help for the list of commands\n", command)
+	}
+
+	return false
+}
+
+// step advances the invasion by the number of ticks in the arguments,
+// or until the context is cancelled
+func (s *interactiveSession) step(ctx context.Context, stepper *game.Stepper, args []string) {
+	ticks := 1
+
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed <= 0 {
+			s.printf("Invalid number of ticks %q, expected a positive integer\n", args[0])
+
+			return
+		}
+
+		ticks = parsed
+	}
+
+	for i := 0; i < ticks && ctx.Err() == nil && stepper.Step(); i++ {
+	}
+
+	s.printTick(stepper)
+}
+
+// show prints the roads and the invaders of the city in the arguments
+func (s *interactiveSession) show(args []string) {
+	if len(args) == 0 {
+		s.printf("Missing city name, usage: show <city>\n")
+
+		return
+	}
+
+	// City names can't contain spaces, so any extra argument is ignored
+	view, err := s.earthMap.City(args[0])
+	if err != nil {
+		s.printf("Unable to show the city, %v\n", err)
+
+		return
+	}
+
+	invaders := "none"
+
+	if len(view.Invaders) > 0 {
+		ids := make([]string, len(view.Invaders))
+		for i, id := range view.Invaders {
+			ids[i] = strconv.Itoa(id)
+		}
+
+		invaders = strings.Join(ids, ", ")
+	}
+
+	s.printf("%s\n", strings.Join(append([]string{view.Name}, view.Roads...), " "))
+	s.printf("  Invaders: %s\n", invaders)
+	s.printf("  Destroyed: %t\n", view.Destroyed)
+}
+
+// printTick prints the state of the invasion at the current tick
+func (s *interactiveSession) printTick(stepper *game.Stepper) {
+	progress := s.earthMap.Progress()
+
+	s.printf(
+		"Tick %d | Aliens wandering: %d | Cities destroyed: %d\n",
+		stepper.Tick(),
+		progress.AliensRemaining,
+		progress.CitiesDestroyed,
+	)
+
+	if stepper.Done() {
+		s.printf("No alien is wandering anymore, the invasion is over\n")
+	}
+}
+
+// printf prints the formatted message to the session output
+func (s *interactiveSession) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(s.output, format, args...)
+}
+
+// readLines reads the input lines in a separate routine, so waiting
+// for a command can be cancelled. The routine reading the input
+// is left blocked on the read if the context is cancelled in the meantime
+func readLines(ctx context.Context, input io.Reader) <-chan string {
+	lineCh := make(chan string)
+
+	go func() {
+		defer close(lineCh)
+
+		scanner := bufio.NewScanner(input)
+
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+				return
+			case lineCh <- scanner.Text():
+			}
+		}
+	}()
+
+	return lineCh
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// newInteractiveMap creates the Earth map of the given city lines
+func newInteractiveMap(t *testing.T, maxMoves int, lines ...string) *game.EarthMap {
+	t.Helper()
+
+	reader, err := stream.NewFormatReader(strings.NewReader(strings.Join(lines, "\n")), stream.FormatText)
+	if err != nil {
+		t.Fatalf("unable to create the map reader, %v", err)
+	}
+
+	earthMap := game.NewEarthMap(hclog.NewNullLogger(), game.WithMaxMoves(maxMoves))
+	if err := earthMap.InitMap(reader); err != nil {
+		t.Fatalf("unable to initialize the map, %v", err)
+	}
+
+	return earthMap
+}
+
+// TestInteractiveSession runs scripted sessions, and makes
+// sure the command output and the outcome are correct
+func TestInteractiveSession(t *testing.T) {
+	t.Parallel()
+
+	const placed = "Placed the aliens, type help for the list of commands\n> "
+
+	var (
+		pair = []string{"Foo north=Bar", "Bar south=Foo"}
+		over = "No alien is wandering anymore, the invasion is over\n"
+	)
+
+	testTable := []struct {
+		name                string
+		lines               []string
+		numAliens           int
+		script              string
+		expectedOutput      string
+		expectedInterrupted bool
+	}{
+		{
+			"step to completion",
+			pair,
+			1,
+			"step\nstep 5\nstep\nrun\n",
+			placed +
+				"Tick 1 | Aliens wandering: 1 | Cities destroyed: 0\n> " +
+				"Tick 3 | Aliens wandering: 0 | Cities destroyed: 0\n" + over + "> " +
+				"Tick 3 | Aliens wandering: 0 | Cities destroyed: 0\n" + over + "> " +
+				"Tick 3 | Aliens wandering: 0 | Cities destroyed: 0\n" + over,
+			false,
+		},
+		{
+			"run at once",
+			pair,
+			1,
+			"run\n",
+			placed + "Tick 3 | Aliens wandering: 0 | Cities destroyed: 0\n" + over,
+			false,
+		},
+		{
+			"quit while running",
+			pair,
+			1,
+			"step\nquit\n",
+			placed + "Tick 1 | Aliens wandering: 1 | Cities destroyed: 0\n> ",
+			true,
+		},
+		{
+			"end of input",
+			pair,
+			1,
+			"step",
+			placed + "Tick 1 | Aliens wandering: 1 | Cities destroyed: 0\n> \n",
+			true,
+		},
+		{
+			"map",
+			pair,
+			1,
+			"map\nquit\n",
+			placed + "Bar south=Foo\nFoo north=Bar\n> ",
+			true,
+		},
+		{
+			"invalid commands",
+			pair,
+			1,
+			"\nbogus\nstep x\nstep 0\nshow\nshow Qux\nhelp\nquit\n",
+			placed + "> " +
+				"Unknown command \"bogus\", type help for the list of commands\n> " +
+				"Invalid number of ticks \"x\", expected a positive integer\n> " +
+				"Invalid number of ticks \"0\", expected a positive integer\n> " +
+				"Missing city name, usage: show <city>\n> " +
+				"Unable to show the city, city not found on the map: Qux\n> " +
+				interactiveHelp + "> ",
+			true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				output   bytes.Buffer
+				earthMap = newInteractiveMap(t, 3, testCase.lines...)
+			)
+
+			session := newInteractiveSession(strings.NewReader(testCase.script), &output, earthMap)
+
+			result, err := session.run(context.Background(), testCase.numAliens)
+			if err != nil {
+				t.Fatalf("unable to run the session, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedInterrupted, result.Interrupted)
+			assert.Equal(t, testCase.expectedOutput, output.String())
+		})
+	}
+}
+
+// TestInteractiveSession_Show makes sure the destroyed city is shown
+// with its invaders, and left out of the standing map
+func TestInteractiveSession_Show(t *testing.T) {
+	t.Parallel()
+
+	var (
+		output   bytes.Buffer
+		earthMap = newInteractiveMap(t, 3, "Foo")
+	)
+
+	// Both aliens land in the only city, and destroy it
+	session := newInteractiveSession(strings.NewReader("show Foo\nmap\nrun\n"), &output, earthMap)
+
+	result, err := session.run(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unable to run the session, %v", err)
+	}
+
+	assert.Equal(
+		t,
+		"Placed the aliens, type help for the list of commands\n> "+
+			"Foo\n  Invaders: 0, 1\n  Destroyed: true\n> "+
+			"> "+
+			"Tick 0 | Aliens wandering: 0 | Cities destroyed: 1\n"+
+			"No alien is wandering anymore, the invasion is over\n",
+		output.String(),
+	)
+	assert.Equal(t, 1, result.CitiesDestroyed)
+}
+
+// TestInteractiveSession_Cancelled makes sure the session waiting
+// for a command is stopped by the context cancellation
+func TestInteractiveSession_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	// The pipe is never written to, so the session blocks on the prompt
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	cancelFn()
+
+	session := newInteractiveSession(reader, io.Discard, newInteractiveMap(t, 3, "Foo north=Bar", "Bar south=Foo"))
+
+	result, err := session.run(ctx, 1)
+	if err != nil {
+		t.Fatalf("unable to run the session, %v", err)
+	}
+
+	assert.True(t, result.Interrupted)
+}
+
+// TestInteractiveSession_CancelledCommand makes sure the commands
+// advancing the invasion stop once the context is cancelled
+func TestInteractiveSession_CancelledCommand(t *testing.T) {
+	t.Parallel()
+
+	for _, line := range []string{"step 5", "run"} {
+		line := line
+
+		t.Run(line, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			ctx, cancelFn := context.WithCancel(context.Background())
+			cancelFn()
+
+			earthMap := newInteractiveMap(t, 3, "Foo north=Bar", "Bar south=Foo")
+			session := newInteractiveSession(strings.NewReader(""), &output, earthMap)
+
+			stepper, err := earthMap.NewStepper(1)
+			if err != nil {
+				t.Fatalf("unable to create the stepper, %v", err)
+			}
+
+			session.execute(ctx, stepper, line)
+
+			assert.Equal(t, "Tick 0 | Aliens wandering: 1 | Cities destroyed: 0\n", output.String())
+			assert.True(t, stepper.Conclude().Interrupted)
+		})
+	}
+}
+
+// TestRoot_Interactive makes sure the interactive session
+// takes the commands from the standard input, and writes the output
+func TestRoot_Interactive(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	// executeInteractive executes the root command with the given command script
+	executeInteractive := func(script string, args ...string) (string, error) {
+		var (
+			stdout bytes.Buffer
+
+			rootCmd = NewRootCommand().baseCmd
+		)
+
+		rootCmd.SetIn(strings.NewReader(script))
+		rootCmd.SetOut(&stdout)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs(args)
+
+		err := rootCmd.Execute()
+
+		return stdout.String(), err
+	}
+
+	t.Run("run to completion", func(t *testing.T) {
+		stdout, err := executeInteractive(
+			"step\nrun\n",
+			"1", "--map-path", mapPath, "--max-moves", "2", "--interactive",
+		)
+
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "Tick 1 | Aliens wandering: 1 | Cities destroyed: 0\n")
+		assert.Contains(t, stdout, "Tick 2 | Aliens wandering: 0 | Cities destroyed: 0\n")
+		assert.True(t, strings.HasSuffix(stdout, "A total of 0 cities were destroyed\nBar south=Foo\nFoo north=Bar\n"))
+	})
+
+	t.Run("quit while running", func(t *testing.T) {
+		stdout, err := executeInteractive(
+			"quit\n",
+			"1", "--map-path", mapPath, "--interactive",
+		)
+
+		assert.ErrorIs(t, err, errInterrupted)
+		assert.Contains(t, stdout, "Foo north=Bar\n")
+	})
+
+	t.Run("multiple runs", func(t *testing.T) {
+		_, err := executeInteractive("", "1", "--map-path", mapPath, "--interactive", "--runs", "2")
+
+		assert.ErrorIs(t, err, errInteractiveRuns)
+	})
+
+	t.Run("map from the standard input", func(t *testing.T) {
+		_, err := executeInteractive("", "1", "--map-path", stdinPath, "--interactive", "--runs", "1")
+
+		assert.ErrorIs(t, err, errInteractiveStdin)
+	})
+}
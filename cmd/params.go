@@ -1,10 +1,70 @@
 package cmd
 
+import (
+	"time"
+
+	"github.com/zivkovicmilos/alien-invasion/scenario"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
 // Define the present flags for the base program
 const (
 	mapPathFlag    = "map-path"
 	outputPathFlag = "output-path"
 	logLevelFlag   = "log-level"
+	logOutputFlag  = "log-output"
+	logFileFlag    = "log-file"
+	logFormatFlag  = "log-format"
+	jsonLogFlag    = "json-log"
+	quietFlag      = "quiet"
+	announceFlag   = "announce"
+
+	listSurvivorsFlag = "list-survivors"
+	aliensFlag        = "aliens"
+	appendOutputFlag  = "append-output"
+	outputFormatFlag  = "output-format"
+	formatFlag        = "format"
+	inputFormatFlag   = "input-format"
+	maxCitiesFlag     = "max-cities"
+	maxLineSizeFlag   = "max-line-size"
+	maxAliensFlag     = "max-aliens"
+	maxMovesFlag      = "max-moves"
+	runsFlag          = "runs"
+	parallelFlag      = "parallel"
+	seedFlag          = "seed"
+	timeoutFlag       = "timeout"
+	travelCostsFlag   = "travel-costs"
+	perAlienRandFlag  = "per-alien-rand"
+	interactiveFlag   = "interactive"
+	tuiFlag           = "tui"
+	dryRunFlag        = "dry-run"
+	configFlag        = "config"
+	printConfigFlag   = "print-config"
+	logAlsoStderrFlag = "log-also-stderr"
+	forceExitFlag     = "force-exit-after"
+	strategyFlag      = "strategy"
+	scenarioFlag      = "scenario"
+
+	reportDestructionsFlag = "report-destructions"
+	collisionFlag          = "collision"
+	builtinMapFlag         = "builtin-map"
+	noClobberFlag          = "no-clobber"
+	checksumFileFlag       = "checksum-file"
+)
+
+// Define the special log output destinations
+const (
+	logOutputStdout = "stdout"
+	logOutputStderr = "stderr"
+)
+
+// stdinPath is the map path that reads the map from the standard input
+const stdinPath = "-"
+
+// Define the supported log formats
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
 )
 
 var (
@@ -18,9 +78,49 @@ type rootParams struct {
 	mapPath    string
 	outputPath string
 	logLevel   string
+	logOutput  string
+	logFile    string
+	logFormat  string
+	jsonLog    bool
+	quiet      bool
+	announce   bool
+
+	listSurvivors bool
+	aliens        int
+	appendOutput  bool
+	outputFormat  stream.Format
+	format        stream.Format
+	inputFormat   stream.Format
+	maxCities     int
+	maxLineSize   int
+	maxAliens     int
+	maxMoves      int
+	runs          int
+	parallel      int
+	seed          int64
+	timeout       time.Duration
+	travelCosts   bool
+	perAlienRand  bool
+	interactive   bool
+	tui           bool
+	dryRun        bool
+	configPath    string
+	printConfig   bool
+	logAlsoStderr bool
+	forceExit     time.Duration
+	strategy      string
+	scenarioPath  string
+	scenario      *scenario.Scenario // the loaded scenario, if any
+
+	reportDestructions bool
+	collision          string
+	builtinMap         string
+	noClobber          bool
+	checksumFile       string
 }
 
-// getRequiredFlags returns the required flags
+// getRequiredFlags returns the required flags. The map path
+// is filled in with the standard input when the map is piped in
 func (r *rootParams) getRequiredFlags() []string {
 	return []string{
 		mapPathFlag,
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// progressInterval is the interval between the progress line updates
+const progressInterval = time.Second
+
+// progressRenderer renders the progress of the running simulation
+// as a single line, which is rewritten in place on each update
+type progressRenderer struct {
+	output   io.Writer            // the terminal the progress is rendered to
+	progress func() game.Progress // the source of the simulation progress
+	now      func() time.Time     // the clock the elapsed time is measured with
+
+	start     time.Time // the start of the simulation
+	lineWidth int       // the width of the last rendered line
+}
+
+// newProgressRenderer creates a new progress renderer, with the elapsed time
+// measured from the moment of creation
+func newProgressRenderer(
+	output io.Writer,
+	progress func() game.Progress,
+	now func() time.Time,
+) *progressRenderer {
+	return &progressRenderer{
+		output:   output,
+		progress: progress,
+		now:      now,
+		start:    now(),
+	}
+}
+
+// render rewrites the progress line with the current progress
+func (p *progressRenderer) render() {
+	progress := p.progress()
+
+	line := fmt.Sprintf(
+		"Elapsed: %s | Aliens remaining: %d | Cities destroyed: %d",
+		p.now().Sub(p.start).Truncate(time.Second),
+		progress.AliensRemaining,
+		progress.CitiesDestroyed,
+	)
+
+	// Pad the line, so the leftovers of a longer previous line are erased
+	padding := ""
+	if p.lineWidth > len(line) {
+		padding = strings.Repeat(" ", p.lineWidth-len(line))
+	}
+
+	p.lineWidth = len(line)
+
+	_, _ = fmt.Fprintf(p.output, "\r%s%s", line, padding)
+}
+
+// finish renders the final progress, and moves past the progress line
+func (p *progressRenderer) finish() {
+	p.render()
+
+	_, _ = fmt.Fprintln(p.output)
+}
+
+// progressView is a live view of the simulation progress on the terminal
+type progressView interface {
+	// render updates the view with the current progress
+	render()
+
+	// finish renders the final progress, once the simulation completes
+	finish()
+}
+
+// runProgress renders the progress on every tick, until the done channel is closed
+func runProgress(view progressView, ticks <-chan time.Time, doneCh <-chan struct{}) {
+	for {
+		select {
+		case <-doneCh:
+			view.finish()
+
+			return
+		case <-ticks:
+			view.render()
+		}
+	}
+}
+
+// isProgressEnabled checks if the progress should be displayed on the given output.
+// The progress is only displayed on a terminal, and never alongside
+// quiet or machine-readable (JSON) logs
+func isProgressEnabled(output io.Writer) bool {
+	if params.quiet || params.logFormat == logFormatJSON {
+		return false
+	}
+
+	return isTerminal(output)
+}
+
+// isTerminal checks if the output is a terminal
+func isTerminal(output io.Writer) bool {
+	file, ok := output.(*os.File)
+	if !ok {
+		return false
+	}
+
+	return isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// fakeClock is a clock that only moves when advanced
+type fakeClock struct {
+	current time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.current
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.current = c.current.Add(d)
+}
+
+// TestProgressRenderer_Render makes sure the progress line
+// is rewritten in place with the current counters
+func TestProgressRenderer_Render(t *testing.T) {
+	var (
+		output   bytes.Buffer
+		clock    = &fakeClock{current: time.Date(2022, 10, 29, 21, 58, 14, 0, time.UTC)}
+		progress = game.Progress{AliensRemaining: 1000, CitiesDestroyed: 0}
+	)
+
+	renderer := newProgressRenderer(&output, func() game.Progress {
+		return progress
+	}, clock.now)
+
+	renderer.render()
+
+	clock.advance(65*time.Second + 500*time.Millisecond)
+	progress = game.Progress{AliensRemaining: 10, CitiesDestroyed: 12}
+
+	renderer.render()
+
+	// The longer previous line is erased with padding
+	clock.advance(time.Second)
+	progress = game.Progress{AliensRemaining: 0, CitiesDestroyed: 12}
+
+	renderer.finish()
+
+	assert.Equal(
+		t,
+		"\rElapsed: 0s | Aliens remaining: 1000 | Cities destroyed: 0"+
+			"\rElapsed: 1m5s | Aliens remaining: 10 | Cities destroyed: 12"+
+			"\rElapsed: 1m6s | Aliens remaining: 0 | Cities destroyed: 12 "+
+			"\n",
+		output.String(),
+	)
+}
+
+// TestRunProgress makes sure the progress is rendered on every
+// tick, and finished once the simulation completes
+func TestRunProgress(t *testing.T) {
+	var (
+		output bytes.Buffer
+		clock  = &fakeClock{current: time.Date(2022, 10, 29, 21, 58, 14, 0, time.UTC)}
+
+		ticks  = make(chan time.Time)
+		doneCh = make(chan struct{})
+		exitCh = make(chan struct{})
+	)
+
+	renderer := newProgressRenderer(&output, func() game.Progress {
+		return game.Progress{AliensRemaining: 2, CitiesDestroyed: 1}
+	}, clock.now)
+
+	go func() {
+		defer close(exitCh)
+
+		runProgress(renderer, ticks, doneCh)
+	}()
+
+	ticks <- clock.now()
+
+	close(doneCh)
+	<-exitCh
+
+	assert.Equal(
+		t,
+		"\rElapsed: 0s | Aliens remaining: 2 | Cities destroyed: 1"+
+			"\rElapsed: 0s | Aliens remaining: 2 | Cities destroyed: 1\n",
+		output.String(),
+	)
+}
+
+// TestIsProgressEnabled makes sure the progress is
+// not displayed when the output is not a terminal
+func TestIsProgressEnabled(t *testing.T) {
+	var output bytes.Buffer
+
+	assert.False(t, isProgressEnabled(&output))
+
+	// The command output is not a terminal in tests,
+	// so the progress never ends up on standard error
+	_, stderr, err := executeRootCommand(
+		t,
+		"2",
+		"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+	)
+	if err != nil {
+		t.Fatalf("unable to execute command, %v", err)
+	}
+
+	assert.NotContains(t, stderr, "Aliens remaining")
+}
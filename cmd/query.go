@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// Define the present flags for the query command
+const (
+	neighborsOfFlag = "neighbors-of"
+)
+
+// queryParams defines the storage for
+// the query command arguments
+type queryParams struct {
+	mapPath     string
+	neighborsOf string
+}
+
+// newQueryCommand creates the command that looks up
+// the map file without simulating the invasion
+func newQueryCommand() *cobra.Command {
+	queryParams := &queryParams{}
+
+	queryCmd := &cobra.Command{
+		Use:          "query",
+		Short:        "Looks up the neighbors of a city in the map file, without simulating the invasion",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runQuery(cmd, queryParams)
+		},
+	}
+
+	queryCmd.Flags().StringVar(
+		&queryParams.mapPath,
+		mapPathFlag,
+		"",
+		"The path to the input map file of the Earth",
+	)
+
+	queryCmd.Flags().StringVar(
+		&queryParams.neighborsOf,
+		neighborsOfFlag,
+		"",
+		"The city whose neighbors are printed, along with the directions of the roads to them",
+	)
+
+	_ = queryCmd.MarkFlagRequired(mapPathFlag)
+	_ = queryCmd.MarkFlagRequired(neighborsOfFlag)
+
+	return queryCmd
+}
+
+// runQuery runs the query command
+func runQuery(cmd *cobra.Command, queryParams *queryParams) error {
+	earthMap := game.NewEarthMap(hclog.NewNullLogger(), game.WithPreservedOrder())
+
+	if err := loadMap(queryParams.mapPath, earthMap); err != nil {
+		return err
+	}
+
+	roads, err := earthMap.Neighbors(queryParams.neighborsOf)
+	if err != nil {
+		return fmt.Errorf("unable to look up the neighbors, %w", err)
+	}
+
+	if err := writeNeighbors(cmd.OutOrStdout(), queryParams.neighborsOf, roads); err != nil {
+		return fmt.Errorf("unable to write the neighbors, %w", err)
+	}
+
+	return nil
+}
+
+// writeNeighbors writes out the roads of the city as a table,
+// in the order they are declared in the map file
+func writeNeighbors(w io.Writer, name string, roads []game.Road) error {
+	if len(roads) == 0 {
+		_, err := fmt.Fprintf(w, "%s has no neighbors\n", name)
+
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, road := range roads {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\n", road.Direction, road.City)
+	}
+
+	return tw.Flush()
+}
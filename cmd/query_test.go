@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// TestQuery_NeighborsOf makes sure the neighbors of the city are
+// printed along with the road directions, in the map file order
+func TestQuery_NeighborsOf(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo west=Baz north=Bar", "Bar south=Foo", "Baz east=Foo", "Lone")
+
+	testTable := []struct {
+		name           string
+		city           string
+		expectedOutput string
+	}{
+		{
+			"city with neighbors",
+			"Foo",
+			"west   Baz\nnorth  Bar\n",
+		},
+		{
+			"implicitly declared road",
+			"Bar",
+			"south  Foo\n",
+		},
+		{
+			"isolated city",
+			"Lone",
+			"Lone has no neighbors\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			stdout, _, err := executeRootCommand(
+				t,
+				"query",
+				"--map-path", mapPath,
+				"--neighbors-of", testCase.city,
+			)
+			if err != nil {
+				t.Fatalf("unable to run the query command, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedOutput, stdout)
+		})
+	}
+}
+
+// TestQuery_CityNotFound makes sure a city missing
+// from the map is reported as an error
+func TestQuery_CityNotFound(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	stdout, _, err := executeRootCommand(
+		t,
+		"query",
+		"--map-path", mapPath,
+		"--neighbors-of", "Baz",
+	)
+
+	assert.ErrorIs(t, err, game.ErrCityNotFound)
+	assert.ErrorContains(t, err, "Baz")
+	assert.Equal(t, exitCodeError, exitCode(err))
+	assert.Empty(t, stdout)
+}
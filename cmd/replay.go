@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// Define the present flags for the replay command
+const (
+	replayFileFlag = "replay-file"
+)
+
+var errReplaySeed = errors.New("the seed can't be set, because the replay file dictates the randomness")
+
+// replayParams defines the storage for
+// the replay command arguments
+type replayParams struct {
+	mapPath    string
+	replayFile string
+	outputPath string
+	seed       int64
+}
+
+// newReplayCommand creates the command that re-runs a recorded simulation
+func newReplayCommand() *cobra.Command {
+	replayParams := &replayParams{}
+
+	replayCmd := &cobra.Command{
+		Use:          "replay",
+		Short:        "Replays a recorded invasion simulation on the map",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if cmd.Flags().Changed(seedFlag) {
+				return errReplaySeed
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runReplay(cmd, replayParams)
+		},
+	}
+
+	replayCmd.Flags().StringVar(
+		&replayParams.mapPath,
+		mapPathFlag,
+		"",
+		"The path to the input map file of the Earth",
+	)
+
+	replayCmd.Flags().StringVar(
+		&replayParams.replayFile,
+		replayFileFlag,
+		"",
+		"The path to the recorded simulation file",
+	)
+
+	replayCmd.Flags().StringVar(
+		&replayParams.outputPath,
+		outputPathFlag,
+		"",
+		"The path to output the Earth map after the replay. If omitted, the output is directed to the console",
+	)
+
+	// The seed is only accepted so it can be explicitly rejected
+	replayCmd.Flags().Int64Var(
+		&replayParams.seed,
+		seedFlag,
+		0,
+		"Not supported, the replay file dictates the randomness",
+	)
+
+	_ = replayCmd.Flags().MarkHidden(seedFlag)
+
+	setRequiredFlags(replayCmd, []string{mapPathFlag, replayFileFlag})
+
+	return replayCmd
+}
+
+// runReplay runs the replay command
+func runReplay(cmd *cobra.Command, replayParams *replayParams) error {
+	recording, err := readRecording(replayParams.replayFile)
+	if err != nil {
+		return err
+	}
+
+	logger := newLogger(cmd.ErrOrStderr())
+
+	earthMap := game.NewEarthMap(logger)
+
+	if err := loadMap(replayParams.mapPath, earthMap); err != nil {
+		return err
+	}
+
+	if err := earthMap.Replay(recording); err != nil {
+		return fmt.Errorf("unable to replay the invasion, %w", err)
+	}
+
+	// Set up the output writer
+	writer, err := getDestinationWriter(cmd, replayParams.outputPath, stream.FileWriterOptions{})
+	if err != nil {
+		return err
+	}
+
+	if err := writeOutput(writer, earthMap.WriteOutput); err != nil {
+		return err
+	}
+
+	logger.Info("Replay completed successfully!")
+
+	return nil
+}
+
+// readRecording reads the recorded simulation from the replay file
+func readRecording(replayFile string) (*game.Recording, error) {
+	file, err := os.Open(replayFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the replay file, %w", err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var recording game.Recording
+
+	if err := json.NewDecoder(file).Decode(&recording); err != nil {
+		return nil, fmt.Errorf("unable to read the replay file, %w", err)
+	}
+
+	return &recording, nil
+}
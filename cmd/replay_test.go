@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// writeRecording writes the recording to a temporary replay file,
+// and returns its path
+func writeRecording(t *testing.T, recording *game.Recording) string {
+	t.Helper()
+
+	replayPath := filepath.Join(t.TempDir(), "run.replay")
+
+	data, err := json.Marshal(recording)
+	if err != nil {
+		t.Fatalf("unable to marshal the recording, %v", err)
+	}
+
+	if err := os.WriteFile(replayPath, data, 0o600); err != nil {
+		t.Fatalf("unable to write replay file, %v", err)
+	}
+
+	return replayPath
+}
+
+// TestReplay_RecordedRun makes sure a run recorded using the library
+// is replayed through the command with the identical result
+func TestReplay_RecordedRun(t *testing.T) {
+	var (
+		mapPath      = filepath.Join(t.TempDir(), "map.txt")
+		expectedPath = filepath.Join(t.TempDir(), "expected.txt")
+		outputPath   = filepath.Join(t.TempDir(), "output.txt")
+
+		recorder = game.NewRecorder()
+	)
+
+	// Write out the map file
+	mapWriter, err := stream.NewFileWriter(mapPath)
+	if err != nil {
+		t.Fatalf("unable to create map file, %v", err)
+	}
+
+	if err := game.GenerateGridMap(10, 10).WriteOutput(mapWriter); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	_ = mapWriter.Close()
+
+	// Record a run using the library
+	earthMap := game.NewEarthMap(hclog.NewNullLogger(), game.WithRecorder(recorder))
+
+	if err := loadMap(mapPath, earthMap); err != nil {
+		t.Fatalf("unable to load the map, %v", err)
+	}
+
+	if _, err := earthMap.SimulateInvasion(context.Background(), 30); err != nil {
+		t.Fatalf("unable to simulate the invasion, %v", err)
+	}
+
+	expectedWriter, err := stream.NewFileWriter(expectedPath)
+	if err != nil {
+		t.Fatalf("unable to create expected output file, %v", err)
+	}
+
+	if err := earthMap.WriteOutput(expectedWriter); err != nil {
+		t.Fatalf("unable to write expected output file, %v", err)
+	}
+
+	_ = expectedWriter.Close()
+
+	// Replay the run through the command
+	_, stderr, err := executeRootCommand(
+		t,
+		"replay",
+		"--map-path", mapPath,
+		"--replay-file", writeRecording(t, recorder.Recording()),
+		"--output-path", outputPath,
+	)
+	if err != nil {
+		t.Fatalf("unable to replay the run, %v", err)
+	}
+
+	expected, _ := os.ReadFile(expectedPath)
+	output, _ := os.ReadFile(outputPath)
+
+	assert.Equal(t, string(expected), string(output))
+	assert.Contains(t, stderr, "cities were destroyed")
+}
+
+// TestReplay_Invalid makes sure mismatched replay files
+// and the seed flag are rejected
+func TestReplay_Invalid(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	t.Run("unknown city", func(t *testing.T) {
+		replayPath := writeRecording(t, &game.Recording{
+			Placements: []game.Decision{{Alien: 0, City: "Zed"}},
+		})
+
+		_, _, err := executeRootCommand(
+			t,
+			"replay",
+			"--map-path", mapPath,
+			"--replay-file", replayPath,
+			"--output-path", outputPath,
+		)
+
+		assert.ErrorIs(t, err, game.ErrReplayMismatch)
+		assert.NoFileExists(t, outputPath)
+	})
+
+	t.Run("seed provided", func(t *testing.T) {
+		replayPath := writeRecording(t, &game.Recording{})
+
+		_, _, err := executeRootCommand(
+			t,
+			"replay",
+			"--map-path", mapPath,
+			"--replay-file", replayPath,
+			"--seed", "42",
+		)
+
+		assert.ErrorIs(t, err, errReplaySeed)
+	})
+}
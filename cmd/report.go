@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+	"github.com/zivkovicmilos/alien-invasion/version"
+)
+
+// destructionMessage returns the message announcing the destruction
+func destructionMessage(destruction game.Destruction) string {
+	return fmt.Sprintf(
+		"%s has been destroyed by alien %d and alien %d!",
+		destruction.City,
+		destruction.Aliens[0],
+		destruction.Aliens[1],
+	)
+}
+
+// destructionReport collects the destructions of the invasion in the order
+// they happen, so they can be written to the output along with the map
+type destructionReport struct {
+	mux      sync.Mutex
+	messages []string
+}
+
+// newDestructionReport creates a new, empty destruction report
+func newDestructionReport() *destructionReport {
+	return &destructionReport{
+		messages: make([]string, 0),
+	}
+}
+
+// add records the destruction. It is a destruction listener
+// of the simulation, so it's called from the alien routines concurrently
+func (r *destructionReport) add(destruction game.Destruction) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.messages = append(r.messages, destructionMessage(destruction))
+}
+
+// write writes out the recorded destructions as comment lines,
+// so the output can still be read back as a map. The report starts with
+// a line stamped with the program version, so it can be traced back to its build
+func (r *destructionReport) write(writer stream.OutputWriter) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if err := writer.Write(reportHeader()); err != nil {
+		return err
+	}
+
+	for _, message := range r.messages {
+		if err := writer.Write(fmt.Sprintf("# %s\n", message)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportHeader returns the header line of the destruction report
+func reportHeader() string {
+	return fmt.Sprintf("# Destructions reported by alien-invasion %s\n", version.Version)
+}
+
+// combineListeners combines the destruction listeners
+// into a single one, notifying each of them in order
+func combineListeners(listeners ...func(game.Destruction)) func(game.Destruction) {
+	return func(destruction game.Destruction) {
+		for _, listener := range listeners {
+			listener(destruction)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+	"github.com/zivkovicmilos/alien-invasion/version"
+)
+
+// TestDestructionReport_Write makes sure the destructions are
+// written out as comment lines, in the order they happened
+func TestDestructionReport_Write(t *testing.T) {
+	var (
+		output    bytes.Buffer
+		report    = newDestructionReport()
+		announced = make([]string, 0)
+	)
+
+	listener := combineListeners(report.add, func(destruction game.Destruction) {
+		announced = append(announced, destruction.City)
+	})
+
+	listener(game.Destruction{City: "Foo", Aliens: []int{3, 7}})
+	listener(game.Destruction{City: "Bar", Aliens: []int{1, 2}})
+
+	writer := stream.NewWriter(&output)
+
+	assert.NoError(t, report.write(writer))
+	assert.NoError(t, writer.Flush())
+
+	assert.Equal(
+		t,
+		reportHeader()+
+			"# Foo has been destroyed by alien 3 and alien 7!\n# Bar has been destroyed by alien 1 and alien 2!\n",
+		output.String(),
+	)
+	assert.Equal(t, []string{"Foo", "Bar"}, announced)
+}
+
+// TestRoot_ReportDestructions makes sure the destructions are written
+// to the output before the map, when enabled, and the output is still a map
+func TestRoot_ReportDestructions(t *testing.T) {
+	mapPath := writeTempMap(t, "A east=B", "B east=C", "C east=D", "D east=E", "E east=F")
+
+	t.Run("report enabled", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+		stdout, _, err := executeRootCommand(
+			t,
+			"6",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--report-destructions",
+			"--seed", "1",
+		)
+		assert.NoError(t, err)
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		// Each announced destruction is reported in the output
+		announcements := regexp.MustCompile(`(?m)^\w+ has been destroyed by alien \d+ and alien \d+!$`).
+			FindAllString(stdout, -1)
+
+		assert.NotEmpty(t, announcements)
+
+		reported := make([]string, 0)
+		mapLines := make([]string, 0)
+
+		// The report starts with the version stamp
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		assert.Equal(t, "# Destructions reported by alien-invasion "+version.Version, lines[0])
+
+		for _, line := range lines[1:] {
+			if strings.HasPrefix(line, "# ") {
+				// The report precedes the map
+				assert.Empty(t, mapLines)
+
+				reported = append(reported, strings.TrimPrefix(line, "# "))
+			} else {
+				mapLines = append(mapLines, line)
+			}
+		}
+
+		assert.ElementsMatch(t, announcements, reported)
+
+		// The output is read back as a map, skipping the report
+		stdout, _, err = executeRootCommand(t, "stats", "--map-path", outputPath)
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "Cities")
+	})
+
+	t.Run("report disabled", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+		_, _, err := executeRootCommand(t, "6", "--map-path", mapPath, "--output-path", outputPath, "--seed", "1")
+		assert.NoError(t, err)
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		assert.NotContains(t, string(output), "#")
+	})
+
+	for _, args := range [][]string{{"--runs", "2"}, {"--output-format", "json"}} {
+		args := args
+
+		t.Run("unsupported "+args[0], func(t *testing.T) {
+			_, _, err := executeRootCommand(
+				t,
+				append([]string{"6", "--map-path", mapPath, "--report-destructions"}, args...)...,
+			)
+
+			assert.ErrorIs(t, err, errReportUnsupported)
+		})
+	}
+}
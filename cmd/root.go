@@ -4,23 +4,63 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
 	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/scenario"
 	"github.com/zivkovicmilos/alien-invasion/stream"
+	"github.com/zivkovicmilos/alien-invasion/version"
 )
 
 var (
 	errInvalidAlienNumber = errors.New("invalid number of aliens provided")
-	errAlienNumberMissing = errors.New("number of aliens not provided as argument")
+	errAlienNumberMissing = errors.New("number of aliens not provided as argument or flag")
+	errAlienNumberTwice   = errors.New("number of aliens provided both as argument and flag")
+	errInvalidLogFormat   = errors.New("invalid log format provided")
+	errInvalidLogLevel    = errors.New("invalid log level provided")
+	errFormatUnsupported  = errors.New("output format is not supported for this output")
+	errInvalidMaxMoves    = errors.New("max moves must be a positive number")
+	errInvalidMaxLineSize = errors.New("max line size must be a positive number")
+	errInvalidTimeout     = errors.New("timeout must not be negative")
+	errInvalidForceExit   = errors.New("force exit duration must not be negative")
+	errInvalidRuns        = errors.New("number of runs must be a positive number")
+	errInvalidParallel    = errors.New("number of parallel runs must be a positive number")
+	errAppendWithoutFile  = errors.New("append output requires an output file path")
+	errAppendUnsupported  = errors.New("append output is only supported for the text format")
+	errReportUnsupported  = errors.New("destruction report is only supported for a single run in the text format")
+	errNoClobberConflict  = errors.New("no-clobber output conflicts with append output")
+	errNoClobberNoFile    = errors.New("no-clobber output requires an output file path")
+	errOutputExists       = errors.New("output file already exists")
+	errInteractiveRuns    = errors.New("interactive mode only supports a single run")
+	errLogFileConflict    = errors.New("log file alias conflicts with the log output destination")
+	errLogAlsoStderr      = errors.New("logging to the standard error output as well requires a log file")
+	errInteractiveStdin   = errors.New("interactive mode reads the commands from the standard input, so the map can't be read from it")
+	errTUIUnsupported     = errors.New("the dashboard only supports a single, non-interactive run")
+	errFormatConflict     = errors.New("format alias conflicts with the output format")
+	errJSONLogConflict    = errors.New("json log flag conflicts with the log format")
 )
 
+// newEarthMap is the Earth map constructor used by the root command,
+// which can be overridden for testing purposes
+var newEarthMap = game.NewEarthMap
+
+// openOutputWriter opens the output destination writer used by the root command,
+// which can be overridden for testing purposes
+var openOutputWriter = getDestinationWriter
+
+// terminationSignalCh returns the termination signal channel used by the root command,
+// which can be overridden for testing purposes
+var terminationSignalCh = getTerminationSignalCh
+
 type RootCommand struct {
 	baseCmd *cobra.Command
 }
@@ -28,10 +68,15 @@ type RootCommand struct {
 func NewRootCommand() *RootCommand {
 	rootCommand := &RootCommand{
 		baseCmd: &cobra.Command{
-			Short:   "A program for simulating the invasion of mad aliens on Earth",
-			Args:    validateArguments,
-			PreRunE: runPreRun,
-			RunE:    runCommand,
+			Use:               "alien-invasion [aliens]",
+			Short:             "A program for simulating the invasion of mad aliens on Earth",
+			Long:              "A program for simulating the invasion of mad aliens on Earth\n\n" + envHelp + "\n\n" + exitCodesHelp,
+			Example:           alienNumberExample,
+			Version:           version.String(),
+			Args:              validateArguments,
+			PersistentPreRunE: applyEnv,
+			PreRunE:           runPreRun,
+			RunE:              runCommand,
 		},
 	}
 
@@ -41,31 +86,101 @@ func NewRootCommand() *RootCommand {
 	// Set the required flags
 	setRequiredFlags(rootCommand.baseCmd, params.getRequiredFlags())
 
+	// Set the subcommands
+	rootCommand.baseCmd.AddCommand(
+		newValidateCommand(),
+		newGenerateCommand(),
+		newStatsCommand(),
+		newQueryCommand(),
+		newDiffCommand(),
+		newConvertCommand(),
+		newReplayCommand(),
+		newServeCommand(),
+		newVersionCommand(),
+		newCompletionCommand(),
+	)
+
+	// Set the flag value completions
+	registerFlagCompletions(rootCommand.baseCmd)
+
 	return rootCommand
 }
 
 func (rc *RootCommand) Execute() {
-	if err := rc.baseCmd.Execute(); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
+	err := rc.baseCmd.Execute()
 
-		os.Exit(1)
+	// The invasion outcomes are not failures, so they are only reported with the exit code
+	var outcome *outcomeError
+	if err != nil && !errors.As(err, &outcome) {
+		_, _ = fmt.Fprintln(os.Stderr, err)
 	}
+
+	os.Exit(exitCode(err))
 }
 
+// alienNumberExample shows both forms of providing the number of aliens
+const alienNumberExample = `  alien-invasion 10 --map-path ./mapfile.txt
+  alien-invasion --aliens 10 --map-path ./mapfile.txt`
+
 // setFlags sets the base command flags
 func setFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&params.aliens,
+		aliensFlag,
+		0,
+		"The number of aliens, as an alternative to the positional argument",
+	)
+
+	cmd.Flags().StringVar(
+		&params.configPath,
+		configFlag,
+		"",
+		"The path to the YAML (or JSON) config file, holding the flag values keyed by the flag names. The flags and environment variables take precedence over the config file",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.printConfig,
+		printConfigFlag,
+		false,
+		"Print the resolved configuration in the config file format, without simulating the invasion",
+	)
+
 	cmd.Flags().StringVar(
 		&params.mapPath,
 		mapPathFlag,
 		"",
-		"The path to the input map file of the Earth",
+		fmt.Sprintf(
+			"The path (or http(s) URL) to the input map file of the Earth, or %q for the standard input (the default when piped)",
+			stdinPath,
+		),
+	)
+
+	cmd.Flags().StringVar(
+		&params.builtinMap,
+		builtinMapFlag,
+		"",
+		fmt.Sprintf(
+			"The name of a demo map embedded in the program, used instead of the map path (%s)",
+			strings.Join(builtinMaps(), ", "),
+		),
+	)
+
+	params.inputFormat = stream.FormatText
+
+	cmd.Flags().Var(
+		newFormatValue(&params.inputFormat, stream.InputFormats()),
+		inputFormatFlag,
+		fmt.Sprintf(
+			"The format of the input map (%s). If omitted, the format is detected from the map path extension",
+			joinFormats(stream.InputFormats()),
+		),
 	)
 
 	cmd.Flags().StringVar(
 		&params.outputPath,
 		outputPathFlag,
 		"",
-		"The path to output the Earth map after the invasion. If omitted, the output is directed to the console",
+		"The path (or http(s) URL to POST) to output the Earth map after the invasion. If omitted, the output is directed to the console",
 	)
 
 	cmd.Flags().StringVar(
@@ -74,22 +189,294 @@ func setFlags(cmd *cobra.Command) {
 		"INFO",
 		"The log level for the program execution",
 	)
+
+	cmd.Flags().IntVar(
+		&params.maxCities,
+		maxCitiesFlag,
+		game.DefaultMaxCities,
+		"The max number of cities the input map can contain, 0 for unlimited",
+	)
+
+	cmd.Flags().IntVar(
+		&params.maxLineSize,
+		maxLineSizeFlag,
+		stream.DefaultMaxLineSize,
+		"The max size of a single text map line in bytes. A longer line fails the map reading",
+	)
+
+	cmd.Flags().IntVar(
+		&params.maxAliens,
+		maxAliensFlag,
+		game.DefaultMaxAliens,
+		"The max number of aliens the simulation can use, 0 for unlimited",
+	)
+
+	cmd.Flags().IntVar(
+		&params.maxMoves,
+		maxMovesFlag,
+		game.DefaultMaxMoves,
+		"The max number of moves each alien makes before it stops wandering",
+	)
+
+	cmd.Flags().IntVar(
+		&params.runs,
+		runsFlag,
+		1,
+		"The number of times the invasion is simulated. Multiple runs output the aggregate statistics instead of the map",
+	)
+
+	cmd.Flags().IntVar(
+		&params.parallel,
+		parallelFlag,
+		1,
+		"The number of invasion runs simulated at the same time",
+	)
+
+	cmd.Flags().Int64Var(
+		&params.seed,
+		seedFlag,
+		0,
+		"The seed for the random alien placement and movement. If omitted, a random seed is generated",
+	)
+
+	cmd.Flags().DurationVar(
+		&params.timeout,
+		timeoutFlag,
+		0,
+		"The max duration of the invasion simulation (e.g. 30s, 5m). If omitted, the simulation is not bounded",
+	)
+
+	cmd.Flags().DurationVar(
+		&params.forceExit,
+		forceExitFlag,
+		0,
+		"The max duration of the graceful shutdown after a termination signal, before the exit is forced. "+
+			"If omitted, the exit is forced only by a second signal",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.travelCosts,
+		travelCostsFlag,
+		false,
+		"Parse the road travel costs from the map (e.g. north=Bar:3), and spend the max moves as a travel budget",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.perAlienRand,
+		perAlienRandFlag,
+		false,
+		"Give each alien its own random number generator, seeded from the seed and the alien ID, instead of sharing one",
+	)
+
+	cmd.Flags().StringVar(
+		&params.scenarioPath,
+		scenarioFlag,
+		"",
+		"The path to the YAML scenario file, bundling the map, the simulation parameters and the expected outcome. "+
+			"The flags and environment variables take precedence over the scenario, which takes precedence over the config file",
+	)
+
+	cmd.Flags().StringVar(
+		&params.strategy,
+		strategyFlag,
+		game.DefaultStrategy,
+		fmt.Sprintf("The movement strategy of the aliens (%s)", strings.Join(game.Strategies(), ", ")),
+	)
+
+	cmd.Flags().StringVar(
+		&params.collision,
+		collisionFlag,
+		string(game.DefaultCollisionStrategy),
+		fmt.Sprintf(
+			"The outcome of two aliens meeting in a city (%s)",
+			strings.Join(game.CollisionStrategies(), ", "),
+		),
+	)
+
+	cmd.Flags().BoolVar(
+		&params.interactive,
+		interactiveFlag,
+		false,
+		"Step through the invasion at a prompt on the standard input, after the aliens are placed",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.tui,
+		tuiFlag,
+		false,
+		"Display a live dashboard of the invasion on the standard output, if it's a terminal",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.dryRun,
+		dryRunFlag,
+		false,
+		"Load and validate the map, and report the effective parameters, without simulating the invasion or writing the output",
+	)
+
+	params.outputFormat = stream.FormatText
+
+	cmd.Flags().Var(
+		newFormatValue(&params.outputFormat, stream.OutputFormats()),
+		outputFormatFlag,
+		fmt.Sprintf(
+			"The format of the map output (%s). If omitted, the format is inferred from the output path extension",
+			joinFormats(stream.OutputFormats()),
+		),
+	)
+
+	// The format flag is a shorter alias of the output format flag.
+	// It's kept apart, so it can't silently override a different output format
+	params.format = ""
+
+	cmd.Flags().Var(
+		newFormatValue(&params.format, stream.OutputFormats()),
+		formatFlag,
+		fmt.Sprintf("An alias of the --%s flag", outputFormatFlag),
+	)
+
+	_ = cmd.Flags().MarkHidden(formatFlag)
+
+	cmd.Flags().BoolVar(
+		&params.listSurvivors,
+		listSurvivorsFlag,
+		false,
+		"Output only the sorted names of the cities that survived the invasion",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.reportDestructions,
+		reportDestructionsFlag,
+		false,
+		"Write the destructions to the output as comment lines before the map, in the order they happened",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.appendOutput,
+		appendOutputFlag,
+		false,
+		"Append the output to the output file after a run header line, instead of replacing the file",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.noClobber,
+		noClobberFlag,
+		false,
+		"Refuse to write the output if the output file already exists, instead of replacing the file",
+	)
+
+	cmd.Flags().StringVar(
+		&params.checksumFile,
+		checksumFileFlag,
+		"",
+		"The path to write the SHA-256 checksum of the output to, in the sha256sum format. If omitted, the checksum is not computed",
+	)
+
+	cmd.Flags().StringVar(
+		&params.logOutput,
+		logOutputFlag,
+		logOutputStderr,
+		fmt.Sprintf(
+			"The log output destination for the program execution (%s, %s or a file path)",
+			logOutputStdout,
+			logOutputStderr,
+		),
+	)
+
+	cmd.Flags().StringVar(
+		&params.logFile,
+		logFileFlag,
+		"",
+		fmt.Sprintf(
+			"An alias of the --%s flag for a log file path. Logs from successive runs are appended to the file",
+			logOutputFlag,
+		),
+	)
+
+	cmd.Flags().BoolVar(
+		&params.logAlsoStderr,
+		logAlsoStderrFlag,
+		false,
+		fmt.Sprintf("Write the logs to the standard error output as well as the log file (set with --%s or --%s)", logOutputFlag, logFileFlag),
+	)
+
+	cmd.Flags().StringVar(
+		&params.logFormat,
+		logFormatFlag,
+		logFormatText,
+		fmt.Sprintf(
+			"The log format for the program execution (%s or %s)",
+			logFormatText,
+			logFormatJSON,
+		),
+	)
+
+	cmd.Flags().BoolVar(
+		&params.quiet,
+		quietFlag,
+		false,
+		"Suppress the logs below the error level and the invasion summary, so only the map is output",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.announce,
+		announceFlag,
+		false,
+		"Keep the destruction announcements on the standard output in quiet mode",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.jsonLog,
+		jsonLogFlag,
+		false,
+		fmt.Sprintf("Emit the logs in JSON format, shorthand for --%s %s", logFormatFlag, logFormatJSON),
+	)
 }
 
 // validateArguments validates that the command line arguments are valid
 func validateArguments(cmd *cobra.Command, args []string) error {
-	// Make sure at least one argument is present (the number of aliens)
-	if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
-		return errAlienNumberMissing
+	// The number of aliens can also be set in the environment or the config file,
+	// so the missing number is only detected once they are applied
+	if len(args) == 0 && !cmd.Flags().Changed(aliensFlag) {
+		return nil
 	}
 
-	// Make sure the number of aliens is valid
-	numAliens, err := strconv.Atoi(args[0])
-	if err != nil || numAliens == 0 {
-		return errInvalidAlienNumber
+	// Make sure the number of aliens is present exactly once, and valid
+	_, err := resolveAlienNumber(cmd, args)
+
+	return err
+}
+
+// resolveAlienNumber resolves the number of aliens from either the
+// positional argument, or the aliens flag, which are mutually exclusive
+func resolveAlienNumber(cmd *cobra.Command, args []string) (int, error) {
+	flagSet := cmd.Flags().Changed(aliensFlag)
+
+	switch {
+	case flagSet && len(args) > 0:
+		return 0, errAlienNumberTwice
+	case flagSet:
+		if params.aliens <= 0 {
+			return 0, fmt.Errorf("%w: %d, expected a positive integer", errInvalidAlienNumber, params.aliens)
+		}
+
+		return params.aliens, nil
+	case len(args) == 0:
+		return 0, errAlienNumberMissing
+	default:
+		return parseAlienNumber(args[0])
 	}
+}
 
-	return nil
+// parseAlienNumber parses the number of aliens argument,
+// which must be a positive integer
+func parseAlienNumber(arg string) (int, error) {
+	numAliens, err := strconv.Atoi(arg)
+	if err != nil || numAliens <= 0 {
+		return 0, fmt.Errorf("%w: %q, expected a positive integer", errInvalidAlienNumber, arg)
+	}
+
+	return numAliens, nil
 }
 
 // setRequiredFlags marks the specified flags as required
@@ -100,42 +487,423 @@ func setRequiredFlags(cmd *cobra.Command, requiredFlags []string) {
 }
 
 // runPreRun instantiates the command line arguments for the runtime
-func runPreRun(_ *cobra.Command, args []string) error {
-	numAliens, err := strconv.Atoi(args[0])
-	if err != nil || numAliens == 0 {
-		return errInvalidAlienNumber
+func runPreRun(cmd *cobra.Command, args []string) error {
+	// Fill in the flags that are not set on the command line from the scenario
+	params.scenario = nil
+
+	if params.scenarioPath != "" {
+		loaded, err := scenario.Load(params.scenarioPath)
+		if err != nil {
+			return err
+		}
+
+		if err := applyScenario(cmd, args, loaded); err != nil {
+			return err
+		}
+
+		params.scenario = loaded
+	}
+
+	// Fill in the flags that are not set on the command line from the config file
+	if params.configPath != "" {
+		values, err := loadConfig(params.configPath)
+		if err != nil {
+			return err
+		}
+
+		if err := applyConfig(cmd, args, values); err != nil {
+			return err
+		}
+	}
+
+	// The built-in map replaces the map file
+	if err := applyBuiltinMap(cmd); err != nil {
+		return err
+	}
+
+	// Read the piped map from the standard input, if the map path is omitted
+	if err := applyStdinMapPath(cmd); err != nil {
+		return err
+	}
+
+	// Fill in the remaining defaults from the map header
+	if err := applyMapHeader(cmd, args, params.mapPath); err != nil {
+		return err
+	}
+
+	numAliens, err := resolveAlienNumber(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	// Reject absurd alien counts before the map is loaded,
+	// since every alien is allocated up front
+	if params.maxAliens > 0 && numAliens > params.maxAliens {
+		return fmt.Errorf(
+			"%w: %d aliens, at most %d allowed (raise the limit with --%s, or 0 for unlimited)",
+			game.ErrTooManyAliens,
+			numAliens,
+			params.maxAliens,
+			maxAliensFlag,
+		)
 	}
 
 	// Set the number of aliens
 	params.n = numAliens
 
+	// Make sure the max move count is valid
+	if params.maxMoves <= 0 {
+		return fmt.Errorf("%w: %d", errInvalidMaxMoves, params.maxMoves)
+	}
+
+	// Make sure the max line size is valid
+	if params.maxLineSize <= 0 {
+		return fmt.Errorf("%w: %d", errInvalidMaxLineSize, params.maxLineSize)
+	}
+
+	// Make sure the number of runs is valid
+	if params.runs <= 0 {
+		return fmt.Errorf("%w: %d", errInvalidRuns, params.runs)
+	}
+
+	// Make sure the run parallelism is valid
+	if params.parallel <= 0 {
+		return fmt.Errorf("%w: %d", errInvalidParallel, params.parallel)
+	}
+
+	// Make sure the timeout is valid
+	if params.timeout < 0 {
+		return fmt.Errorf("%w: %s", errInvalidTimeout, params.timeout)
+	}
+
+	if params.forceExit < 0 {
+		return fmt.Errorf("%w: %s", errInvalidForceExit, params.forceExit)
+	}
+
+	// Make sure the movement strategy is registered
+	if _, err := game.LookupStrategy(params.strategy); err != nil {
+		return err
+	}
+
+	// Make sure the collision strategy is known
+	if _, err := game.ParseCollisionStrategy(params.collision); err != nil {
+		return err
+	}
+
+	// The interactive session steps through a single invasion,
+	// and takes over the standard input for the commands
+	if params.interactive {
+		if params.runs > 1 {
+			return fmt.Errorf("%w: %d runs", errInteractiveRuns, params.runs)
+		}
+
+		if params.mapPath == stdinPath {
+			return errInteractiveStdin
+		}
+	}
+
+	// The dashboard follows a single invasion as it happens
+	if params.tui && (params.runs > 1 || params.interactive) {
+		return errTUIUnsupported
+	}
+
+	// The JSON log flag is a shorthand for the JSON log format,
+	// so it can't be combined with a different log format
+	if params.jsonLog {
+		if cmd.Flags().Changed(logFormatFlag) && params.logFormat != logFormatJSON {
+			return fmt.Errorf("%w: --%s %s", errJSONLogConflict, logFormatFlag, params.logFormat)
+		}
+
+		params.logFormat = logFormatJSON
+	}
+
+	// Make sure the log format is supported
+	if params.logFormat != logFormatText && params.logFormat != logFormatJSON {
+		return fmt.Errorf("%w: %s", errInvalidLogFormat, params.logFormat)
+	}
+
+	// Quiet mode only lets the errors through
+	if params.quiet {
+		params.logLevel = hclog.Error.String()
+	}
+
+	// The format alias sets the output format, unless a different one is set explicitly
+	if cmd.Flags().Changed(formatFlag) {
+		if cmd.Flags().Changed(outputFormatFlag) && params.format != params.outputFormat {
+			return fmt.Errorf(
+				"%w: --%s %s, --%s %s",
+				errFormatConflict,
+				formatFlag,
+				params.format,
+				outputFormatFlag,
+				params.outputFormat,
+			)
+		}
+
+		if err := cmd.Flags().Set(outputFormatFlag, string(params.format)); err != nil {
+			return err
+		}
+	}
+
+	// The log file flag is an alias of the log output flag,
+	// unless a different log output destination is set explicitly
+	if cmd.Flags().Changed(logFileFlag) && params.logFile != "" {
+		if cmd.Flags().Changed(logOutputFlag) && params.logFile != params.logOutput {
+			return fmt.Errorf(
+				"%w: --%s %s, --%s %s",
+				errLogFileConflict,
+				logFileFlag,
+				params.logFile,
+				logOutputFlag,
+				params.logOutput,
+			)
+		}
+
+		if err := cmd.Flags().Set(logOutputFlag, params.logFile); err != nil {
+			return err
+		}
+	}
+
+	// Detect the input format from the map path, if the format is omitted.
+	// The standard input has no extension, so it's read in the text format by default
+	if !cmd.Flags().Changed(inputFormatFlag) && params.mapPath != stdinPath {
+		params.inputFormat = stream.DetectInputFormat(params.mapPath)
+	}
+
+	// Infer the output format from the output path, if the format is omitted.
+	// The map output supports every output format, the aggregate output only a subset,
+	// and the survivor list only the text format
+	var supportedFormats []stream.Format
+
+	switch {
+	case params.runs > 1:
+		supportedFormats = aggregateFormats
+	case !params.listSurvivors:
+		supportedFormats = stream.OutputFormats()
+	}
+
+	if !cmd.Flags().Changed(outputFormatFlag) && params.outputPath != "" {
+		// Extensions of unsupported formats fall back to the text format
+		if format := stream.FormatFromPath(params.outputPath); isSupportedFormat(format, supportedFormats) {
+			params.outputFormat = format
+		}
+	}
+
+	if params.outputFormat != stream.FormatText && !isSupportedFormat(params.outputFormat, supportedFormats) {
+		return fmt.Errorf("%w: %s", errFormatUnsupported, params.outputFormat)
+	}
+
+	// Appending only makes sense for files, and only the text
+	// format can hold the output of successive runs
+	if params.appendOutput {
+		if params.outputPath == "" || stream.IsHTTPURL(params.outputPath) {
+			return errAppendWithoutFile
+		}
+
+		if params.outputFormat != stream.FormatText {
+			return fmt.Errorf("%w: %s", errAppendUnsupported, params.outputFormat)
+		}
+	}
+
+	// The output of a previous run is never replaced with no-clobber, so an existing
+	// output file is reported before the invasion is simulated, instead of after
+	if params.noClobber {
+		if params.outputPath == "" || stream.IsHTTPURL(params.outputPath) {
+			return errNoClobberNoFile
+		}
+
+		if params.appendOutput {
+			return errNoClobberConflict
+		}
+
+		if _, err := os.Stat(params.outputPath); err == nil {
+			return fmt.Errorf("%w: %s", errOutputExists, params.outputPath)
+		}
+	}
+
+	// The destruction report is written as comment lines, which only the text format has
+	if params.reportDestructions && (params.runs > 1 || params.outputFormat != stream.FormatText) {
+		return errReportUnsupported
+	}
+
+	// The standard error output can only be an addition to a log file
+	if params.logAlsoStderr && !isLogFile(params.logOutput) {
+		return errLogAlsoStderr
+	}
+
+	// Make sure the log level is known (the level names are case-insensitive)
+	if hclog.LevelFromString(params.logLevel) == hclog.NoLevel {
+		return fmt.Errorf("%w: %s", errInvalidLogLevel, params.logLevel)
+	}
+
 	return nil
 }
 
 // runCommand runs the root command
-func runCommand(_ *cobra.Command, _ []string) error {
-	// Create an instance of the file reader
-	fileReader, err := stream.NewFileReader(params.mapPath)
+func runCommand(cmd *cobra.Command, _ []string) error {
+	// Only show the resolved configuration, if set
+	if params.printConfig {
+		if err := writeEffectiveConfig(cmd.OutOrStdout(), cmd); err != nil {
+			return fmt.Errorf("unable to write the configuration, %w", err)
+		}
+
+		return nil
+	}
+
+	// Set up the log output destination
+	logOutput, closeLogOutput, err := getLogOutput(cmd, params.logOutput, params.logAlsoStderr)
 	if err != nil {
-		return fmt.Errorf("unable to create a file reader, %w", err)
+		return err
 	}
 
+	defer func() {
+		_ = closeLogOutput()
+	}()
+
 	// Create an instance of the logger
-	logger := hclog.New(&hclog.LoggerOptions{
-		Name:  "alien-invasion",
-		Level: hclog.LevelFromString(params.logLevel),
-	})
+	logger := newLogger(logOutput)
+
+	// Report the build, so the output can be traced back to it
+	logger.Info(fmt.Sprintf("Running alien-invasion %s", version.String()))
+
+	// Pick the seed for the random number generator,
+	// and report it so the run can be reproduced
+	var (
+		seed       = params.seed
+		randomSeed = !cmd.Flags().Changed(seedFlag)
+	)
+
+	if randomSeed {
+		seed = time.Now().UnixNano()
+
+		logger.Info(fmt.Sprintf("Using random seed %d", seed))
+	} else {
+		logger.Info(fmt.Sprintf("Using provided seed %d", seed))
+	}
+
+	// The strategy is validated before the run
+	strategy, err := game.LookupStrategy(params.strategy)
+	if err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("Using the %s movement strategy", params.strategy))
+
+	// The collision strategy is validated before the run
+	collision, err := game.ParseCollisionStrategy(params.collision)
+	if err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("Using the %s collision strategy", params.collision))
+
+	// Report the scenario, so the run can be traced back to the exact scenario file
+	if params.scenario != nil {
+		logger.Info(fmt.Sprintf("Running the %s scenario (sha256 %s)", params.scenario.Name, params.scenario.Hash))
+	}
+
+	mapOpts := []game.Option{
+		game.WithMaxCities(params.maxCities),
+		game.WithMaxAliens(params.maxAliens),
+		game.WithMaxMoves(params.maxMoves),
+		game.WithSeed(seed),
+		game.WithStrategy(strategy),
+		game.WithCollisionStrategy(collision),
+	}
+
+	if params.travelCosts {
+		mapOpts = append(mapOpts, game.WithTravelCosts())
+	}
+
+	if params.perAlienRand {
+		mapOpts = append(mapOpts, game.WithPerAlienRand())
+	}
+
+	// The dry run fails on the first malformed map line, like the map validation
+	if params.dryRun {
+		mapOpts = append(mapOpts, game.WithStrictParsing())
+	}
+
+	// The live dashboard is only displayed on a terminal, and falls back
+	// to the regular output otherwise, so the output can still be redirected
+	var liveDashboard *dashboard
+
+	if params.tui {
+		if isTerminal(cmd.OutOrStdout()) {
+			liveDashboard = newDashboard(cmd.OutOrStdout(), time.Now)
+		} else {
+			logger.Warn("The dashboard is disabled, since the standard output is not a terminal")
+		}
+	}
+
+	listeners := make([]func(game.Destruction), 0)
+
+	switch {
+	case liveDashboard != nil:
+		// The dashboard lists the destructions in its feed
+		listeners = append(listeners, liveDashboard.onDestruction)
+	case params.runs == 1 && (!params.quiet || params.announce):
+		// The destruction announcements are program data, so they are
+		// written to the standard output instead of the log stream.
+		// Multiple runs only output the aggregate statistics
+		listeners = append(listeners, newDestructionAnnouncer(getAnnouncementWriter(cmd)))
+	}
+
+	// The destructions are also written to the output before the map, if set,
+	// so the output holds both what happened and the final state
+	var report *destructionReport
+
+	if params.reportDestructions {
+		report = newDestructionReport()
+		listeners = append(listeners, report.add)
+	}
+
+	if len(listeners) > 0 {
+		mapOpts = append(mapOpts, game.WithDestructionListener(combineListeners(listeners...)))
+	}
 
 	// Create an instance of the Earth map
-	earthMap := game.NewEarthMap(logger)
+	earthMap := newEarthMap(logger, mapOpts...)
+
+	logger.Info(fmt.Sprintf("Using max moves per alien %d", params.maxMoves))
+
+	// Init the map from the map file, in the input format
+	mapReader, err := getMapReader(cmd)
+	if err != nil {
+		return err
+	}
+
+	// The map is read in full before the invasion, so a failure
+	// to close the map reader doesn't affect the outcome
+	defer func() {
+		if err := mapReader.Close(); err != nil {
+			logger.Warn(fmt.Sprintf("Unable to close the map reader, %v", err))
+		}
+	}()
+
+	// Check the map and the parameters only, without the invasion
+	if params.dryRun {
+		return runDryRun(cmd, mapReader, earthMap, seed, randomSeed)
+	}
+
+	if err := initMap(mapReader, earthMap); err != nil {
+		return err
+	}
 
-	// Init the map from the map file
-	earthMap.InitMap(fileReader)
+	// Keep track of the city count, to tell if every city is destroyed
+	totalCities := len(earthMap.Cities())
+
+	if liveDashboard != nil {
+		liveDashboard.track(earthMap.Progress, totalCities, params.n)
+	}
 
 	// Simulate the invasion
 	var (
 		wg                 sync.WaitGroup
 		simulationComplete = make(chan struct{})
+		simulationResult   game.SimulationResult
+		aggregateResult    game.AggregateResult
+		simulationErr      error
 	)
 
 	// The assumption is that very large invasion simulations
@@ -145,6 +913,14 @@ func runCommand(_ *cobra.Command, _ []string) error {
 	simulationCtx, cancelSimulation := context.WithCancel(context.Background())
 	defer cancelSimulation()
 
+	// Bound the total simulation run time, if set
+	if params.timeout > 0 {
+		var cancelTimeout context.CancelFunc
+
+		simulationCtx, cancelTimeout = context.WithTimeout(simulationCtx, params.timeout)
+		defer cancelTimeout()
+	}
+
 	wg.Add(1)
 
 	go func() {
@@ -152,64 +928,512 @@ func runCommand(_ *cobra.Command, _ []string) error {
 			wg.Done()
 		}()
 
-		earthMap.SimulateInvasion(simulationCtx, params.n)
+		switch {
+		case params.runs > 1:
+			aggregateResult, simulationErr = earthMap.SimulateRunsParallel(
+				simulationCtx,
+				params.n,
+				params.runs,
+				params.parallel,
+			)
+		case params.interactive:
+			session := newInteractiveSession(cmd.InOrStdin(), cmd.OutOrStdout(), earthMap)
+			simulationResult, simulationErr = session.run(simulationCtx, params.n)
+		default:
+			simulationResult, simulationErr = earthMap.SimulateInvasion(simulationCtx, params.n)
+		}
+
 		close(simulationComplete)
 	}()
 
-	// Wait for either the simulation to complete,
+	// Display the progress of long simulations on the terminal, either on the dashboard
+	// or the progress line, until the simulation completes. The interactive session has its own prompt
+	var (
+		view         progressView
+		viewInterval time.Duration
+	)
+
+	switch {
+	case liveDashboard != nil:
+		view, viewInterval = liveDashboard, dashboardInterval
+	case params.runs == 1 && !params.interactive && isProgressEnabled(cmd.ErrOrStderr()):
+		view = newProgressRenderer(cmd.ErrOrStderr(), earthMap.Progress, time.Now)
+		viewInterval = progressInterval
+	}
+
+	if view != nil {
+		ticker := time.NewTicker(viewInterval)
+		defer ticker.Stop()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			runProgress(view, ticker.C, simulationComplete)
+		}()
+	}
+
+	// The run completes once the simulation completes, and its output is written.
+	// The output can hang as well (ex. a pipe that is not read), so the termination
+	// signals are handled until the whole run completes
+	var (
+		runComplete = make(chan struct{})
+		runErr      error
+	)
+
+	finishRun := func() error {
+		// Wait for the simulation to gracefully exit
+		wg.Wait()
+
+		// Check if the simulation was cut short by the timeout, before the output is written,
+		// so the time it takes to write the output doesn't count against the timeout
+		timedOut := errors.Is(simulationCtx.Err(), context.DeadlineExceeded)
+
+		if simulationErr != nil {
+			return fmt.Errorf("unable to simulate the invasion, %w", simulationErr)
+		}
+
+		// Sum up how far the invasion got, so it's clear the termination signal was honored.
+		// Only the termination signal cancels the simulation, the timeout is reported later
+		if errors.Is(simulationCtx.Err(), context.Canceled) {
+			logInterruptSummary(logger, simulationResult, aggregateResult)
+		}
+
+		if params.runs == 1 && !params.quiet {
+			_, _ = fmt.Fprintf(
+				getAnnouncementWriter(cmd),
+				"A total of %d cities were destroyed\n",
+				simulationResult.CitiesDestroyed,
+			)
+		}
+
+		if params.travelCosts {
+			logger.Info(fmt.Sprintf("The aliens traveled a total cost of %d", simulationResult.TotalCost))
+		}
+
+		// Set up the output writer. The aggregate output is encoded
+		// on its own, since the writer format applies only to map lines
+		writerFormat := params.outputFormat
+		if params.runs > 1 {
+			writerFormat = stream.FormatText
+		}
+
+		destination, err := openOutputWriter(
+			cmd,
+			params.outputPath,
+			stream.FileWriterOptions{
+				Append:    params.appendOutput,
+				NoClobber: params.noClobber,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Checksum the exact bytes written to the output destination, in the output format
+		var checksum *stream.ChecksumWriter
+
+		if params.checksumFile != "" {
+			checksum = stream.NewChecksumWriter(destination)
+			destination = checksum
+		}
+
+		err = writeOutput(destination, func(writer stream.OutputWriter) error {
+			// Separate the output from the output of the previous runs
+			if params.appendOutput {
+				if err := writer.Write(runHeader(time.Now(), seed)); err != nil {
+					return err
+				}
+			}
+
+			// Name the scenario in the text output, so the output can be traced back to it
+			if params.scenario != nil && params.outputFormat == stream.FormatText {
+				if err := writer.Write(scenarioHeader(params.scenario)); err != nil {
+					return err
+				}
+			}
+
+			if report != nil {
+				if err := report.write(writer); err != nil {
+					return err
+				}
+			}
+
+			// Write the invasion output
+			switch {
+			case params.runs > 1:
+				return writeAggregate(writer, aggregateResult, params.outputFormat)
+			case params.listSurvivors:
+				return writeSurvivors(writer, earthMap.Cities())
+			default:
+				return earthMap.WriteOutputFormat(writer, writerFormat)
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		if checksum != nil {
+			if err := writeChecksumFile(params.checksumFile, checksum.Sum(), params.outputPath); err != nil {
+				return err
+			}
+		}
+
+		// Report the simulation cut short by the timeout
+		if timedOut {
+			if params.runs > 1 {
+				logger.Warn(
+					fmt.Sprintf(
+						"Invasion runs truncated by the %s timeout, with %d of %d runs completed",
+						params.timeout,
+						aggregateResult.Runs,
+						params.runs,
+					),
+				)
+
+				return reportOutcome(cmd, errInterrupted)
+			}
+
+			logger.Warn(
+				fmt.Sprintf(
+					"Invasion truncated by the %s timeout, with %d cities destroyed",
+					params.timeout,
+					simulationResult.CitiesDestroyed,
+				),
+			)
+
+			return reportOutcome(cmd, errInterrupted)
+		}
+
+		// Check if the simulation was cut short by a termination signal
+		if simulationResult.Interrupted || aggregateResult.Interrupted {
+			return reportOutcome(cmd, errInterrupted)
+		}
+
+		// Check the outcome against the scenario expectations
+		if params.scenario != nil && params.runs == 1 {
+			if err := params.scenario.Verify(simulationResult, earthMap.Cities()); err != nil {
+				cmd.SilenceUsage = true
+
+				return err
+			}
+
+			logger.Info("The scenario expectations are met")
+		}
+
+		logger.Info("Invasion completed successfully!")
+
+		if params.runs == 1 && totalCities > 0 && simulationResult.CitiesDestroyed == totalCities {
+			return reportOutcome(cmd, errAllDestroyed)
+		}
+
+		return nil
+	}
+
+	go func() {
+		defer close(runComplete)
+
+		runErr = finishRun()
+	}()
+
+	// Wait for either the run to complete,
 	// or the user to exit
-	select {
-	// Get the system-wide signal handler
-	case <-getTerminationSignalCh():
-		// Shut down the simulation
-		cancelSimulation()
-	// Wait for the simulation to complete
-	case <-simulationComplete:
+	signalCh := terminationSignalCh()
+	defer signal.Stop(signalCh)
+
+	if waitForShutdown(signalCh, runComplete, cancelSimulation, params.forceExit, logger) {
+		// Let the next signal terminate the program, in case the best-effort
+		// flush of the logs hangs as well. The run is not waited for, so the
+		// output is left incomplete if the exit is forced while it's written
+		signal.Stop(signalCh)
+
+		return reportOutcome(cmd, errForcedExit)
 	}
 
-	// Wait for the simulation to gracefully exit
-	wg.Wait()
+	return runErr
+}
+
+// reportOutcome returns the invasion outcome as the command error,
+// which is not printed, since the outcome is not a failure
+func reportOutcome(cmd *cobra.Command, outcome *outcomeError) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
 
-	// Set up the output writer
-	writer, err := getOutputWriter()
+	return outcome
+}
+
+// loadMap initializes the Earth map using the map file
+func loadMap(mapPath string, earthMap *game.EarthMap) error {
+	// Create an instance of the file reader
+	fileReader, err := stream.NewFileReader(mapPath)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to create a file reader, %w", err)
+	}
+
+	defer func() {
+		_ = fileReader.Close()
+	}()
+
+	return initMap(fileReader, earthMap)
+}
+
+// initMap initializes the Earth map using the map reader
+func initMap(reader stream.InputReader, earthMap *game.EarthMap) error {
+	if err := earthMap.InitMap(reader); err != nil {
+		return fmt.Errorf("unable to initialize the map, %w", err)
+	}
+
+	return nil
+}
+
+// getInputReader returns the map reader for the input format, reading the map
+// from the file (decompressed if gzip compressed), the http(s) URL or the standard input
+func getInputReader(cmd *cobra.Command, mapPath string, format stream.Format) (stream.InputReader, error) {
+	// The standard input is wrapped, so it's not closed along with the map reader
+	var source io.Reader = struct{ io.Reader }{cmd.InOrStdin()}
+
+	switch {
+	case mapPath == stdinPath:
+	case stream.IsHTTPURL(mapPath):
+		body, err := stream.OpenURL(cmd.Context(), mapPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to download the map, %w", err)
+		}
+
+		source = body
+	default:
+		mapFile, err := stream.OpenMapFile(mapPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the map file, %w", err)
+		}
+
+		source = mapFile
+	}
+
+	reader, err := stream.NewFormatReader(source, format)
+	if err != nil {
+		if closer, ok := source.(io.Closer); ok {
+			_ = closer.Close()
+		}
+
+		return nil, fmt.Errorf("unable to read the %s map, %w", format, err)
+	}
+
+	return reader, nil
+}
+
+// newDestructionAnnouncer creates a destruction listener that announces
+// each destroyed city to the output
+func newDestructionAnnouncer(output io.Writer) func(game.Destruction) {
+	var mux sync.Mutex
+
+	return func(destruction game.Destruction) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		_, _ = fmt.Fprintln(output, destructionMessage(destruction))
+	}
+}
+
+// writeSurvivors writes the names of the surviving cities
+// to the output writer, one per line
+func writeSurvivors(writer stream.OutputWriter, survivors []string) error {
+	for _, survivor := range survivors {
+		if err := writer.Write(fmt.Sprintf("%s\n", survivor)); err != nil {
+			return fmt.Errorf("unable to write to output stream, %w", err)
+		}
 	}
 
-	// Write the invasion output to the file
-	if err := earthMap.WriteOutput(writer); err != nil {
+	return writer.Flush()
+}
+
+// runHeader returns the header line, which separates
+// the output of a single run in the appended output file.
+// The program version is stamped in, so each run can be traced back to its build
+func runHeader(startTime time.Time, seed int64) string {
+	return fmt.Sprintf(
+		"# Run at %s with seed %d by alien-invasion %s\n",
+		startTime.UTC().Format(time.RFC3339),
+		seed,
+		version.Version,
+	)
+}
+
+// scenarioHeader returns the comment line naming the scenario
+// of the run, along with the scenario hash
+func scenarioHeader(s *scenario.Scenario) string {
+	return fmt.Sprintf("# Scenario %s (sha256 %s)\n", s.Name, s.Hash)
+}
+
+// writeOutput writes the output using the write callback, and closes the output writer.
+// Closing the writer flushes the buffered output (or renames the output file into place),
+// so a failure to close it means the output may be lost, and is returned as well
+func writeOutput(writer stream.OutputWriter, write func(stream.OutputWriter) error) error {
+	if err := write(writer); err != nil {
+		_ = writer.Close()
+
 		return fmt.Errorf("unable to write output to file, %w", err)
 	}
 
-	logger.Info("Invasion completed successfully!")
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("unable to close the output, %w", err)
+	}
 
 	return nil
 }
 
-// getOutputWriter returns the appropriate output writer
-// based on user preferences
-func getOutputWriter() (stream.OutputWriter, error) {
+// writeChecksumFile writes the checksum of the output to the checksum file, in the
+// coreutils format (<checksum>  <file name>), so it can be checked with sha256sum -c.
+// The standard output is named "-", like in the coreutils
+func writeChecksumFile(path, sum, outputPath string) error {
+	name := outputPath
+	if name == "" {
+		name = "-"
+	}
+
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("%s  %s\n", sum, name)), 0o644); err != nil {
+		return fmt.Errorf("unable to write the checksum file, %w", err)
+	}
+
+	return nil
+}
+
+// getDestinationWriter returns the writer of the output destination
+// (the output file, the output URL or the standard output), which writes the output as is
+func getDestinationWriter(
+	cmd *cobra.Command,
+	outputPath string,
+	fileOpts stream.FileWriterOptions,
+) (stream.OutputWriter, error) {
 	var (
 		err error
 
-		writer = stream.NewConsoleWriter()
+		writer = stream.NewConsoleWriterTo(cmd.OutOrStdout())
 	)
 
-	if params.outputPath != "" {
-		// Output file is set, make sure it is valid
-		writer, err = stream.NewFileWriter(params.outputPath)
+	switch {
+	case stream.IsHTTPURL(outputPath):
+		// Output URL is set, the output is POSTed to it on flush
+		writer, err = stream.NewHTTPWriter(outputPath, stream.WithHTTPContext(cmd.Context()))
 
 		if err != nil {
-			return nil, fmt.Errorf("unable to create an output file, %w", err)
+			return nil, fmt.Errorf("unable to create an output request, %w", err)
+		}
+	case outputPath != "":
+		// Output file is set, make sure it is valid. The file options
+		// pick whether the output replaces the file or is added to its end
+		writer, err = stream.NewFileWriterOptions(outputPath, fileOpts)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the output file, %w", err)
 		}
 	}
 
 	return writer, nil
 }
 
+// getAnnouncementWriter returns the writer of the destruction announcements and summary.
+// A map written to the standard output in a machine-readable format is kept
+// on its own, so the announcements are written to the standard error instead
+func getAnnouncementWriter(cmd *cobra.Command) io.Writer {
+	if params.outputPath == "" && params.outputFormat != stream.FormatText {
+		return cmd.ErrOrStderr()
+	}
+
+	return cmd.OutOrStdout()
+}
+
+// newLogger creates the program logger based on user preferences
+func newLogger(output io.Writer) hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:       "alien-invasion",
+		Level:      hclog.LevelFromString(params.logLevel),
+		Output:     output,
+		JSONFormat: params.logFormat == logFormatJSON,
+	})
+}
+
+// getLogOutput returns the log output destination based on user preferences,
+// along with a callback for closing it. A log file is optionally mirrored
+// to the standard error output
+func getLogOutput(cmd *cobra.Command, destination string, alsoStderr bool) (io.Writer, func() error, error) {
+	noopClose := func() error {
+		return nil
+	}
+
+	switch destination {
+	case logOutputStdout:
+		return cmd.OutOrStdout(), noopClose, nil
+	case logOutputStderr, "":
+		return cmd.ErrOrStderr(), noopClose, nil
+	}
+
+	// The destination is a file path, logs from successive runs are appended
+	logFile, err := openLogFile(destination)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open the log file, %w", err)
+	}
+
+	if alsoStderr {
+		return io.MultiWriter(logFile, cmd.ErrOrStderr()), closeLogFile(logFile), nil
+	}
+
+	return logFile, closeLogFile(logFile), nil
+}
+
+// isLogFile checks if the log output destination is a log file path
+func isLogFile(destination string) bool {
+	return destination != logOutputStdout && destination != logOutputStderr && destination != ""
+}
+
+// openLogFile opens the log file for appending, creating it if needed.
+// Appending keeps the file usable with external log rotation
+func openLogFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+}
+
+// closeLogFile returns the callback that flushes the
+// log file to the disk, and closes it
+func closeLogFile(logFile *os.File) func() error {
+	return func() error {
+		if err := logFile.Sync(); err != nil {
+			_ = logFile.Close()
+
+			return fmt.Errorf("unable to flush the log file, %w", err)
+		}
+
+		return logFile.Close()
+	}
+}
+
+// logInterruptSummary logs the outcome of the invasion interrupted by a termination signal,
+// before the output is written
+func logInterruptSummary(logger hclog.Logger, result game.SimulationResult, aggregate game.AggregateResult) {
+	if params.runs > 1 {
+		logger.Warn(
+			fmt.Sprintf(
+				"Invasion runs interrupted, with %d of %d runs completed",
+				aggregate.Runs,
+				params.runs,
+			),
+		)
+
+		return
+	}
+
+	logger.Warn(
+		fmt.Sprintf(
+			"Invasion interrupted, with %d aliens still alive and %d cities destroyed so far",
+			result.SurvivingAliens,
+			result.CitiesDestroyed,
+		),
+	)
+}
+
 // getTerminationSignalCh returns a listen channel for
 // system-wide stop signals
-func getTerminationSignalCh() <-chan os.Signal {
+func getTerminationSignalCh() chan os.Signal {
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(
 		signalCh,
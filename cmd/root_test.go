@@ -0,0 +1,2007 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+	"github.com/zivkovicmilos/alien-invasion/version"
+)
+
+// writeTempMap writes the given city lines to a temporary map file,
+// and returns its path
+func writeTempMap(t *testing.T, lines ...string) string {
+	t.Helper()
+
+	mapPath := filepath.Join(t.TempDir(), "map.txt")
+
+	if err := os.WriteFile(mapPath, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	return mapPath
+}
+
+// executeRootCommand runs the root command with the given arguments,
+// and returns the captured standard and error outputs
+func executeRootCommand(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	var (
+		stdout bytes.Buffer
+		stderr bytes.Buffer
+
+		rootCmd = NewRootCommand().baseCmd
+	)
+
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs(args)
+
+	err := rootCmd.Execute()
+
+	return stdout.String(), stderr.String(), err
+}
+
+// TestRoot_LogOutput makes sure the logs land
+// in the chosen log output destination
+func TestRoot_LogOutput(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	t.Run("log file", func(t *testing.T) {
+		logPath := filepath.Join(t.TempDir(), "invasion.log")
+
+		stdout, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-output", logPath,
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		// Make sure the logs are not present on the console,
+		// only the invasion summary
+		assert.Equal(t, "A total of 0 cities were destroyed\n", stdout)
+		assert.Empty(t, stderr)
+
+		logs, err := os.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("unable to read log file, %v", err)
+		}
+
+		assert.Contains(t, string(logs), "Map initialized with 2 cities")
+		assert.Contains(t, string(logs), "Invasion completed successfully!")
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		stdout, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-output", logOutputStdout,
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Contains(t, stdout, "Map initialized with 2 cities")
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		stdout, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Equal(t, "A total of 0 cities were destroyed\n", stdout)
+		assert.Contains(t, stderr, "Map initialized with 2 cities")
+	})
+
+	t.Run("unwritable log file", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-output", filepath.Join(t.TempDir(), "missing", "invasion.log"),
+		)
+
+		assert.ErrorContains(t, err, "unable to open the log file")
+	})
+}
+
+// TestRoot_LogFile makes sure the logs land in the log file,
+// optionally mirrored to the standard error output
+func TestRoot_LogFile(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	readLogs := func(t *testing.T, logPath string) string {
+		t.Helper()
+
+		logs, err := os.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("unable to read log file, %v", err)
+		}
+
+		return string(logs)
+	}
+
+	t.Run("log file", func(t *testing.T) {
+		logPath := filepath.Join(t.TempDir(), "invasion.log")
+
+		for run := 0; run < 2; run++ {
+			stdout, stderr, err := executeRootCommand(
+				t,
+				"1",
+				"--map-path", mapPath,
+				"--output-path", outputPath,
+				"--log-file", logPath,
+			)
+			if err != nil {
+				t.Fatalf("unable to execute command, %v", err)
+			}
+
+			assert.Equal(t, "A total of 0 cities were destroyed\n", stdout)
+			assert.Empty(t, stderr)
+		}
+
+		// The logs of the second run are appended to the logs of the first one
+		logs := readLogs(t, logPath)
+
+		assert.Equal(t, 2, strings.Count(logs, "Map initialized with 2 cities"))
+		assert.Equal(t, 2, strings.Count(logs, "Invasion completed successfully!"))
+
+		// The map is still written to the output path
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		assert.NotContains(t, string(output), "Map initialized")
+	})
+
+	// The standard error output is an addition to a log file set with either flag
+	for _, logFlag := range []string{logFileFlag, logOutputFlag} {
+		logFlag := logFlag
+
+		t.Run(fmt.Sprintf("--%s and stderr", logFlag), func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "invasion.log")
+
+			_, stderr, err := executeRootCommand(
+				t,
+				"1",
+				"--map-path", mapPath,
+				"--output-path", outputPath,
+				"--"+logFlag, logPath,
+				"--log-also-stderr",
+			)
+			if err != nil {
+				t.Fatalf("unable to execute command, %v", err)
+			}
+
+			assert.Contains(t, readLogs(t, logPath), "Map initialized with 2 cities")
+			assert.Contains(t, stderr, "Map initialized with 2 cities")
+		})
+	}
+
+	t.Run("same log file and log output", func(t *testing.T) {
+		logPath := filepath.Join(t.TempDir(), "invasion.log")
+
+		_, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-file", logPath,
+			"--log-output", logPath,
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Equal(t, 1, strings.Count(readLogs(t, logPath), "Map initialized with 2 cities"))
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("unwritable log file", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-file", filepath.Join(t.TempDir(), "missing", "invasion.log"),
+		)
+
+		assert.ErrorContains(t, err, "unable to open the log file")
+	})
+
+	t.Run("log output conflict", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--log-file", filepath.Join(t.TempDir(), "invasion.log"),
+			"--log-output", logOutputStdout,
+		)
+
+		assert.ErrorIs(t, err, errLogFileConflict)
+	})
+
+	t.Run("stderr without log file", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--log-also-stderr",
+		)
+
+		assert.ErrorIs(t, err, errLogAlsoStderr)
+	})
+}
+
+// TestRoot_LogFormat makes sure the logs are emitted
+// in the chosen log format
+func TestRoot_LogFormat(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	t.Run("json", func(t *testing.T) {
+		_, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-format", logFormatJSON,
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(stderr), "\n")
+		if len(lines) == 0 {
+			t.Fatal("no log lines emitted")
+		}
+
+		// Make sure every log line is a valid JSON object
+		for _, line := range lines {
+			var logLine map[string]interface{}
+
+			if err := json.Unmarshal([]byte(line), &logLine); err != nil {
+				t.Fatalf("log line is not valid JSON, %s", line)
+			}
+
+			assert.Contains(t, logLine, "@message")
+			assert.Contains(t, logLine, "@level")
+		}
+
+		assert.Contains(t, stderr, `"@message":"Map initialized with 2 cities"`)
+	})
+
+	t.Run("json shorthand", func(t *testing.T) {
+		// Both aliens land in the only city, and destroy it
+		stdout, stderr, err := executeRootCommand(
+			t,
+			"2",
+			"--map-path", writeTempMap(t, "Foo"),
+			"--output-path", outputPath,
+			"--json-log",
+		)
+
+		// The destroyed map is an outcome, not a failure
+		assert.ErrorIs(t, err, errAllDestroyed)
+
+		var logLine map[string]interface{}
+
+		firstLine, _, _ := strings.Cut(stderr, "\n")
+		if err := json.Unmarshal([]byte(firstLine), &logLine); err != nil {
+			t.Fatalf("log line is not valid JSON, %s", firstLine)
+		}
+
+		assert.Equal(t, "info", logLine["@level"])
+		assert.Equal(t, "alien-invasion", logLine["@module"])
+		assert.Contains(t, logLine, "@timestamp")
+		assert.Contains(t, logLine, "@message")
+
+		// The destruction announcement and summary remain plain data
+		assert.Equal(
+			t,
+			"Foo has been destroyed by alien 0 and alien 1!\n"+
+				"A total of 1 cities were destroyed\n",
+			stdout,
+		)
+	})
+
+	t.Run("json shorthand with the json format", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-format", logFormatJSON,
+			"--json-log",
+		)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("json shorthand with the text format", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-format", logFormatText,
+			"--json-log",
+		)
+
+		assert.ErrorIs(t, err, errJSONLogConflict)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		_, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Contains(t, stderr, "[INFO]  alien-invasion.earth-map: Map initialized with 2 cities")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-format", "xml",
+		)
+
+		assert.ErrorIs(t, err, errInvalidLogFormat)
+	})
+}
+
+// TestRoot_ListSurvivors makes sure only the sorted surviving
+// city names are written when listing survivors
+func TestRoot_ListSurvivors(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo", "Baz")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	_, _, err := executeRootCommand(
+		t,
+		"1",
+		"--map-path", mapPath,
+		"--output-path", outputPath,
+		"--list-survivors",
+	)
+	if err != nil {
+		t.Fatalf("unable to execute command, %v", err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	// A single alien can't destroy any city
+	assert.Equal(t, "Bar\nBaz\nFoo\n", string(output))
+	assert.NotContains(t, string(output), "=")
+}
+
+// TestRoot_Limits makes sure the map size and alien count
+// limits are enforced with distinct errors
+func TestRoot_Limits(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo", "Baz")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	t.Run("max cities", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--max-cities", "2",
+		)
+
+		assert.ErrorIs(t, err, game.ErrMapTooLarge)
+		assert.ErrorContains(t, err, "unable to initialize the map")
+	})
+
+	t.Run("max aliens", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"5",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--max-aliens", "4",
+		)
+
+		// The alien count is rejected before the map is loaded
+		assert.ErrorIs(t, err, game.ErrTooManyAliens)
+		assert.ErrorContains(t, err, "--max-aliens")
+	})
+}
+
+// TestRoot_AlienNumber makes sure only positive alien counts
+// within the max alien count are accepted
+func TestRoot_AlienNumber(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	testTable := []struct {
+		name          string
+		numAliens     string
+		expectedError error
+	}{
+		{
+			"negative count",
+			"-5",
+			errInvalidAlienNumber,
+		},
+		{
+			"zero count",
+			"0",
+			errInvalidAlienNumber,
+		},
+		{
+			"non-integer count",
+			"abc",
+			errInvalidAlienNumber,
+		},
+		{
+			"overflowing count",
+			"99999999999999999999",
+			errInvalidAlienNumber,
+		},
+		{
+			"huge count",
+			"1000000000000",
+			game.ErrTooManyAliens,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			// The arguments are terminated, so negative counts are not parsed as flags
+			_, _, err := executeRootCommand(
+				t,
+				"--map-path", mapPath,
+				"--",
+				testCase.numAliens,
+			)
+
+			assert.ErrorIs(t, err, testCase.expectedError)
+		})
+	}
+}
+
+// TestRoot_AliensFlag makes sure the number of aliens is accepted
+// either as the positional argument, or the aliens flag, but not both
+func TestRoot_AliensFlag(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	testTable := []struct {
+		name          string
+		args          []string
+		expectedError error
+	}{
+		{
+			"flag only",
+			[]string{"--aliens", "1"},
+			nil,
+		},
+		{
+			"positional only",
+			[]string{"1"},
+			nil,
+		},
+		{
+			"both",
+			[]string{"1", "--aliens", "1"},
+			errAlienNumberTwice,
+		},
+		{
+			"neither",
+			[]string{},
+			errAlienNumberMissing,
+		},
+		{
+			"invalid flag",
+			[]string{"--aliens=-3"},
+			errInvalidAlienNumber,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			stdout, _, err := executeRootCommand(
+				t,
+				append(testCase.args, "--map-path", mapPath)...,
+			)
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Contains(t, stdout, "A total of 0 cities were destroyed")
+		})
+	}
+}
+
+// TestRoot_Seed makes sure the seed is parsed and forwarded
+// to the Earth map, or generated and reported if omitted
+func TestRoot_Seed(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	// Capture the constructed map
+	var earthMap *game.EarthMap
+
+	newEarthMap = func(log hclog.Logger, opts ...game.Option) *game.EarthMap {
+		earthMap = game.NewEarthMap(log, opts...)
+
+		return earthMap
+	}
+
+	t.Cleanup(func() {
+		newEarthMap = game.NewEarthMap
+	})
+
+	t.Run("provided seed", func(t *testing.T) {
+		_, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--seed", "12345",
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12345), earthMap.Seed())
+		assert.Contains(t, stderr, "Using provided seed 12345")
+	})
+
+	t.Run("generated seed", func(t *testing.T) {
+		_, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+		)
+
+		assert.NoError(t, err)
+		assert.Contains(
+			t,
+			stderr,
+			fmt.Sprintf("Using random seed %d", earthMap.Seed()),
+		)
+	})
+
+	t.Run("invalid seed", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--seed", "abc",
+		)
+
+		assert.ErrorContains(t, err, "invalid argument")
+	})
+}
+
+// TestRoot_MaxMoves makes sure the alien move cap is
+// validated, and forwarded to the simulation
+func TestRoot_MaxMoves(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo east=Baz", "Baz west=Bar")
+
+	t.Run("valid max moves", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+		_, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--max-moves", "5",
+		)
+
+		assert.NoError(t, err)
+		assert.Contains(t, stderr, "Using max moves per alien 5")
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		assert.NotEmpty(t, string(output))
+	})
+
+	for _, maxMoves := range []string{"0", "-1"} {
+		maxMoves := maxMoves
+
+		t.Run("invalid max moves "+maxMoves, func(t *testing.T) {
+			_, _, err := executeRootCommand(
+				t,
+				"1",
+				"--map-path", mapPath,
+				"--max-moves", maxMoves,
+			)
+
+			assert.ErrorIs(t, err, errInvalidMaxMoves)
+		})
+	}
+}
+
+// TestRoot_MaxLineSize makes sure map lines longer than bufio.MaxScanTokenSize
+// are read whole, and lines longer than the max line size fail the map reading
+func TestRoot_MaxLineSize(t *testing.T) {
+	var (
+		longName = strings.Repeat("B", 2*bufio.MaxScanTokenSize)
+		mapPath  = writeTempMap(t, "Foo north="+longName, longName+" south=Foo", "Baz")
+	)
+
+	t.Run("long line", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+		_, _, err := executeRootCommand(t, "1", "--map-path", mapPath, "--output-path", outputPath)
+
+		assert.NoError(t, err)
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		// The cities after the long lines are read as well
+		assert.Contains(t, string(output), "Foo north="+longName+"\n")
+		assert.Contains(t, string(output), "Baz\n")
+	})
+
+	t.Run("line over the max", func(t *testing.T) {
+		_, _, err := executeRootCommand(t, "1", "--map-path", mapPath, "--max-line-size", "1024")
+
+		assert.ErrorIs(t, err, bufio.ErrTooLong)
+	})
+
+	t.Run("invalid max line size", func(t *testing.T) {
+		_, _, err := executeRootCommand(t, "1", "--map-path", mapPath, "--max-line-size", "0")
+
+		assert.ErrorIs(t, err, errInvalidMaxLineSize)
+	})
+}
+
+// TestRoot_Timeout makes sure a simulation cut short by the timeout
+// still writes the output, and reports the truncation
+func TestRoot_Timeout(t *testing.T) {
+	var (
+		mapPath    = filepath.Join(t.TempDir(), "map.txt")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	// Write out a large generated map
+	writer, err := stream.NewFileWriter(mapPath)
+	if err != nil {
+		t.Fatalf("unable to create map file, %v", err)
+	}
+
+	if err := game.GenerateGridMap(100, 100).WriteOutput(writer); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unable to close map file, %v", err)
+	}
+
+	_, stderr, err := executeRootCommand(
+		t,
+		"1000",
+		"--map-path", mapPath,
+		"--output-path", outputPath,
+		"--timeout", "1ms",
+	)
+
+	// The output is still written for the truncated invasion
+	assert.ErrorIs(t, err, errInterrupted)
+	assert.Contains(t, stderr, "Invasion truncated by the 1ms timeout")
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	assert.NotEmpty(t, string(output))
+}
+
+// slowWriter is an output writer that takes a while to write each line
+type slowWriter struct {
+	*stream.SliceWriter
+
+	delay time.Duration
+}
+
+func (sw *slowWriter) Write(s string) error {
+	time.Sleep(sw.delay)
+
+	return sw.SliceWriter.Write(s)
+}
+
+// TestRoot_Timeout_SlowOutput makes sure the invasion completed within the timeout
+// is not reported as truncated, if writing the output outlasts the timeout
+func TestRoot_Timeout_SlowOutput(t *testing.T) {
+	openOutputWriter = func(
+		*cobra.Command,
+		string,
+		stream.FileWriterOptions,
+	) (stream.OutputWriter, error) {
+		return &slowWriter{
+			SliceWriter: stream.NewSliceWriter(),
+			delay:       300 * time.Millisecond,
+		}, nil
+	}
+
+	t.Cleanup(func() {
+		openOutputWriter = getDestinationWriter
+	})
+
+	// A single alien with a single move finishes right away, and the output takes longer than the timeout
+	_, stderr, err := executeRootCommand(
+		t,
+		"1",
+		"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+		"--max-moves", "1",
+		"--timeout", "200ms",
+	)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, stderr, "Invasion truncated")
+}
+
+// blockingWriter is an output writer that hangs on the first write
+type blockingWriter struct {
+	*stream.SliceWriter
+}
+
+func (bw *blockingWriter) Write(string) error {
+	select {}
+}
+
+// TestRoot_ForcedExit_HangingOutput makes sure the termination signals
+// can force the exit while the output is written
+func TestRoot_ForcedExit_HangingOutput(t *testing.T) {
+	var (
+		// The writer is never released, so the run is left hanging
+		writer = &blockingWriter{
+			SliceWriter: stream.NewSliceWriter(),
+		}
+
+		signalCh = make(chan os.Signal, 2)
+	)
+
+	openOutputWriter = func(
+		*cobra.Command,
+		string,
+		stream.FileWriterOptions,
+	) (stream.OutputWriter, error) {
+		// Both signals are delivered once the output is being written
+		signalCh <- syscall.SIGINT
+		signalCh <- syscall.SIGINT
+
+		return writer, nil
+	}
+
+	terminationSignalCh = func() chan os.Signal {
+		return signalCh
+	}
+
+	t.Cleanup(func() {
+		openOutputWriter = getDestinationWriter
+		terminationSignalCh = getTerminationSignalCh
+	})
+
+	_, stderr, err := executeRootCommand(
+		t,
+		"1",
+		"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+	)
+
+	assert.ErrorIs(t, err, errForcedExit)
+	assert.Contains(t, stderr, "Caught the interrupt signal again, forcing the exit")
+}
+
+// TestRoot_InterruptSummary makes sure the invasion interrupted by a termination signal
+// sums up how far it got, and still writes the output
+func TestRoot_InterruptSummary(t *testing.T) {
+	var (
+		mapPath    = filepath.Join(t.TempDir(), "map.txt")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	// Write out a large generated map, so the
+	// invasion is still running when the signal arrives
+	writer, err := stream.NewFileWriter(mapPath)
+	if err != nil {
+		t.Fatalf("unable to create map file, %v", err)
+	}
+
+	if err := game.GenerateGridMap(100, 100).WriteOutput(writer); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unable to close map file, %v", err)
+	}
+
+	// The termination signal is delivered as soon as the invasion starts
+	terminationSignalCh = func() chan os.Signal {
+		signalCh := make(chan os.Signal, 1)
+		signalCh <- syscall.SIGINT
+
+		return signalCh
+	}
+
+	t.Cleanup(func() {
+		terminationSignalCh = getTerminationSignalCh
+	})
+
+	t.Run("single run", func(t *testing.T) {
+		_, stderr, err := executeRootCommand(t, "1000", "--map-path", mapPath, "--output-path", outputPath)
+
+		assert.ErrorIs(t, err, errInterrupted)
+		assert.Regexp(t, `Invasion interrupted, with \d+ aliens still alive and \d+ cities destroyed so far`, stderr)
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		assert.NotEmpty(t, string(output))
+	})
+
+	t.Run("multiple runs", func(t *testing.T) {
+		_, stderr, err := executeRootCommand(
+			t,
+			"1000",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--runs", "50",
+		)
+
+		assert.ErrorIs(t, err, errInterrupted)
+		assert.Regexp(t, `Invasion runs interrupted, with \d+ of 50 runs completed`, stderr)
+	})
+}
+
+// TestRoot_Runs makes sure multiple simulation runs
+// output the aggregate statistics
+func TestRoot_Runs(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo", "Baz")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	t.Run("aggregate output", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--runs", "3",
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		// A single alien can't destroy any city
+		assert.Equal(
+			t,
+			"Runs: 3\n"+
+				"Average cities destroyed: 0.00\n"+
+				"Cities destroyed variance: 0.00\n"+
+				"Average surviving aliens: 1.00\n"+
+				"Cities destroyed distribution:\n"+
+				"0 3\n"+
+				"City survival frequency:\n"+
+				"Bar 100.00%\n"+
+				"Baz 100.00%\n"+
+				"Foo 100.00%\n",
+			string(output),
+		)
+	})
+
+	t.Run("invalid runs", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--runs", "0",
+		)
+
+		assert.ErrorIs(t, err, errInvalidRuns)
+	})
+
+	t.Run("invalid parallel", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--runs", "3",
+			"--parallel", "0",
+		)
+
+		assert.ErrorIs(t, err, errInvalidParallel)
+	})
+
+	t.Run("unsupported aggregate format", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--runs", "3",
+			"--output-format", string(stream.FormatDOT),
+		)
+
+		assert.ErrorIs(t, err, errFormatUnsupported)
+	})
+}
+
+// TestRoot_RunsFormat makes sure the aggregate statistics are
+// written in the format inferred from the output path, regardless of the parallelism
+func TestRoot_RunsFormat(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	testTable := []struct {
+		name      string
+		extension string
+		parallel  string
+		expected  string
+	}{
+		{
+			"json aggregate",
+			".json",
+			"1",
+			`{
+  "runs": 4,
+  "averageDestroyed": 0,
+  "destroyedVariance": 0,
+  "destroyedDistribution": {
+    "0": 4
+  },
+  "averageSurvivingAliens": 1,
+  "survivalRate": {
+    "Bar": 1,
+    "Foo": 1
+  },
+  "interrupted": false
+}
+`,
+		},
+		{
+			"csv aggregate",
+			".csv",
+			"2",
+			"metric,key,value\n" +
+				"runs,,4\n" +
+				"average_destroyed,,0\n" +
+				"destroyed_variance,,0\n" +
+				"average_surviving_aliens,,1\n" +
+				"destroyed_distribution,0,4\n" +
+				"survival_rate,Bar,1\n" +
+				"survival_rate,Foo,1\n",
+		},
+		{
+			"dot extension falls back to text",
+			".dot",
+			"4",
+			"Runs: 4\n" +
+				"Average cities destroyed: 0.00\n" +
+				"Cities destroyed variance: 0.00\n" +
+				"Average surviving aliens: 1.00\n" +
+				"Cities destroyed distribution:\n" +
+				"0 4\n" +
+				"City survival frequency:\n" +
+				"Bar 100.00%\n" +
+				"Foo 100.00%\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "aggregate"+testCase.extension)
+
+			// A single alien can't destroy any city
+			_, _, err := executeRootCommand(
+				t,
+				"1",
+				"--map-path", mapPath,
+				"--output-path", outputPath,
+				"--runs", "4",
+				"--seed", "7",
+				"--parallel", testCase.parallel,
+			)
+			if err != nil {
+				t.Fatalf("unable to execute command, %v", err)
+			}
+
+			output, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("unable to read output file, %v", err)
+			}
+
+			assert.Equal(t, testCase.expected, string(output))
+		})
+	}
+}
+
+// TestRoot_LogLevel makes sure the log level is parsed
+// case-insensitively, and unknown levels are rejected
+func TestRoot_LogLevel(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	t.Run("lowercase level", func(t *testing.T) {
+		_, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-level", "error",
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("unknown level", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--log-level", "verbose",
+		)
+
+		assert.ErrorIs(t, err, errInvalidLogLevel)
+	})
+}
+
+// TestRoot_Quiet makes sure quiet mode suppresses the logs,
+// while keeping the program output
+func TestRoot_Quiet(t *testing.T) {
+	t.Run("map output only", func(t *testing.T) {
+		stdout, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+			"--quiet",
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Equal(t, "Bar south=Foo\nFoo north=Bar\n", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("destruction announcements", func(t *testing.T) {
+		// Both aliens land in the only city, and destroy it
+		stdout, stderr, err := executeRootCommand(
+			t,
+			"2",
+			"--map-path", writeTempMap(t, "Foo"),
+			"--quiet",
+			"--announce",
+		)
+
+		// The destroyed map is an outcome, not a failure
+		assert.ErrorIs(t, err, errAllDestroyed)
+
+		assert.Equal(t, "Foo has been destroyed by alien 0 and alien 1!\n", stdout)
+		assert.Empty(t, stderr)
+	})
+}
+
+// TestRoot_OutputStreams makes sure the program output lands on
+// standard output, and the diagnostics on standard error
+func TestRoot_OutputStreams(t *testing.T) {
+	stdout, stderr, err := executeRootCommand(
+		t,
+		"1",
+		"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+	)
+	if err != nil {
+		t.Fatalf("unable to execute command, %v", err)
+	}
+
+	assert.Equal(
+		t,
+		"A total of 0 cities were destroyed\n"+
+			"Bar south=Foo\n"+
+			"Foo north=Bar\n",
+		stdout,
+	)
+
+	assert.Contains(t, stderr, "Map initialized with 2 cities")
+	assert.Contains(t, stderr, "Invasion completed successfully!")
+	assert.NotContains(t, stderr, "Foo north=Bar")
+}
+
+// TestRoot_OutputURL makes sure the map output
+// is POSTed to the output URL
+func TestRoot_OutputURL(t *testing.T) {
+	var (
+		bodies []string
+		mu     sync.Mutex
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	_, _, err := executeRootCommand(
+		t,
+		"1",
+		"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+		"--output-path", server.URL+"/maps",
+	)
+	if err != nil {
+		t.Fatalf("unable to execute command, %v", err)
+	}
+
+	assert.Equal(t, []string{"Bar south=Foo\nFoo north=Bar\n"}, bodies)
+}
+
+// TestRoot_AppendOutput makes sure successive runs are appended
+// to the output file, each after its own run header line
+func TestRoot_AppendOutput(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	for _, seed := range []string{"1", "2"} {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--seed", seed,
+			"--append-output",
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	if !assert.Len(t, lines, 6) {
+		return
+	}
+
+	// A single alien can't destroy any city, so both runs output the whole map
+	for i, seed := range []string{"1", "2"} {
+		run := lines[i*3 : i*3+3]
+
+		assert.True(t, strings.HasPrefix(run[0], "# Run at "))
+		assert.True(t, strings.HasSuffix(run[0], fmt.Sprintf(" with seed %s by alien-invasion %s", seed, version.Version)))
+		assert.Equal(t, []string{"Bar south=Foo", "Foo north=Bar"}, run[1:])
+	}
+
+	t.Run("missing output file", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--append-output",
+		)
+
+		assert.ErrorIs(t, err, errAppendWithoutFile)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", filepath.Join(t.TempDir(), "output.json"),
+			"--append-output",
+		)
+
+		assert.ErrorIs(t, err, errAppendUnsupported)
+	})
+}
+
+// TestRoot_NoClobber makes sure the output of a previous
+// run is never replaced with the no-clobber flag
+func TestRoot_NoClobber(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+		outputPath = filepath.Join(t.TempDir(), "output.txt")
+	)
+
+	_, _, err := executeRootCommand(
+		t,
+		"1",
+		"--map-path", mapPath,
+		"--output-path", outputPath,
+		"--no-clobber",
+	)
+	if err != nil {
+		t.Fatalf("unable to execute command, %v", err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	assert.Equal(t, "Bar south=Foo\nFoo north=Bar\n", string(output))
+
+	t.Run("existing output file", func(t *testing.T) {
+		if err := os.WriteFile(outputPath, []byte("Baz\n"), 0o600); err != nil {
+			t.Fatalf("unable to write output file, %v", err)
+		}
+
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--no-clobber",
+		)
+
+		assert.ErrorIs(t, err, errOutputExists)
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		assert.Equal(t, "Baz\n", string(output))
+	})
+
+	t.Run("missing output file", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--no-clobber",
+		)
+
+		assert.ErrorIs(t, err, errNoClobberNoFile)
+	})
+
+	t.Run("append output", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", filepath.Join(t.TempDir(), "output.txt"),
+			"--append-output",
+			"--no-clobber",
+		)
+
+		assert.ErrorIs(t, err, errNoClobberConflict)
+	})
+}
+
+// closingWriter is an in-memory output writer that
+// keeps track of being closed, and fails to close if set
+type closingWriter struct {
+	*stream.SliceWriter
+
+	closed   bool
+	closeErr error
+}
+
+func (cw *closingWriter) Close() error {
+	cw.closed = true
+
+	return cw.closeErr
+}
+
+// TestRoot_CloseOutput makes sure the output writer is closed
+// once the output is written, and a failure to close it is reported
+func TestRoot_CloseOutput(t *testing.T) {
+	errCloseFailed := errors.New("close failed")
+
+	testTable := []struct {
+		name        string
+		closeErr    error
+		expectedErr error
+	}{
+		{
+			"closed output",
+			nil,
+			nil,
+		},
+		{
+			"failed close",
+			errCloseFailed,
+			errCloseFailed,
+		},
+	}
+
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			writer := &closingWriter{
+				SliceWriter: stream.NewSliceWriter(),
+				closeErr:    testCase.closeErr,
+			}
+
+			openOutputWriter = func(
+				*cobra.Command,
+				string,
+				stream.FileWriterOptions,
+			) (stream.OutputWriter, error) {
+				return writer, nil
+			}
+
+			t.Cleanup(func() {
+				openOutputWriter = getDestinationWriter
+			})
+
+			_, _, err := executeRootCommand(t, "1", "--map-path", mapPath)
+
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+				assert.ErrorContains(t, err, "unable to close the output")
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.True(t, writer.closed)
+			assert.Equal(t, []string{"Bar south=Foo\n", "Foo north=Bar\n"}, writer.Lines())
+		})
+	}
+}
+
+// TestRoot_ChecksumFile makes sure the checksum of the output
+// is written to the checksum file, in the sha256sum format
+func TestRoot_ChecksumFile(t *testing.T) {
+	var (
+		mapPath      = writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+		checksumPath = filepath.Join(t.TempDir(), "output.sha256")
+	)
+
+	// The checksum of the output, where a single alien can't destroy any city
+	const expectedSum = "a3c19f11c4b7960de558e17a783068f48522ecacee50961d3a97d7cc1ff2ebd7"
+
+	readChecksum := func(t *testing.T) string {
+		t.Helper()
+
+		checksum, err := os.ReadFile(checksumPath)
+		if err != nil {
+			t.Fatalf("unable to read checksum file, %v", err)
+		}
+
+		return string(checksum)
+	}
+
+	t.Run("output file", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--checksum-file", checksumPath,
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Equal(t, fmt.Sprintf("%s  %s\n", expectedSum, outputPath), readChecksum(t))
+	})
+
+	t.Run("standard output", func(t *testing.T) {
+		stdout, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--checksum-file", checksumPath,
+			"--quiet",
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Equal(t, "Bar south=Foo\nFoo north=Bar\n", stdout)
+		assert.Equal(t, fmt.Sprintf("%s  -\n", expectedSum), readChecksum(t))
+	})
+
+	t.Run("output format", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "output.json")
+
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-path", outputPath,
+			"--checksum-file", checksumPath,
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		// The checksum covers the encoded output
+		sum := sha256.Sum256(output)
+
+		assert.Equal(t, fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), outputPath), readChecksum(t))
+	})
+}
+
+// TestRoot_OutputFormat makes sure the map output
+// is written in the chosen output format
+func TestRoot_OutputFormat(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	testTable := []struct {
+		name           string
+		format         stream.Format
+		expectedOutput string
+	}{
+		{
+			"text",
+			stream.FormatText,
+			"Bar south=Foo\nFoo north=Bar\n",
+		},
+		{
+			"json",
+			stream.FormatJSON,
+			`{
+  "cities": [
+    {
+      "name": "Bar",
+      "neighbors": {
+        "south": "Foo"
+      }
+    },
+    {
+      "name": "Foo",
+      "neighbors": {
+        "north": "Bar"
+      }
+    }
+  ]
+}
+`,
+		},
+		{
+			"dot",
+			stream.FormatDOT,
+			"graph earth {\n  \"Bar\" -- \"Foo\" [label=\"south\"];\n}\n",
+		},
+		{
+			"csv",
+			stream.FormatCSV,
+			"city,north,south,east,west\nBar,,Foo,,\nFoo,Bar,,,\n",
+		},
+		{
+			"mermaid",
+			stream.FormatMermaid,
+			"graph TD\n  city0[\"Bar\"]\n  city1[\"Foo\"]\n  city0 ---|south| city1\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// A single alien can't destroy any city
+			stdout, _, err := executeRootCommand(
+				t,
+				"1",
+				"--map-path", mapPath,
+				"--output-format", string(testCase.format),
+				"--quiet",
+			)
+			if err != nil {
+				t.Fatalf("unable to execute command, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedOutput, stdout)
+		})
+	}
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-format", "xml",
+		)
+
+		// The format is validated when the flag is parsed
+		assert.ErrorContains(t, err, errInvalidFormat.Error())
+		assert.ErrorContains(t, err, "text, json, dot, csv, mermaid")
+	})
+
+	t.Run("unsupported output", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-format", string(stream.FormatJSON),
+			"--list-survivors",
+		)
+
+		assert.ErrorIs(t, err, errFormatUnsupported)
+	})
+}
+
+// TestRoot_OutputFormat_MachineReadable makes sure a machine-readable map written
+// to the standard output is not mixed with the destruction announcements
+func TestRoot_OutputFormat_MachineReadable(t *testing.T) {
+	// The aliens can't leave the isolated cities, so a city is destroyed
+	mapPath := writeTempMap(t, "Foo", "Bar")
+
+	stdout, stderr, err := executeRootCommand(
+		t,
+		"3",
+		"--map-path", mapPath,
+		"--output-format", string(stream.FormatJSON),
+	)
+	if err != nil {
+		t.Fatalf("unable to execute command, %v", err)
+	}
+
+	var document map[string]interface{}
+
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &document))
+	assert.Contains(t, document, "cities")
+
+	// The announcements are written to the standard error instead
+	assert.Contains(t, stderr, "has been destroyed by alien")
+	assert.Contains(t, stderr, "A total of 1 cities were destroyed")
+}
+
+// TestRoot_Format makes sure the format flag selects the output format,
+// which is otherwise inferred from the output path extension
+func TestRoot_Format(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Bar south=Foo")
+
+	testTable := []struct {
+		name           string
+		outputFile     string
+		args           []string
+		expectedPrefix string
+	}{
+		{
+			"text by default",
+			"output.txt",
+			nil,
+			"Bar south=Foo\n",
+		},
+		{
+			"json extension",
+			"output.json",
+			nil,
+			"{\n  \"cities\": [",
+		},
+		{
+			"dot extension",
+			"output.dot",
+			nil,
+			"graph earth {\n",
+		},
+		{
+			"csv extension",
+			"output.csv",
+			nil,
+			"city,north,south,east,west\n",
+		},
+		{
+			"mermaid extension",
+			"output.mmd",
+			nil,
+			"graph TD\n",
+		},
+		{
+			"unknown extension",
+			"output.dat",
+			nil,
+			"Bar south=Foo\n",
+		},
+		{
+			"explicit format wins",
+			"output.json",
+			[]string{"--format", string(stream.FormatCSV)},
+			"city,north,south,east,west\n",
+		},
+		{
+			"matching output format",
+			"output.txt",
+			[]string{"--format", string(stream.FormatCSV), "--output-format", string(stream.FormatCSV)},
+			"city,north,south,east,west\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), testCase.outputFile)
+
+			args := append(
+				[]string{"1", "--map-path", mapPath, "--output-path", outputPath},
+				testCase.args...,
+			)
+
+			if _, _, err := executeRootCommand(t, args...); err != nil {
+				t.Fatalf("unable to execute command, %v", err)
+			}
+
+			output, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("unable to read output file, %v", err)
+			}
+
+			assert.True(
+				t,
+				strings.HasPrefix(string(output), testCase.expectedPrefix),
+				"unexpected output %q",
+				output,
+			)
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--format", "xml",
+		)
+
+		assert.ErrorContains(t, err, errInvalidFormat.Error())
+	})
+
+	t.Run("conflicting output format", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--output-format", string(stream.FormatCSV),
+			"--format", string(stream.FormatJSON),
+		)
+
+		assert.ErrorIs(t, err, errFormatConflict)
+	})
+}
+
+// TestRoot_InputFormat makes sure the map is parsed
+// using the chosen input format
+func TestRoot_InputFormat(t *testing.T) {
+	jsonMap := `{"cities": [{"name": "Foo", "neighbors": {"north": "Bar"}}, {"name": "Baz"}]}`
+
+	t.Run("json file", func(t *testing.T) {
+		// The extension doesn't matter, only the input format
+		mapPath := filepath.Join(t.TempDir(), "map.txt")
+		if err := os.WriteFile(mapPath, []byte(jsonMap), 0o600); err != nil {
+			t.Fatalf("unable to write map file, %v", err)
+		}
+
+		stdout, stderr, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--input-format", string(stream.FormatJSON),
+			"--quiet",
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		// A single alien can't destroy any city
+		assert.Equal(t, "Bar south=Foo\nBaz\nFoo north=Bar\n", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("json standard input", func(t *testing.T) {
+		var (
+			stdout bytes.Buffer
+
+			rootCmd = NewRootCommand().baseCmd
+		)
+
+		rootCmd.SetIn(strings.NewReader(jsonMap))
+		rootCmd.SetOut(&stdout)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs([]string{
+			"1",
+			"--map-path", stdinPath,
+			"--input-format", string(stream.FormatJSON),
+			"--quiet",
+		})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Equal(t, "Bar south=Foo\nBaz\nFoo north=Bar\n", stdout.String())
+	})
+
+	t.Run("malformed map", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", writeTempMap(t, "Foo north=Bar"),
+			"--input-format", string(stream.FormatJSON),
+		)
+
+		assert.ErrorContains(t, err, "unable to read the json map")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", writeTempMap(t, "Foo north=Bar"),
+			"--input-format", string(stream.FormatMermaid),
+		)
+
+		assert.ErrorContains(t, err, errInvalidFormat.Error())
+		assert.ErrorContains(t, err, "text, json, dot, csv")
+	})
+
+	t.Run("detected format", func(t *testing.T) {
+		mapPath := filepath.Join(t.TempDir(), "map.dot")
+		if err := os.WriteFile(mapPath, []byte("graph earth {\n  \"Foo\" -- \"Bar\" [label=\"north\"];\n}\n"), 0o600); err != nil {
+			t.Fatalf("unable to write map file, %v", err)
+		}
+
+		stdout, _, err := executeRootCommand(t, "1", "--map-path", mapPath, "--quiet")
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Equal(t, "Bar south=Foo\nFoo north=Bar\n", stdout)
+	})
+
+	t.Run("explicit format overrides the extension", func(t *testing.T) {
+		mapPath := filepath.Join(t.TempDir(), "map.dat")
+		if err := os.WriteFile(mapPath, []byte("city,north\nFoo,Bar\n"), 0o600); err != nil {
+			t.Fatalf("unable to write map file, %v", err)
+		}
+
+		stdout, _, err := executeRootCommand(
+			t,
+			"1",
+			"--map-path", mapPath,
+			"--input-format", string(stream.FormatCSV),
+			"--quiet",
+		)
+		if err != nil {
+			t.Fatalf("unable to execute command, %v", err)
+		}
+
+		assert.Equal(t, "Bar south=Foo\nFoo north=Bar\n", stdout)
+	})
+}
+
+// trackedStrategy is a movement strategy that takes the first road,
+// registered to track the aliens it's created for
+type trackedStrategy struct{}
+
+func (trackedStrategy) Choose(_ string, _ []game.Road, _ *rand.Rand) int {
+	return 0
+}
+
+var (
+	registerTrackedStrategy sync.Once
+	trackedStrategyLock     sync.Mutex
+	trackedStrategyAliens   []int
+)
+
+// TestRoot_Strategy makes sure the chosen strategy is created for each
+// alien, and reported in the logs so the run can be reproduced
+func TestRoot_Strategy(t *testing.T) {
+	const strategyName = "test-tracked"
+
+	registerTrackedStrategy.Do(func() {
+		game.RegisterStrategy(strategyName, func(alienID int) game.MovementStrategy {
+			trackedStrategyLock.Lock()
+			defer trackedStrategyLock.Unlock()
+
+			trackedStrategyAliens = append(trackedStrategyAliens, alienID)
+
+			return trackedStrategy{}
+		})
+	})
+
+	trackedStrategyAliens = nil
+
+	_, stderr, err := executeRootCommand(
+		t,
+		"3",
+		"--map-path", writeTempMap(t, "A east=B", "B east=C", "C east=D", "D east=E", "E east=F"),
+		"--strategy", strategyName,
+		"--seed", "1",
+	)
+
+	// The seed places every alien in a different city, so no alien
+	// is dropped before the run. Destroyed cities are not a failure
+	assert.NotEqual(t, exitCodeError, exitCode(err))
+	assert.Contains(t, stderr, "Using the test-tracked movement strategy")
+
+	sort.Ints(trackedStrategyAliens)
+	assert.Equal(t, []int{0, 1, 2}, trackedStrategyAliens)
+}
+
+// TestRoot_Strategy_Unknown makes sure unknown
+// strategies are rejected before the run
+func TestRoot_Strategy_Unknown(t *testing.T) {
+	_, _, err := executeRootCommand(
+		t,
+		"3",
+		"--map-path", writeTempMap(t, "Foo north=Bar", "Bar south=Foo"),
+		"--strategy", "teleport",
+	)
+
+	assert.ErrorIs(t, err, game.ErrUnknownStrategy)
+}
+
+// TestRoot_Collision makes sure the collision strategy decides if the
+// aliens meeting in a city destroy it, and unknown strategies are rejected
+func TestRoot_Collision(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo")
+
+	t.Run("cities spared", func(t *testing.T) {
+		// Both aliens land in the only city
+		stdout, stderr, err := executeRootCommand(t, "2", "--map-path", mapPath, "--collision", "flee")
+
+		assert.NoError(t, err)
+		assert.Contains(t, stderr, "Using the flee collision strategy")
+		assert.Contains(t, stdout, "A total of 0 cities were destroyed\n")
+		assert.Contains(t, stdout, "Foo\n")
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		_, _, err := executeRootCommand(t, "2", "--map-path", mapPath, "--collision", "truce")
+
+		assert.ErrorIs(t, err, game.ErrUnknownCollisionStrategy)
+	})
+}
+
+// TestRoot_GzipMap makes sure the gzip compressed map files
+// are decompressed, with the format detected from the inner extension
+func TestRoot_GzipMap(t *testing.T) {
+	var compressed bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&compressed)
+
+	if _, err := gzipWriter.Write([]byte(`{"cities":[{"name":"Foo","neighbors":{"north":"Bar"}},{"name":"Bar"}]}`)); err != nil {
+		t.Fatalf("unable to compress the map, %v", err)
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("unable to compress the map, %v", err)
+	}
+
+	mapPath := filepath.Join(t.TempDir(), "map.json.gz")
+
+	if err := os.WriteFile(mapPath, compressed.Bytes(), 0o600); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	stdout, _, err := executeRootCommand(t, "1", "--map-path", mapPath, "--quiet")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bar south=Foo\nFoo north=Bar\n", stdout)
+}
+
+// TestRoot_MapURL makes sure the map is downloaded from the http(s) URL,
+// and the unsuccessful responses are reported
+func TestRoot_MapURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/world.txt" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, "Foo north=Bar\nBar south=Foo\n")
+	}))
+	defer server.Close()
+
+	t.Run("map downloaded", func(t *testing.T) {
+		stdout, _, err := executeRootCommand(t, "1", "--map-path", server.URL+"/world.txt", "--quiet")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bar south=Foo\nFoo north=Bar\n", stdout)
+	})
+
+	t.Run("map not found", func(t *testing.T) {
+		_, _, err := executeRootCommand(t, "1", "--map-path", server.URL+"/missing.txt", "--quiet")
+
+		assert.ErrorIs(t, err, stream.ErrUnexpectedStatus)
+		assert.ErrorContains(t, err, "unable to download the map")
+	})
+}
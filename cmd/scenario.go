@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/scenario"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+var errScenarioMapPath = errors.New("the map is set in the scenario, so the map path can't be set")
+
+// applyScenario sets the flags to the scenario parameters. The flags set on the command line
+// (or with the environment variables) take precedence over the scenario, which takes precedence
+// over the config file. The positional number of aliens also takes precedence
+func applyScenario(cmd *cobra.Command, args []string, loaded *scenario.Scenario) error {
+	if cmd.Flags().Changed(mapPathFlag) {
+		return errScenarioMapPath
+	}
+
+	values := map[string]string{
+		inputFormatFlag: string(loaded.MapFormat()),
+		aliensFlag:      strconv.Itoa(loaded.Aliens),
+	}
+
+	// The inline map is read from the scenario, so the map path is not required
+	if loaded.Map.Path != "" {
+		values[mapPathFlag] = loaded.MapPath()
+	} else {
+		_ = cmd.Flags().SetAnnotation(mapPathFlag, cobra.BashCompOneRequiredFlag, []string{"false"})
+	}
+
+	if loaded.Seed != nil {
+		values[seedFlag] = strconv.FormatInt(*loaded.Seed, 10)
+	}
+
+	if loaded.MaxMoves > 0 {
+		values[maxMovesFlag] = strconv.Itoa(loaded.MaxMoves)
+	}
+
+	if loaded.Strategy != "" {
+		values[strategyFlag] = loaded.Strategy
+	}
+
+	if loaded.Collision != "" {
+		values[collisionFlag] = loaded.Collision
+	}
+
+	if loaded.TravelCosts {
+		values[travelCostsFlag] = strconv.FormatBool(loaded.TravelCosts)
+	}
+
+	if loaded.PerAlienRand {
+		values[perAlienRandFlag] = strconv.FormatBool(loaded.PerAlienRand)
+	}
+
+	for name, value := range values {
+		if cmd.Flags().Changed(name) || (name == aliensFlag && len(args) > 0) {
+			continue
+		}
+
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("%w: %s, %v", scenario.ErrInvalidScenario, name, err)
+		}
+
+		setFlagSource(cmd, name, fmt.Sprintf("the %s scenario", loaded.Name))
+	}
+
+	return nil
+}
+
+// getMapReader returns the reader of the map, which is the built-in map,
+// or the inline map of the scenario, if there is one
+func getMapReader(cmd *cobra.Command) (stream.InputReader, error) {
+	if params.builtinMap != "" {
+		reader, err := openBuiltinMap(params.builtinMap)
+		if err != nil {
+			return nil, err
+		}
+
+		return limitLineSize(reader), nil
+	}
+
+	if params.scenario != nil && params.scenario.Map.Inline != "" {
+		reader, err := params.scenario.MapReader()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the scenario map, %w", err)
+		}
+
+		return limitLineSize(reader), nil
+	}
+
+	reader, err := getInputReader(cmd, params.mapPath, params.inputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return limitLineSize(reader), nil
+}
+
+// limitLineSize sets the max line size of the text map reader,
+// so a longer line fails the map reading instead of being read whole
+func limitLineSize(reader stream.InputReader) stream.InputReader {
+	if scannerReader, ok := reader.(*stream.ScannerReader); ok {
+		scannerReader.SetMaxLineSize(params.maxLineSize)
+	}
+
+	return reader
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/scenario"
+)
+
+// TestRoot_Scenario makes sure the scenario sets the run parameters,
+// and is reported in the logs along with its hash
+func TestRoot_Scenario(t *testing.T) {
+	scenarioPath := filepath.Join("..", "scenario", "testdata", "siege.yaml")
+
+	t.Run("scenario run", func(t *testing.T) {
+		stdout, stderr, err := executeRootCommand(t, "--scenario", scenarioPath)
+
+		// The only city is destroyed, as expected
+		assert.ErrorIs(t, err, errAllDestroyed)
+		assert.Contains(t, stdout, "A total of 1 cities were destroyed\n")
+		assert.Contains(t, stderr, "Running the siege-of-foo scenario (sha256 ")
+		assert.Contains(t, stderr, "The scenario expectations are met")
+	})
+
+	t.Run("scenario in output", func(t *testing.T) {
+		loaded, err := scenario.Load(scenarioPath)
+		if err != nil {
+			t.Fatalf("unable to load the scenario, %v", err)
+		}
+
+		outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+		_, _, err = executeRootCommand(
+			t,
+			"--scenario", scenarioPath,
+			"--output-path", outputPath,
+			"--report-destructions",
+		)
+		assert.ErrorIs(t, err, errAllDestroyed)
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("unable to read output file, %v", err)
+		}
+
+		// The scenario is named before the destruction report
+		lines := strings.Split(string(output), "\n")
+		if !assert.GreaterOrEqual(t, len(lines), 2) {
+			return
+		}
+
+		assert.Equal(t, fmt.Sprintf("# Scenario siege-of-foo (sha256 %s)", loaded.Hash), lines[0])
+		assert.Equal(t, strings.TrimSuffix(reportHeader(), "\n"), lines[1])
+	})
+
+	t.Run("resolved parameters", func(t *testing.T) {
+		config := printConfig(t, "--scenario", scenarioPath)
+
+		assert.Equal(t, 2, config[aliensFlag])
+		assert.Equal(t, 42, config[seedFlag])
+		assert.Equal(t, 100, config[maxMovesFlag])
+		assert.Equal(t, "explore", config[strategyFlag])
+		assert.Equal(t, filepath.Join("..", "scenario", "testdata", "siege.txt"), config[mapPathFlag])
+	})
+
+	t.Run("flags over scenario", func(t *testing.T) {
+		config := printConfig(t, "1", "--scenario", scenarioPath, "--strategy", "random")
+
+		assert.Equal(t, 1, config[aliensFlag])
+		assert.Equal(t, "random", config[strategyFlag])
+	})
+
+	t.Run("unmet expectations", func(t *testing.T) {
+		_, _, err := executeRootCommand(t, "1", "--scenario", scenarioPath)
+
+		assert.ErrorIs(t, err, scenario.ErrExpectationNotMet)
+	})
+
+	t.Run("inline map", func(t *testing.T) {
+		stdout, _, err := executeRootCommand(t, "--scenario", writeTempConfig(
+			t,
+			"scenario.yaml",
+			"name: inline\naliens: 1\nmap:\n  inline: |\n    Foo north=Bar\n    Bar south=Foo\n",
+		))
+
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "A total of 0 cities were destroyed\n")
+	})
+
+	t.Run("map path set", func(t *testing.T) {
+		_, _, err := executeRootCommand(
+			t,
+			"--scenario", scenarioPath,
+			"--map-path", writeTempMap(t, "Foo north=Bar"),
+		)
+
+		assert.ErrorIs(t, err, errScenarioMapPath)
+	})
+}
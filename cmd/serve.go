@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/server"
+	"github.com/zivkovicmilos/alien-invasion/version"
+)
+
+// Define the present flags for the serve command
+const (
+	listenFlag = "listen"
+)
+
+// shutdownTimeout is the max time the server waits
+// for the in-flight requests during shutdown
+const shutdownTimeout = 10 * time.Second
+
+// serveParams defines the storage for
+// the serve command arguments
+type serveParams struct {
+	mapPath string
+	listen  string
+}
+
+// newServeCommand creates the command that exposes
+// the invasion simulation over HTTP
+func newServeCommand() *cobra.Command {
+	serveParams := &serveParams{}
+
+	serveCmd := &cobra.Command{
+		Use:          "serve",
+		Short:        "Exposes the invasion simulation of the map over a JSON HTTP API",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runServe(cmd, serveParams)
+		},
+	}
+
+	serveCmd.Flags().StringVar(
+		&serveParams.mapPath,
+		mapPathFlag,
+		"",
+		"The path to the input map file of the Earth",
+	)
+
+	serveCmd.Flags().StringVar(
+		&serveParams.listen,
+		listenFlag,
+		":8080",
+		"The address the HTTP server listens on",
+	)
+
+	_ = serveCmd.MarkFlagRequired(mapPathFlag)
+
+	return serveCmd
+}
+
+// runServe runs the serve command
+func runServe(cmd *cobra.Command, serveParams *serveParams) error {
+	logger := newLogger(cmd.ErrOrStderr())
+
+	logger.Info(fmt.Sprintf("Running alien-invasion %s", version.String()))
+
+	earthMap := game.NewEarthMap(logger)
+
+	if err := loadMap(serveParams.mapPath, earthMap); err != nil {
+		return err
+	}
+
+	var (
+		simulationServer = server.NewServer(logger, earthMap)
+		httpServer       = &http.Server{
+			Addr:              serveParams.listen,
+			Handler:           simulationServer.Handler(),
+			ReadHeaderTimeout: shutdownTimeout,
+		}
+
+		serverErrCh = make(chan error, 1)
+	)
+
+	go func() {
+		logger.Info(fmt.Sprintf("Listening on %s", serveParams.listen))
+
+		serverErrCh <- httpServer.ListenAndServe()
+	}()
+
+	// Wait for either the server to fail,
+	// or the user to exit
+	select {
+	case err := <-serverErrCh:
+		simulationServer.Close()
+
+		return fmt.Errorf("unable to serve the simulation API, %w", err)
+	case <-getTerminationSignalCh():
+	}
+
+	logger.Info("Shutting down the server...")
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelFn()
+
+	err := httpServer.Shutdown(ctx)
+
+	// Cancel the running simulations
+	simulationServer.Close()
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("unable to shut down the server, %w", err)
+	}
+
+	return nil
+}
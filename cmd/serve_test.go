@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestServe_InvalidAddress makes sure the serve command
+// fails if the server can't listen on the address
+func TestServe_InvalidAddress(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar")
+
+	_, _, err := executeRootCommand(t, "serve", "--map-path", mapPath, "--listen", "invalid:address:0")
+
+	assert.ErrorContains(t, err, "unable to serve the simulation API")
+}
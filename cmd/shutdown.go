@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// waitForShutdown waits for the run (the simulation and its output) to complete, handling the termination
+// signals in two stages. The first signal cancels the simulation gracefully, and a second signal forces the exit,
+// as does the run not completing within the force exit duration (if set) after the first signal.
+// Returns true if the exit is forced, in which case the run is left running
+func waitForShutdown(
+	signalCh <-chan os.Signal,
+	runComplete <-chan struct{},
+	cancelSimulation context.CancelFunc,
+	forceExitAfter time.Duration,
+	logger hclog.Logger,
+) bool {
+	select {
+	case sig := <-signalCh:
+		logger.Warn(
+			fmt.Sprintf("Caught the %s signal, stopping the invasion (repeat the signal to force the exit)", sig),
+		)
+
+		cancelSimulation()
+	case <-runComplete:
+		return false
+	}
+
+	// Bound the graceful shutdown, if set
+	var forceExitCh <-chan time.Time
+
+	if forceExitAfter > 0 {
+		timer := time.NewTimer(forceExitAfter)
+		defer timer.Stop()
+
+		forceExitCh = timer.C
+	}
+
+	select {
+	case sig := <-signalCh:
+		logger.Error(fmt.Sprintf("Caught the %s signal again, forcing the exit", sig))
+
+		return true
+	case <-forceExitCh:
+		logger.Error(fmt.Sprintf("The invasion didn't complete within %s, forcing the exit", forceExitAfter))
+
+		return true
+	case <-runComplete:
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWaitForShutdown makes sure the first signal cancels the simulation gracefully,
+// and the second signal, or the force exit duration, forces the exit
+func TestWaitForShutdown(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name              string
+		signals           int
+		forceExitAfter    time.Duration
+		complete          bool
+		expectedCancelled bool
+		expectedForced    bool
+	}{
+		{
+			"simulation completed",
+			0,
+			0,
+			true,
+			false,
+			false,
+		},
+		{
+			"graceful shutdown",
+			1,
+			0,
+			true,
+			true,
+			false,
+		},
+		{
+			"second signal",
+			2,
+			0,
+			false,
+			true,
+			true,
+		},
+		{
+			"force exit duration",
+			1,
+			time.Millisecond,
+			false,
+			true,
+			true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				signalCh           = make(chan os.Signal, testCase.signals)
+				simulationComplete = make(chan struct{})
+				cancelled          = make(chan struct{})
+			)
+
+			for i := 0; i < testCase.signals; i++ {
+				signalCh <- syscall.SIGINT
+			}
+
+			// The simulation completes once it's cancelled, unless it hangs
+			cancelSimulation := func() {
+				close(cancelled)
+
+				if testCase.complete {
+					close(simulationComplete)
+				}
+			}
+
+			if testCase.complete && testCase.signals == 0 {
+				close(simulationComplete)
+			}
+
+			forced := waitForShutdown(
+				signalCh,
+				simulationComplete,
+				cancelSimulation,
+				testCase.forceExitAfter,
+				hclog.NewNullLogger(),
+			)
+
+			assert.Equal(t, testCase.expectedForced, forced)
+
+			select {
+			case <-cancelled:
+				assert.True(t, testCase.expectedCancelled)
+			default:
+				assert.False(t, testCase.expectedCancelled)
+			}
+		})
+	}
+}
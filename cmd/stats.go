@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// Define the present flags for the stats command
+const (
+	aroundFlag = "around"
+	radiusFlag = "radius"
+)
+
+var errInvalidRadius = errors.New("radius must not be negative")
+
+// statsParams defines the storage for
+// the stats command arguments
+type statsParams struct {
+	mapPath    string
+	outputPath string
+	around     string
+	radius     int
+	json       bool
+}
+
+// newStatsCommand creates the command that inspects
+// a map file without simulating the invasion
+func newStatsCommand() *cobra.Command {
+	statsParams := &statsParams{}
+
+	statsCmd := &cobra.Command{
+		Use:          "stats",
+		Short:        "Prints the summary metrics of the map file, without simulating the invasion",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if statsParams.radius < 0 {
+				return fmt.Errorf("%w: %d", errInvalidRadius, statsParams.radius)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runStats(cmd, statsParams)
+		},
+	}
+
+	statsCmd.Flags().StringVar(
+		&statsParams.mapPath,
+		mapPathFlag,
+		"",
+		"The path to the input map file of the Earth",
+	)
+
+	statsCmd.Flags().StringVar(
+		&statsParams.around,
+		aroundFlag,
+		"",
+		"The city whose neighborhood is inspected, instead of the whole map",
+	)
+
+	statsCmd.Flags().IntVar(
+		&statsParams.radius,
+		radiusFlag,
+		1,
+		"The number of hops around the city included in the inspected neighborhood",
+	)
+
+	statsCmd.Flags().StringVar(
+		&statsParams.outputPath,
+		outputPathFlag,
+		"",
+		"The path to output the inspected map. If omitted, the map is not written out",
+	)
+
+	statsCmd.Flags().BoolVar(
+		&statsParams.json,
+		jsonFlag,
+		false,
+		"Output the map stats in JSON format",
+	)
+
+	_ = statsCmd.MarkFlagRequired(mapPathFlag)
+
+	return statsCmd
+}
+
+// runStats runs the stats command
+func runStats(cmd *cobra.Command, statsParams *statsParams) error {
+	earthMap := game.NewEarthMap(hclog.NewNullLogger())
+
+	if err := loadMap(statsParams.mapPath, earthMap); err != nil {
+		return err
+	}
+
+	// Narrow down the map to the neighborhood of the city, if set
+	if statsParams.around != "" {
+		subgraph, err := earthMap.Subgraph(statsParams.around, statsParams.radius)
+		if err != nil {
+			return fmt.Errorf("unable to extract the neighborhood, %w", err)
+		}
+
+		earthMap = subgraph
+	}
+
+	// Write out the inspected map, if set
+	if statsParams.outputPath != "" {
+		writer, err := getDestinationWriter(cmd, statsParams.outputPath, stream.FileWriterOptions{})
+		if err != nil {
+			return err
+		}
+
+		if err := writeOutput(writer, earthMap.WriteOutput); err != nil {
+			return err
+		}
+	}
+
+	var (
+		stats = earthMap.Stats()
+		err   error
+	)
+
+	if statsParams.json {
+		err = writeJSONStats(cmd.OutOrStdout(), stats)
+	} else {
+		err = writeTextStats(cmd.OutOrStdout(), stats)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to write the map stats, %w", err)
+	}
+
+	return nil
+}
+
+// writeTextStats writes out the map stats as a table
+func writeTextStats(w io.Writer, stats *game.MapStats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	isolated := fmt.Sprintf("%d", len(stats.IsolatedCities))
+	if len(stats.IsolatedCities) > 0 {
+		isolated = fmt.Sprintf("%s (%s)", isolated, strings.Join(stats.IsolatedCities, ", "))
+	}
+
+	_, _ = fmt.Fprintf(tw, "Cities\t%d\n", stats.Cities)
+	_, _ = fmt.Fprintf(tw, "Roads\t%d\n", stats.Roads)
+	_, _ = fmt.Fprintf(tw, "Components\t%d\n", stats.Components)
+	_, _ = fmt.Fprintf(tw, "Isolated cities\t%s\n", isolated)
+
+	for degree, count := range stats.DegreeHistogram {
+		_, _ = fmt.Fprintf(tw, "Cities with %d roads\t%d\n", degree, count)
+	}
+
+	return tw.Flush()
+}
+
+// writeJSONStats writes out the map stats in JSON format
+func writeJSONStats(w io.Writer, stats *game.MapStats) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(stats)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// TestStats_JSON makes sure the map stats are
+// properly output in JSON format
+func TestStats_JSON(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar west=Baz", "Bar west=Bee", "Lone")
+
+	stdout, _, err := executeRootCommand(t, "stats", "--map-path", mapPath, "--json")
+	if err != nil {
+		t.Fatalf("unable to run the stats command, %v", err)
+	}
+
+	var fields map[string]interface{}
+
+	if err := json.Unmarshal([]byte(stdout), &fields); err != nil {
+		t.Fatalf("unable to unmarshal the stats, %v", err)
+	}
+
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"cities":          float64(5),
+			"roads":           float64(3),
+			"degreeHistogram": []interface{}{float64(1), float64(2), float64(2), float64(0), float64(0)},
+			"components":      float64(2),
+			"isolatedCities":  []interface{}{"Lone"},
+		},
+		fields,
+	)
+}
+
+// TestStats_Table makes sure the map stats
+// are printed out as a table by default
+func TestStats_Table(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Lone")
+
+	stdout, _, err := executeRootCommand(t, "stats", "--map-path", mapPath)
+	if err != nil {
+		t.Fatalf("unable to run the stats command, %v", err)
+	}
+
+	expectedOutput := "Cities               3\n" +
+		"Roads                1\n" +
+		"Components           2\n" +
+		"Isolated cities      1 (Lone)\n" +
+		"Cities with 0 roads  1\n" +
+		"Cities with 1 roads  2\n" +
+		"Cities with 2 roads  0\n" +
+		"Cities with 3 roads  0\n" +
+		"Cities with 4 roads  0\n"
+
+	assert.Equal(t, expectedOutput, stdout)
+}
+
+// TestStats_Around makes sure the neighborhood of a city
+// can be inspected and written out
+func TestStats_Around(t *testing.T) {
+	var (
+		mapPath    = writeTempMap(t, "A east=B", "B east=C", "C east=D")
+		outputPath = filepath.Join(t.TempDir(), "sub.txt")
+	)
+
+	stdout, _, err := executeRootCommand(
+		t,
+		"stats",
+		"--map-path", mapPath,
+		"--around", "A",
+		"--radius", "1",
+		"--output-path", outputPath,
+		"--json",
+	)
+	if err != nil {
+		t.Fatalf("unable to run the stats command, %v", err)
+	}
+
+	var stats game.MapStats
+
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("unable to unmarshal the stats, %v", err)
+	}
+
+	assert.Equal(t, 2, stats.Cities)
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	assert.Equal(t, "A east=B\nB west=A\n", string(output))
+
+	// Unknown cities are rejected
+	_, _, err = executeRootCommand(t, "stats", "--map-path", mapPath, "--around", "Z")
+
+	assert.ErrorIs(t, err, game.ErrCityNotFound)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// applyStdinMapPath makes the standard input the map source when the map path is omitted,
+// and the map is piped in (ex. "generate ... | alien-invasion 10"). Setting the flag
+// satisfies its requirement, so the map path is still required on an interactive terminal.
+// The scenario and built-in maps take precedence, since they don't set the map path
+func applyStdinMapPath(cmd *cobra.Command) error {
+	if cmd.Flags().Changed(mapPathFlag) || params.scenario != nil || params.builtinMap != "" {
+		return nil
+	}
+
+	if !isPipedInput(cmd.InOrStdin()) {
+		return nil
+	}
+
+	return cmd.Flags().Set(mapPathFlag, stdinPath)
+}
+
+// isPipedInput checks if the input is piped or redirected from a file,
+// instead of being a terminal (or another character device, like /dev/null).
+// Inputs that are not files, like in-memory readers, are considered piped
+func isPipedInput(input io.Reader) bool {
+	file, ok := input.(*os.File)
+	if !ok {
+		return true
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice == 0
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// executeWithStdin executes the root command
+// with the given standard input
+func executeWithStdin(t *testing.T, stdin io.Reader, args ...string) (string, error) {
+	t.Helper()
+
+	var (
+		stdout bytes.Buffer
+
+		rootCmd = NewRootCommand().baseCmd
+	)
+
+	rootCmd.SetIn(stdin)
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs(args)
+
+	err := rootCmd.Execute()
+
+	return stdout.String(), err
+}
+
+// TestRoot_StdinMapPath makes sure the piped map is read
+// from the standard input when the map path is omitted
+func TestRoot_StdinMapPath(t *testing.T) {
+	t.Run("piped map", func(t *testing.T) {
+		stdout, err := executeWithStdin(
+			t,
+			strings.NewReader("Foo north=Bar\nBar south=Foo\nBaz\n"),
+			"1",
+			"--quiet",
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bar south=Foo\nBaz\nFoo north=Bar\n", stdout)
+	})
+
+	t.Run("terminal input", func(t *testing.T) {
+		// The null device is a character device, like a terminal
+		devNull, err := os.Open(os.DevNull)
+		if err != nil {
+			t.Fatalf("unable to open the null device, %v", err)
+		}
+
+		defer devNull.Close()
+
+		_, err = executeWithStdin(t, devNull, "1", "--quiet")
+
+		assert.ErrorContains(t, err, `required flag(s) "map-path" not set`)
+	})
+
+	t.Run("map path over piped map", func(t *testing.T) {
+		stdout, err := executeWithStdin(
+			t,
+			strings.NewReader("Qux\n"),
+			"1",
+			"--map-path", writeTempMap(t, "Foo"),
+			"--quiet",
+		)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, stdout, "Qux")
+	})
+}
+
+// TestIsPipedInput makes sure only the character
+// devices are not considered piped inputs
+func TestIsPipedInput(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("unable to open the null device, %v", err)
+	}
+
+	defer devNull.Close()
+
+	mapFile, err := os.Open(writeTempMap(t, "Foo"))
+	if err != nil {
+		t.Fatalf("unable to open the map file, %v", err)
+	}
+
+	defer mapFile.Close()
+
+	pipeReader, pipeWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unable to create a pipe, %v", err)
+	}
+
+	defer pipeReader.Close()
+	defer pipeWriter.Close()
+
+	assert.True(t, isPipedInput(strings.NewReader("Foo")))
+	assert.True(t, isPipedInput(mapFile))
+	assert.True(t, isPipedInput(pipeReader))
+	assert.False(t, isPipedInput(devNull))
+}
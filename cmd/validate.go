@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// Define the present flags for the validate command
+const (
+	jsonFlag = "json"
+)
+
+var errMapInvalid = errors.New("map validation failed")
+
+// validateParams defines the storage for
+// the validate command arguments
+type validateParams struct {
+	mapPath string
+	json    bool
+}
+
+// newValidateCommand creates the command that checks
+// a map file without simulating the invasion
+func newValidateCommand() *cobra.Command {
+	validateParams := &validateParams{}
+
+	validateCmd := &cobra.Command{
+		Use:          "validate",
+		Short:        "Checks the map file for errors, without simulating the invasion",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runValidate(cmd, validateParams)
+		},
+	}
+
+	validateCmd.Flags().StringVar(
+		&validateParams.mapPath,
+		mapPathFlag,
+		"",
+		"The path to the input map file of the Earth",
+	)
+
+	validateCmd.Flags().BoolVar(
+		&validateParams.json,
+		jsonFlag,
+		false,
+		"Output the validation report in JSON format",
+	)
+
+	_ = validateCmd.MarkFlagRequired(mapPathFlag)
+
+	return validateCmd
+}
+
+// runValidate runs the validate command
+func runValidate(cmd *cobra.Command, validateParams *validateParams) error {
+	// Create an instance of the file reader
+	fileReader, err := stream.NewFileReader(validateParams.mapPath)
+	if err != nil {
+		return fmt.Errorf("unable to create a file reader, %w", err)
+	}
+
+	defer func() {
+		_ = fileReader.Close()
+	}()
+
+	// Load the map, failing on the first malformed line
+	earthMap := game.NewEarthMap(hclog.NewNullLogger(), game.WithStrictParsing())
+	report := loadAndValidate(fileReader, earthMap)
+
+	// Output the report
+	if validateParams.json {
+		err = writeJSONReport(cmd.OutOrStdout(), report)
+	} else {
+		err = writeTextReport(cmd.OutOrStdout(), report)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to write the validation report, %w", err)
+	}
+
+	if report.HasErrors() {
+		return errMapInvalid
+	}
+
+	return nil
+}
+
+// loadAndValidate loads the map from the reader, and validates it.
+// A map that fails to load is reported with the load error as the only issue
+func loadAndValidate(reader stream.InputReader, earthMap *game.EarthMap) *game.ValidationReport {
+	if err := earthMap.InitMap(reader); err != nil {
+		return &game.ValidationReport{
+			Issues: []game.Issue{
+				{
+					Severity: game.SeverityError,
+					Message:  err.Error(),
+				},
+			},
+		}
+	}
+
+	return earthMap.Validate()
+}
+
+// writeTextReport writes out the human readable validation report
+func writeTextReport(w io.Writer, report *game.ValidationReport) error {
+	if _, err := fmt.Fprintf(
+		w,
+		"Cities: %d\nRoads: %d\nRegions: %d\n",
+		report.Cities,
+		report.Roads,
+		report.Components,
+	); err != nil {
+		return err
+	}
+
+	return writeTextIssues(w, report)
+}
+
+// writeTextIssues writes out the issue counts, followed by the found issues
+func writeTextIssues(w io.Writer, report *game.ValidationReport) error {
+	numErrors := 0
+
+	for _, issue := range report.Issues {
+		if issue.Severity == game.SeverityError {
+			numErrors++
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "Issues: %d errors, %d warnings\n", numErrors, len(report.Issues)-numErrors); err != nil {
+		return err
+	}
+
+	for _, issue := range report.Issues {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", issue.Severity, issue.Message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJSONReport writes out the validation report in JSON format
+func writeJSONReport(w io.Writer, report *game.ValidationReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
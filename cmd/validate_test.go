@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// TestValidate_Report makes sure the validate command reports
+// the map issues, and fails only if errors were found
+func TestValidate_Report(t *testing.T) {
+	t.Run("valid map", func(t *testing.T) {
+		mapPath := writeTempMap(t, "Foo north=Bar", "Bar west=Baz")
+
+		stdout, _, err := executeRootCommand(t, "validate", "--map-path", mapPath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Cities: 3\nRoads: 2\nRegions: 1\nIssues: 0 errors, 0 warnings\n", stdout)
+	})
+
+	t.Run("map with warnings", func(t *testing.T) {
+		mapPath := writeTempMap(t, "Foo north=Bar", "Lone")
+
+		stdout, _, err := executeRootCommand(t, "validate", "--map-path", mapPath)
+
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "Issues: 0 errors, 2 warnings\n")
+		assert.Contains(t, stdout, "warning: city Lone has no roads\n")
+	})
+
+	t.Run("broken map", func(t *testing.T) {
+		mapPath := writeTempMap(t, "Foo north=Bar", "Bar up=Baz")
+
+		stdout, _, err := executeRootCommand(t, "validate", "--map-path", mapPath)
+
+		assert.ErrorIs(t, err, errMapInvalid)
+		assert.Contains(t, stdout, "Issues: 1 errors, 0 warnings\n")
+		assert.Contains(t, stdout, "unable to parse line 2")
+	})
+}
+
+// TestValidate_JSON makes sure the validation report
+// can be output in JSON format
+func TestValidate_JSON(t *testing.T) {
+	mapPath := writeTempMap(t, "Foo north=Bar", "Lone")
+
+	stdout, _, err := executeRootCommand(t, "validate", "--map-path", mapPath, "--json")
+	if err != nil {
+		t.Fatalf("unable to validate the map, %v", err)
+	}
+
+	var report game.ValidationReport
+
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("unable to unmarshal the report, %v", err)
+	}
+
+	assert.Equal(t, 3, report.Cities)
+	assert.Equal(t, 1, report.Roads)
+	assert.Equal(t, 2, report.Components)
+	assert.Len(t, report.Issues, 2)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zivkovicmilos/alien-invasion/version"
+)
+
+// newVersionCommand creates the command that prints
+// the build information of the program
+func newVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "version",
+		Short:        "Prints the version, git commit and build date of the program",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_, err := fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Version:    %s\nCommit:     %s\nBuild date: %s\n",
+				version.Version,
+				version.Commit,
+				version.BuildDate,
+			)
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/version"
+)
+
+// setBuildInfo overrides the build information for the duration of the test,
+// as if it was injected using the linker flags
+func setBuildInfo(t *testing.T, buildVersion, commit, buildDate string) {
+	t.Helper()
+
+	previousVersion, previousCommit, previousBuildDate := version.Version, version.Commit, version.BuildDate
+
+	version.Version, version.Commit, version.BuildDate = buildVersion, commit, buildDate
+
+	t.Cleanup(func() {
+		version.Version, version.Commit, version.BuildDate = previousVersion, previousCommit, previousBuildDate
+	})
+}
+
+// TestVersion_Command makes sure the version command
+// prints the injected build information
+func TestVersion_Command(t *testing.T) {
+	setBuildInfo(t, "v1.2.3", "abc1234", "2022-11-01")
+
+	stdout, _, err := executeRootCommand(t, "version")
+	if err != nil {
+		t.Fatalf("unable to run the version command, %v", err)
+	}
+
+	assert.Equal(
+		t,
+		"Version:    v1.2.3\nCommit:     abc1234\nBuild date: 2022-11-01\n",
+		stdout,
+	)
+}
+
+// TestVersion_Flag makes sure the root version flag
+// prints the injected build information
+func TestVersion_Flag(t *testing.T) {
+	setBuildInfo(t, "v1.2.3", "abc1234", "2022-11-01")
+
+	stdout, _, err := executeRootCommand(t, "--version")
+	if err != nil {
+		t.Fatalf("unable to run the version flag, %v", err)
+	}
+
+	assert.Contains(t, stdout, "v1.2.3 (commit abc1234, built 2022-11-01)")
+}
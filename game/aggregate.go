@@ -0,0 +1,209 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// AggregateResult contains the statistics of multiple invasion simulations
+// run on independent copies of the same map
+type AggregateResult struct {
+	Runs                   int                `json:"runs"`                   // the number of completed simulation runs
+	AverageDestroyed       float64            `json:"averageDestroyed"`       // the average number of cities destroyed per run
+	DestroyedVariance      float64            `json:"destroyedVariance"`      // the variance of the number of cities destroyed per run
+	DestroyedDistribution  map[int]int        `json:"destroyedDistribution"`  // the number of runs per destroyed city count
+	AverageSurvivingAliens float64            `json:"averageSurvivingAliens"` // the average number of aliens surviving per run
+	SurvivalRate           map[string]float64 `json:"survivalRate"`           // the fraction of runs each city survived
+	Interrupted            bool               `json:"interrupted"`            // flag indicating if the runs were cut short
+}
+
+// runOutcome is the outcome of a single simulation run
+type runOutcome struct {
+	completed bool             // flag indicating if the run was executed
+	result    SimulationResult // the result of the run
+	survivors []string         // the cities that survived the run
+	err       error            // the simulation error, if any
+}
+
+// SimulateRuns runs the invasion simulation the given number of times,
+// each on a fresh copy of the map. The runs use seeds derived from the map seed
+// (seed, seed+1, ...), so the aggregate is reproducible. The map itself is left untouched.
+//
+// Returns the statistics of the completed runs
+func (m *EarthMap) SimulateRuns(ctx context.Context, numAliens, runs int) (AggregateResult, error) {
+	return m.SimulateRunsParallel(ctx, numAliens, runs, 1)
+}
+
+// SimulateRunsParallel runs the invasion simulations like SimulateRuns,
+// with at most the given number of runs simulated at the same time.
+// The statistics don't depend on the parallelism, since each run has its own seed
+func (m *EarthMap) SimulateRunsParallel(
+	ctx context.Context,
+	numAliens,
+	runs,
+	parallel int,
+) (AggregateResult, error) {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		outcomes = make([]runOutcome, runs)
+		runCh    = make(chan int)
+
+		wg sync.WaitGroup
+	)
+
+	// Start the run workers
+	for worker := 0; worker < parallel; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for run := range runCh {
+				outcomes[run] = m.simulateRun(ctx, numAliens, run)
+			}
+		}()
+	}
+
+	// Hand out the runs, until they are exhausted or the context is cancelled
+	func() {
+		defer close(runCh)
+
+		for run := 0; run < runs; run++ {
+			select {
+			case <-ctx.Done():
+				return
+			case runCh <- run:
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	return m.aggregateOutcomes(outcomes)
+}
+
+// simulateRun simulates a single run on a fresh copy of the map
+func (m *EarthMap) simulateRun(ctx context.Context, numAliens, run int) runOutcome {
+	runMap := m.Clone()
+	WithSeed(m.config.seed + int64(run))(runMap)
+
+	result, err := runMap.SimulateInvasion(ctx, numAliens)
+	if err != nil {
+		return runOutcome{
+			completed: true,
+			err:       fmt.Errorf("unable to simulate run %d, %w", run+1, err),
+		}
+	}
+
+	// Gather the surviving cities
+	destroyedCities := make(map[string]struct{})
+	for _, name := range runMap.DestroyedCities() {
+		destroyedCities[name] = struct{}{}
+	}
+
+	survivors := make([]string, 0)
+
+	for _, name := range runMap.Cities() {
+		if _, ok := destroyedCities[name]; !ok {
+			survivors = append(survivors, name)
+		}
+	}
+
+	return runOutcome{
+		completed: true,
+		result:    result,
+		survivors: survivors,
+	}
+}
+
+// aggregateOutcomes calculates the statistics of the run outcomes, in run order.
+// The runs following the first interrupted run are left out of the statistics
+func (m *EarthMap) aggregateOutcomes(outcomes []runOutcome) (AggregateResult, error) {
+	var (
+		cities    = m.Cities()
+		survivals = make(map[string]int, len(cities))
+		destroyed = make([]int, 0, len(outcomes))
+
+		survivingAliens = 0
+
+		aggregate = AggregateResult{
+			DestroyedDistribution: make(map[int]int),
+			SurvivalRate:          make(map[string]float64, len(cities)),
+		}
+	)
+
+	for _, outcome := range outcomes {
+		if outcome.err != nil {
+			return aggregate, outcome.err
+		}
+
+		if !outcome.completed || outcome.result.Interrupted {
+			// Partial runs would skew the statistics
+			aggregate.Interrupted = true
+
+			break
+		}
+
+		destroyed = append(destroyed, outcome.result.CitiesDestroyed)
+		aggregate.DestroyedDistribution[outcome.result.CitiesDestroyed]++
+
+		survivingAliens += outcome.result.SurvivingAliens
+
+		// Tally up the surviving cities
+		for _, name := range outcome.survivors {
+			survivals[name]++
+		}
+	}
+
+	aggregate.Runs = len(destroyed)
+	if aggregate.Runs == 0 {
+		return aggregate, nil
+	}
+
+	// Calculate the mean and (population) variance of the destroyed city count
+	total := 0
+	for _, count := range destroyed {
+		total += count
+	}
+
+	aggregate.AverageDestroyed = float64(total) / float64(aggregate.Runs)
+
+	for _, count := range destroyed {
+		deviation := float64(count) - aggregate.AverageDestroyed
+		aggregate.DestroyedVariance += deviation * deviation
+	}
+
+	aggregate.DestroyedVariance /= float64(aggregate.Runs)
+	aggregate.AverageSurvivingAliens = float64(survivingAliens) / float64(aggregate.Runs)
+
+	for _, name := range cities {
+		aggregate.SurvivalRate[name] = float64(survivals[name]) / float64(aggregate.Runs)
+	}
+
+	return aggregate, nil
+}
+
+// EstimateSurvival estimates the probability of each city on the map surviving
+// the invasion, by simulating it the given number of times (Monte Carlo).
+// The options are applied to the copies of the map the runs use, and the map itself
+// is left untouched.
+//
+// Returns the fraction of runs each city survived, or nil if the invasion can't be simulated
+func EstimateSurvival(m *EarthMap, numAliens, runs int, opts ...Option) map[string]float64 {
+	base := m.Clone()
+
+	for _, opt := range opts {
+		opt(base)
+	}
+
+	aggregate, err := base.SimulateRuns(context.Background(), numAliens, runs)
+	if err != nil || aggregate.Runs == 0 {
+		return nil
+	}
+
+	return aggregate.SurvivalRate
+}
@@ -0,0 +1,131 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestMap_SimulateRuns makes sure the statistics of multiple
+// simulation runs are properly aggregated
+func TestMap_SimulateRuns(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name      string
+		numAliens int
+		expected  AggregateResult
+	}{
+		{
+			"city always destroyed",
+			2,
+			AggregateResult{
+				Runs:                   3,
+				AverageDestroyed:       1,
+				DestroyedVariance:      0,
+				DestroyedDistribution:  map[int]int{1: 3},
+				AverageSurvivingAliens: 0,
+				SurvivalRate:           map[string]float64{"Foo": 0},
+			},
+		},
+		{
+			"city always survives",
+			1,
+			AggregateResult{
+				Runs:                   3,
+				AverageDestroyed:       0,
+				DestroyedVariance:      0,
+				DestroyedDistribution:  map[int]int{0: 3},
+				AverageSurvivingAliens: 1,
+				SurvivalRate:           map[string]float64{"Foo": 1},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewEarthMap(hclog.NewNullLogger())
+			assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo"})))
+
+			aggregate, err := m.SimulateRuns(context.Background(), testCase.numAliens, 3)
+			if err != nil {
+				t.Fatalf("unable to simulate the runs, %v", err)
+			}
+
+			assert.Equal(t, testCase.expected, aggregate)
+
+			// The statistics don't depend on the parallelism
+			aggregate, err = m.SimulateRunsParallel(context.Background(), testCase.numAliens, 3, 2)
+			if err != nil {
+				t.Fatalf("unable to simulate the parallel runs, %v", err)
+			}
+
+			assert.Equal(t, testCase.expected, aggregate)
+
+			// Make sure the original map is untouched
+			assert.Equal(t, []string{"Foo"}, m.Cities())
+			assert.Equal(t, 0, m.DestroyedCount())
+		})
+	}
+}
+
+// TestMap_SimulateRuns_Interrupted makes sure interrupted
+// runs are left out of the statistics
+func TestMap_SimulateRuns_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	cancelFn()
+
+	aggregate, err := GenerateGridMap(10, 10).SimulateRuns(ctx, 1, 3)
+	if err != nil {
+		t.Fatalf("unable to simulate the runs, %v", err)
+	}
+
+	assert.True(t, aggregate.Interrupted)
+	assert.Equal(t, 0, aggregate.Runs)
+}
+
+// TestEstimateSurvival makes sure the survival probabilities
+// are within the expected bounds
+func TestEstimateSurvival(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{
+		"Foo east=Bar",
+		"Baz",
+	})))
+
+	estimate := EstimateSurvival(m, 4, 50, WithSeed(42))
+
+	if !assert.Len(t, estimate, 3) {
+		return
+	}
+
+	for city, probability := range estimate {
+		assert.GreaterOrEqual(t, probability, 0.0, city)
+		assert.LessOrEqual(t, probability, 1.0, city)
+	}
+
+	// With 4 aliens on 3 cities, at least one city
+	// is bound to host 2 of them, and fall
+	assert.Less(t, estimate["Foo"]+estimate["Bar"]+estimate["Baz"], 3.0)
+
+	// Make sure the original map is untouched
+	assert.Equal(t, []string{"Bar", "Baz", "Foo"}, m.Cities())
+	assert.Equal(t, 0, m.DestroyedCount())
+
+	// A city that always hosts both aliens never survives
+	single := NewEarthMap(hclog.NewNullLogger())
+	assert.NoError(t, single.InitMap(stream.NewSliceReader([]string{"Foo"})))
+
+	assert.Equal(t, map[string]float64{"Foo": 0}, EstimateSurvival(single, 2, 10, WithSeed(42)))
+}
@@ -3,19 +3,102 @@ package game
 import (
 	"context"
 	"math/rand"
-	"time"
 )
 
+// movementBehavior selects the next city the alien moves to from the current city.
+// The behavior needs to lay siege to the selected city before returning it,
+// or return nil if the alien cannot move anywhere
+type movementBehavior func(a *alien, current *city) *city
+
+// randomMovement is the default alien movement behavior,
+// where the alien lays siege to a random accessible neighbor
+func randomMovement(a *alien, current *city) *city {
+	return a.siegeRandomNeighbor(current)
+}
+
 // alien defines the single alien instance
 type alien struct {
-	id int
+	id       int
+	behavior movementBehavior // selects the next city the alien moves to
+	rng      *rand.Rand       // the random number generator used for movement
+	maxMoves int              // the max number of moves (or travel cost budget) of the alien
+	recorder *Recorder        // the recorder of the alien moves, if any
+	budget   *moveBudget      // the total move budget shared by the aliens, if any
+
+	travelCosts bool // flag indicating if the max moves are a travel cost budget
+	traveled    int  // the total travel cost of the roads the alien has taken
+
+	limitedEnergy bool // flag indicating if the alien has an energy budget
+	energy        int  // the remaining energy of the alien, spent a unit per move
+	starved       bool // flag indicating if the alien ran out of energy
+}
+
+// withTravelCosts makes the alien max moves a budget of the road travel costs,
+// instead of a limit on the number of moves
+func withTravelCosts() func(*alien) {
+	return func(a *alien) {
+		a.travelCosts = true
+	}
+}
+
+// withAlienEnergy gives the alien an energy budget, which decreases with each move.
+// The alien starves once the energy runs out, regardless of the max moves
+func withAlienEnergy(energy int) func(*alien) {
+	return func(a *alien) {
+		a.limitedEnergy = true
+		a.energy = energy
+	}
+}
+
+// withRecorder sets a specific alien move recorder
+func withRecorder(recorder *Recorder) func(*alien) {
+	return func(a *alien) {
+		a.recorder = recorder
+	}
+}
+
+// withMoveBudget sets the total move budget the alien spends its moves from
+func withMoveBudget(budget *moveBudget) func(*alien) {
+	return func(a *alien) {
+		a.budget = budget
+	}
+}
+
+// withMaxMoves sets a specific alien max move count
+func withMaxMoves(maxMoves int) func(*alien) {
+	return func(a *alien) {
+		a.maxMoves = maxMoves
+	}
+}
+
+// withRand sets a specific alien random number generator
+func withRand(rng *rand.Rand) func(*alien) {
+	return func(a *alien) {
+		a.rng = rng
+	}
+}
+
+// withBehavior sets a specific alien movement behavior
+func withBehavior(behavior movementBehavior) func(*alien) {
+	return func(a *alien) {
+		a.behavior = behavior
+	}
 }
 
 // newAlien creates a new alien instance
-func newAlien(id int) *alien {
-	return &alien{
-		id: id,
+func newAlien(id int, opts ...func(*alien)) *alien {
+	a := &alien{
+		id:       id,
+		behavior: randomMovement,
+		rng:      newRand(generateSeed()),
+		maxMoves: DefaultMaxMoves,
+	}
+
+	for _, callback := range opts {
+		callback(a)
 	}
+
+	return a
 }
 
 // runAlien runs the alien's main run loop
@@ -34,8 +117,8 @@ func (a *alien) runAlien(
 		case <-ctx.Done():
 			return
 		default:
-			// Attempt to lay siege to a random neighbor
-			siegedNeighbor := a.siegeRandomNeighbor(currentCity)
+			// Attempt to lay siege to the next city
+			siegedNeighbor := a.selectNextCity(currentCity)
 			if siegedNeighbor == nil {
 				// No neighbor can be sieged, the alien dies
 				notifyCh(ctx, doneCh)
@@ -43,8 +126,19 @@ func (a *alien) runAlien(
 				return
 			}
 
-			// Check if the current city can be left
-			if !currentCity.removeInvader(a.id) {
+			// Check if the road to the sieged neighbor fits in the rest of the travel budget,
+			// otherwise the alien stays in the current city, as if its max moves were reached
+			if !a.canAfford(currentCity, siegedNeighbor) {
+				siegedNeighbor.liftSiege(a.id)
+
+				notifyCh(ctx, doneCh)
+
+				return
+			}
+
+			// Move to the sieged neighbor, if the current city can be left
+			moved, destroyed := a.moveTo(currentCity, siegedNeighbor)
+			if !moved {
 				// The alien cannot leave the current city because it
 				// has been killed, remove the siege from the neighbor
 				siegedNeighbor.liftSiege(a.id)
@@ -56,14 +150,32 @@ func (a *alien) runAlien(
 
 			currentCity = siegedNeighbor
 
-			// Invade the sieged neighbor
-			currentCity.addInvader(a.id)
-
 			// Increase the movement counter
 			moveCount++
 
-			// Check if max moves have been reached
-			if moveCount >= maxMoveCount {
+			// Check if the total move budget has been used up, in which
+			// case the simulation is stopped, and there is no one to notify
+			if a.budget != nil && !a.budget.spend() {
+				return
+			}
+
+			// Check if the alien died destroying the city it moved to,
+			// so it doesn't hold up a siege on the next city in the meantime
+			if destroyed {
+				notifyCh(ctx, doneCh)
+
+				return
+			}
+
+			// Check if the alien has starved
+			if !a.useEnergy() {
+				notifyCh(ctx, doneCh)
+
+				return
+			}
+
+			// Check if max moves (or the travel budget) have been reached
+			if a.spent(moveCount) >= a.maxMoves {
 				notifyCh(ctx, doneCh)
 
 				return
@@ -72,6 +184,114 @@ func (a *alien) runAlien(
 	}
 }
 
+// moveTo leaves the current city, and invades the sieged next city.
+// Returns false if the current city can't be left, because the alien has been killed,
+// or the alien was killed in a fight over the next city,
+// along with a flag indicating if the invasion destroyed the next city
+func (a *alien) moveTo(current, next *city) (moved bool, destroyed bool) {
+	// Moves are serialized while recording,
+	// so the recorded order is the order in which they happened
+	if a.recorder != nil {
+		a.recorder.mux.Lock()
+		defer a.recorder.mux.Unlock()
+	}
+
+	if !current.removeInvader(a.id) {
+		return false, false
+	}
+
+	// Invade the sieged neighbor. The alien holds the siege,
+	// so the invasion only fails if the city was destroyed in the meantime
+	invaded, destroyed := next.tryInvade(a.id, a.rng)
+	if !invaded {
+		return false, false
+	}
+
+	a.traveled += current.costTo(next)
+
+	if a.recorder != nil {
+		a.recorder.addMove(a.id, next.name)
+	}
+
+	return true, destroyed
+}
+
+// spent returns how much of the alien's max moves have been used up,
+// which is the travel cost so far if travel costs are enabled
+func (a *alien) spent(moveCount int) int {
+	if a.travelCosts {
+		return a.traveled
+	}
+
+	return moveCount
+}
+
+// canAfford checks if the alien can travel the road from the current city to the next one,
+// without going over its travel budget. The road is always affordable without travel costs,
+// since the max moves are checked after each move
+func (a *alien) canAfford(current, next *city) bool {
+	if !a.travelCosts {
+		return true
+	}
+
+	return a.traveled+current.costTo(next) <= a.maxMoves
+}
+
+// useEnergy spends a unit of the alien's energy on a move, if the energy is limited.
+// Returns false if the alien ran out of energy, in which case the alien has starved
+func (a *alien) useEnergy() bool {
+	if !a.limitedEnergy {
+		return true
+	}
+
+	a.energy--
+
+	if a.energy > 0 {
+		return true
+	}
+
+	a.starved = true
+
+	return false
+}
+
+// selectNextCity selects the next city using the alien's movement behavior,
+// making sure the alien holds a siege on the selected city, and on no other neighbor.
+// Returns nil if the alien cannot move to any city
+func (a *alien) selectNextCity(current *city) *city {
+	next := a.behavior(a, current)
+
+	// The behavior might have laid siege to more than the selected city,
+	// and the stray sieges would block the other aliens for good
+	a.liftStraySieges(current, next)
+
+	if next == nil {
+		return nil
+	}
+
+	// The behavior might not have respected the siege mechanics,
+	// so the siege is laid here if it's missing
+	if !next.hasSiege(a.id) && !next.laySiege(a.id) {
+		return nil
+	}
+
+	return next
+}
+
+// liftStraySieges lifts the sieges the alien holds on the neighbors of the current city,
+// other than the selected next city (if any). The alien keeps its siege on the current city
+func (a *alien) liftStraySieges(current, next *city) {
+	for _, neighbor := range current.neighbors {
+		if neighbor == next || neighbor == current {
+			continue
+		}
+
+		if neighbor.hasSiege(a.id) {
+			neighbor.liftSiege(a.id)
+		}
+	}
+}
+
 // notifyCh safely alerts the channel of a notification,
 // while making sure the running thread is properly cancelled
 func notifyCh(ctx context.Context, ch chan<- struct{}) {
@@ -95,14 +315,10 @@ func (a *alien) siegeRandomNeighbor(c *city) *city {
 		return nil
 	}
 
-	// Seed the random number generator
-	rand.Seed(time.Now().UnixNano())
-
 	// While there are still valid neighbors, attempt to siege
 	// them randomly
 	for c.hasAccessibleNeighbors() {
-		//nolint:gosec
-		randNeighbor := c.neighbors[direction(rand.Intn(numDirections))]
+		randNeighbor := c.neighbors[direction(a.rng.Intn(numDirections))]
 
 		if randNeighbor == nil {
 			// No neighbor in this direction, try again
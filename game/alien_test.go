@@ -244,6 +244,44 @@ func TestAlien_AlienKilled_MaxMovesReached(t *testing.T) {
 	assert.False(t, invadingCityNeighbor.destroyed)
 }
 
+// TestAlien_AlienStarved makes sure the alien stops
+// wandering once it runs out of energy
+func TestAlien_AlienStarved(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = newAlien(0, withAlienEnergy(3))
+
+		cityA = newCity("A")
+		cityB = newCity("B")
+
+		alienDoneCh = make(chan struct{})
+	)
+
+	cityA.addNeighbor(north, cityB)
+	cityB.addNeighbor(south, cityA)
+
+	// Place the alien in city A
+	cityA.laySiege(a.id)
+	cityA.addInvader(a.id)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	go a.runAlien(ctx, cityA, alienDoneCh)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("alien did not finish in time")
+	case <-alienDoneCh:
+	}
+
+	// The alien moves A -> B -> A -> B, well before the max moves
+	assert.True(t, a.starved)
+	assert.Equal(t, 3, a.traveled)
+	assert.Contains(t, cityB.invaders, a.id)
+}
+
 // TestAlien_AlienKilled_CityInvaded verifies the main run functionality
 // of the alien thread, and that it gets killed off appropriately
 // when it invades a city and encounters another alien
@@ -300,6 +338,48 @@ func TestAlien_AlienKilled_CityInvaded(t *testing.T) {
 	assert.True(t, neighbor.destroyed)
 }
 
+// TestAlien_AlienKilled_CityDestroyedOnArrival makes sure the alien that
+// destroys the city it moves to stops right away, without selecting the next city
+func TestAlien_AlienKilled_CityDestroyedOnArrival(t *testing.T) {
+	t.Parallel()
+
+	var (
+		selections  = 0
+		alienDoneCh = make(chan struct{})
+
+		cityA = newCity("A")
+		cityB = newCity("B")
+		cityC = newCity("C")
+	)
+
+	cityA.addNeighbor(north, cityB)
+	cityB.addNeighbor(north, cityC)
+
+	// City B already has an invader
+	cityB.tryInvade(1, nil)
+
+	a := newAlien(0, withBehavior(func(a *alien, current *city) *city {
+		selections++
+
+		return randomMovement(a, current)
+	}))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	go a.runAlien(ctx, cityA, alienDoneCh)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("alien did not finish in time")
+	case <-alienDoneCh:
+	}
+
+	assert.True(t, cityB.destroyed)
+	assert.Equal(t, 1, selections)
+	assert.False(t, cityC.hasSiege(a.id))
+}
+
 // TestAlien_AlienKilled_CitySiegedNotInvaded verifies the main run functionality
 // of the alien thread, and that it gets killed off appropriately
 // when it sieges a city, but cannot leave the current one (doesn't invade it)
@@ -355,3 +435,322 @@ func TestAlien_AlienKilled_CitySiegedNotInvaded(t *testing.T) {
 	// Make sure the siege is removed
 	assert.Len(t, neighbor.sieges, 0)
 }
+
+// lowestNameNeighbor returns the accessible neighbor with the lowest name
+func lowestNameNeighbor(current *city) *city {
+	var lowest *city
+
+	for _, neighbor := range current.neighbors {
+		if neighbor.isDestroyed() {
+			continue
+		}
+
+		if lowest == nil || neighbor.name < lowest.name {
+			lowest = neighbor
+		}
+	}
+
+	return lowest
+}
+
+// TestAlien_CustomBehavior verifies the alien uses the provided
+// deterministic movement behavior, while respecting the siege mechanics
+func TestAlien_CustomBehavior(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		behavior movementBehavior
+	}{
+		{
+			"behavior laying sieges",
+			func(a *alien, current *city) *city {
+				neighbor := lowestNameNeighbor(current)
+				if neighbor == nil || !neighbor.laySiege(a.id) {
+					return nil
+				}
+
+				return neighbor
+			},
+		},
+		{
+			"behavior ignoring sieges",
+			func(a *alien, current *city) *city {
+				return lowestNameNeighbor(current)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				a = newAlien(0, withBehavior(testCase.behavior))
+
+				cityA = newCity("A")
+				cityB = newCity("B")
+				cityC = newCity("C")
+
+				alienDoneCh = make(chan struct{})
+			)
+
+			// Create a line map A-B-C
+			cityA.addNeighbor(east, cityB)
+			cityB.addNeighbor(west, cityA)
+			cityB.addNeighbor(east, cityC)
+			cityC.addNeighbor(west, cityB)
+
+			// Place the alien in city C
+			cityC.laySiege(a.id)
+			cityC.addInvader(a.id)
+
+			ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelFn()
+
+			go a.runAlien(ctx, cityC, alienDoneCh)
+
+			select {
+			case <-ctx.Done():
+				t.Fatal("alien did not finish in time")
+			case <-alienDoneCh:
+			}
+
+			// The alien moves C -> B -> A -> B -> A ...,
+			// ending up in city A after an even number of moves
+			assert.Len(t, cityC.invaders, 0)
+			assert.Len(t, cityB.invaders, 0)
+			assert.Len(t, cityB.sieges, 0)
+			assert.Contains(t, cityA.invaders, a.id)
+			assert.Contains(t, cityA.sieges, a.id)
+		})
+	}
+}
+
+// BenchmarkAlien_NotifyCh compares the completion signaling of many
+// aliens through an unbuffered and a buffered done channel
+func BenchmarkAlien_NotifyCh(b *testing.B) {
+	const numAliens = 100_000
+
+	benchTable := []struct {
+		name       string
+		bufferSize int
+	}{
+		{
+			"unbuffered",
+			0,
+		},
+		{
+			"buffered",
+			numAliens,
+		},
+	}
+
+	for _, benchCase := range benchTable {
+		benchCase := benchCase
+
+		b.Run(benchCase.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var (
+					wg     sync.WaitGroup
+					doneCh = make(chan struct{}, benchCase.bufferSize)
+				)
+
+				wg.Add(numAliens)
+
+				for j := 0; j < numAliens; j++ {
+					go func() {
+						defer wg.Done()
+
+						notifyCh(context.Background(), doneCh)
+					}()
+				}
+
+				for j := 0; j < numAliens; j++ {
+					<-doneCh
+				}
+
+				wg.Wait()
+			}
+		})
+	}
+}
+
+// siegeEveryNeighbor returns a movement behavior that lays siege to every accessible
+// neighbor, and selects one of them with the given selector (or none)
+func siegeEveryNeighbor(selector func(current *city) *city) movementBehavior {
+	return func(a *alien, current *city) *city {
+		for _, neighbor := range current.neighbors {
+			if !neighbor.isDestroyed() {
+				neighbor.laySiege(a.id)
+			}
+		}
+
+		return selector(current)
+	}
+}
+
+// TestAlien_DeathPaths_NoOrphanedSieges makes sure no siege is left behind
+// on any of the paths the alien stops on. The only sieges left are the ones of the
+// aliens in their cities, so every siege is held by an invader of the city
+func TestAlien_DeathPaths_NoOrphanedSieges(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		roads  [][2]string // the roads leading north from the first to the second city
+		setup  func(cities map[string]*city)
+		opts   []func(*alien) // the options of the alien, which starts in city A
+		notify bool           // flag indicating if the alien reports stopping
+
+		expectedSieges map[string]int
+	}{
+		{
+			"trapped without neighbors",
+			nil,
+			func(map[string]*city) {},
+			nil,
+			true,
+			map[string]int{"A": 1},
+		},
+		{
+			"neighbor destroyed",
+			[][2]string{{"A", "B"}},
+			func(cities map[string]*city) {
+				cities["B"].tryInvade(1, nil)
+				cities["B"].tryInvade(2, nil)
+			},
+			nil,
+			true,
+			map[string]int{"A": 1, "B": 2},
+		},
+		{
+			"current city destroyed",
+			[][2]string{{"A", "B"}},
+			func(cities map[string]*city) {
+				cities["A"].tryInvade(1, nil)
+			},
+			nil,
+			true,
+			map[string]int{"A": 2, "B": 0},
+		},
+		{
+			"next city destroyed on arrival",
+			[][2]string{{"A", "B"}, {"B", "C"}},
+			func(cities map[string]*city) {
+				cities["B"].tryInvade(1, nil)
+			},
+			nil,
+			true,
+			map[string]int{"A": 0, "B": 2, "C": 0},
+		},
+		{
+			"max moves reached",
+			[][2]string{{"A", "B"}},
+			func(map[string]*city) {},
+			[]func(*alien){withMaxMoves(1)},
+			true,
+			map[string]int{"A": 0, "B": 1},
+		},
+		{
+			"starved",
+			[][2]string{{"A", "B"}},
+			func(map[string]*city) {},
+			[]func(*alien){withAlienEnergy(1)},
+			true,
+			map[string]int{"A": 0, "B": 1},
+		},
+		{
+			"total move budget exhausted",
+			[][2]string{{"A", "B"}},
+			func(map[string]*city) {},
+			[]func(*alien){withMoveBudget(newMoveBudget(1, func() {}))},
+			false,
+			map[string]int{"A": 0, "B": 1},
+		},
+		{
+			"behavior sieging every neighbor",
+			[][2]string{{"A", "B"}, {"C", "A"}},
+			func(map[string]*city) {},
+			[]func(*alien){
+				withMaxMoves(1),
+				withBehavior(siegeEveryNeighbor(lowestNameNeighbor)),
+			},
+			true,
+			map[string]int{"A": 0, "B": 1, "C": 0},
+		},
+		{
+			"behavior sieging every neighbor, then giving up",
+			[][2]string{{"A", "B"}, {"C", "A"}},
+			func(map[string]*city) {},
+			[]func(*alien){
+				withBehavior(siegeEveryNeighbor(func(*city) *city {
+					return nil
+				})),
+			},
+			true,
+			map[string]int{"A": 1, "B": 0, "C": 0},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			cities := map[string]*city{
+				"A": newCity("A"),
+			}
+
+			for _, road := range testCase.roads {
+				for _, name := range road {
+					if _, ok := cities[name]; !ok {
+						cities[name] = newCity(name)
+					}
+				}
+
+				cities[road[0]].addNeighbor(north, cities[road[1]])
+				cities[road[1]].addNeighbor(south, cities[road[0]])
+			}
+
+			// Place the alien in city A
+			a := newAlien(0, testCase.opts...)
+			cities["A"].tryInvade(a.id, nil)
+
+			testCase.setup(cities)
+
+			var (
+				alienDoneCh = make(chan struct{}, 1)
+				stoppedCh   = make(chan struct{})
+			)
+
+			ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelFn()
+
+			go func() {
+				defer close(stoppedCh)
+
+				a.runAlien(ctx, cities["A"], alienDoneCh)
+			}()
+
+			select {
+			case <-ctx.Done():
+				t.Fatal("alien did not stop in time")
+			case <-stoppedCh:
+			}
+
+			assert.Equal(t, testCase.notify, len(alienDoneCh) == 1)
+
+			for name, c := range cities {
+				assert.Len(t, c.sieges, testCase.expectedSieges[name], "sieges of city %s", name)
+
+				for id := range c.sieges {
+					assert.Contains(t, c.invaders, id, "orphaned siege of alien %d in city %s", id, name)
+				}
+			}
+		})
+	}
+}
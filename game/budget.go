@@ -0,0 +1,36 @@
+package game
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// moveBudget is the budget of the total moves across all aliens of a simulation
+type moveBudget struct {
+	moves int64 // the number of moves made so far, kept first for 64-bit alignment
+
+	limit     int64     // the max number of total moves
+	exhausted func()    // the callback stopping the simulation, once the budget is used up
+	once      sync.Once // makes sure the simulation is stopped only once
+}
+
+// newMoveBudget creates a budget of the given total moves,
+// which calls the exhausted callback once it's used up
+func newMoveBudget(limit int, exhausted func()) *moveBudget {
+	return &moveBudget{
+		limit:     int64(limit),
+		exhausted: exhausted,
+	}
+}
+
+// spend spends a single move from the budget.
+// Returns false if this move used up the budget, or it was already used up
+func (b *moveBudget) spend() bool {
+	if atomic.AddInt64(&b.moves, 1) < b.limit {
+		return true
+	}
+
+	b.once.Do(b.exhausted)
+
+	return false
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestMoveBudget_Spend makes sure the budget is used up
+// on the final move, and the simulation is stopped only once
+func TestMoveBudget_Spend(t *testing.T) {
+	t.Parallel()
+
+	stops := 0
+	budget := newMoveBudget(3, func() {
+		stops++
+	})
+
+	assert.True(t, budget.spend())
+	assert.True(t, budget.spend())
+	assert.False(t, budget.spend())
+	assert.False(t, budget.spend())
+
+	assert.Equal(t, 1, stops)
+}
+
+// TestMap_SimulateInvasion_MaxTotalMoves makes sure the simulation
+// stops once the aliens make the total number of moves
+func TestMap_SimulateInvasion_MaxTotalMoves(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single alien", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewEarthMap(hclog.NewNullLogger(), WithMaxTotalMoves(50))
+		assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo north=Bar", "Bar south=Foo"})))
+
+		result, err := m.SimulateInvasion(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("unable to simulate the invasion, %v", err)
+		}
+
+		// The lone alien makes every move of the budget
+		assert.Equal(t, 50, result.TotalCost)
+		assert.True(t, result.MoveBudgetExhausted)
+		assert.False(t, result.Interrupted)
+		assert.Equal(t, 1, result.SurvivingAliens)
+	})
+
+	t.Run("many aliens", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			numAliens     = 20
+			maxTotalMoves = 500
+		)
+
+		m := GenerateGridMap(10, 10)
+		WithMaxTotalMoves(maxTotalMoves)(m)
+
+		result, err := m.SimulateInvasion(context.Background(), numAliens)
+		if err != nil {
+			t.Fatalf("unable to simulate the invasion, %v", err)
+		}
+
+		// Each of the other aliens can complete at most
+		// a single move already under way, once the budget is used up
+		assert.LessOrEqual(t, result.TotalCost, maxTotalMoves+numAliens-1)
+
+		if result.MoveBudgetExhausted {
+			assert.GreaterOrEqual(t, result.TotalCost, maxTotalMoves)
+		}
+	})
+}
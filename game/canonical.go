@@ -0,0 +1,99 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// canonicalLines returns the canonical form of the earth map.
+// The canonical form consists of one line per city, sorted by the city name,
+// with the neighbors listed in the fixed north, south, east, west order.
+// Every road is emitted from both sides, and every city has its own line
+func (m *EarthMap) canonicalLines() []string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	names := make([]string, 0, len(m.cityMap))
+	for name := range m.cityMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+
+	for _, name := range names {
+		var (
+			sb   strings.Builder
+			city = m.cityMap[name]
+		)
+
+		sb.WriteString(city.name)
+
+		for _, direction := range directions {
+			neighbor, ok := city.neighbors[direction]
+			if !ok {
+				continue
+			}
+
+			sb.WriteString(
+				fmt.Sprintf(
+					" %s=%s",
+					direction.getName(),
+					neighbor.name,
+				),
+			)
+		}
+
+		lines = append(lines, sb.String())
+	}
+
+	return lines
+}
+
+// Canonical returns the canonical form of the earth map as text,
+// which is identical for any two logically identical maps
+func (m *EarthMap) Canonical() string {
+	return strings.Join(m.canonicalLines(), "\n")
+}
+
+// CanonicalizeOutput parses the map input lines, and returns them in canonical form:
+// cities sorted by name, neighbors in the fixed north, south, east, west order,
+// and every road emitted from both sides, with implicitly defined cities getting
+// their own line. Empty lines are ignored, while any other invalid line is an error
+func CanonicalizeOutput(lines []string) ([]string, error) {
+	m := NewEarthMap(hclog.NewNullLogger())
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if err := m.addCityLine(strings.TrimSuffix(line, "\n")); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.canonicalLines(), nil
+}
+
+// MapsEquivalent checks if the two earth maps have the same
+// cities and roads, regardless of how they were constructed
+func MapsEquivalent(a, b *EarthMap) bool {
+	linesA, linesB := a.canonicalLines(), b.canonicalLines()
+
+	if len(linesA) != len(linesB) {
+		return false
+	}
+
+	for i := range linesA {
+		if linesA[i] != linesB[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestCanonical_CanonicalizeOutput makes sure scrambled
+// but equivalent map files canonicalize identically
+func TestCanonical_CanonicalizeOutput(t *testing.T) {
+	t.Parallel()
+
+	var (
+		original = []string{
+			"Foo north=Bar west=Baz south=Qu-ux",
+			"Bar south=Foo west=Bee",
+		}
+
+		// Same map, with different line and neighbor order,
+		// and explicitly defined reverse roads
+		scrambled = []string{
+			"Bee east=Bar\n",
+			"",
+			"Qu-ux north=Foo",
+			"Bar west=Bee south=Foo",
+			"Foo south=Qu-ux west=Baz north=Bar",
+			"Baz east=Foo",
+		}
+
+		expected = []string{
+			"Bar south=Foo west=Bee",
+			"Baz east=Foo",
+			"Bee east=Bar",
+			"Foo north=Bar south=Qu-ux west=Baz",
+			"Qu-ux north=Foo",
+		}
+	)
+
+	canonicalOriginal, err := CanonicalizeOutput(original)
+	if err != nil {
+		t.Fatalf("unable to canonicalize output, %v", err)
+	}
+
+	canonicalScrambled, err := CanonicalizeOutput(scrambled)
+	if err != nil {
+		t.Fatalf("unable to canonicalize output, %v", err)
+	}
+
+	assert.Equal(t, expected, canonicalOriginal)
+	assert.Equal(t, expected, canonicalScrambled)
+}
+
+// TestCanonical_CanonicalizeOutput_Invalid makes sure
+// invalid lines are rejected
+func TestCanonical_CanonicalizeOutput_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := CanonicalizeOutput([]string{
+		"Foo north=Bar",
+		" north=Baz",
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidCityLine)
+}
+
+// TestCanonical_MapsEquivalent makes sure logically identical
+// maps are considered equivalent
+func TestCanonical_MapsEquivalent(t *testing.T) {
+	t.Parallel()
+
+	newMap := func(lines ...string) *EarthMap {
+		m := NewEarthMap(hclog.NewNullLogger())
+		m.InitMap(stream.NewSliceReader(lines))
+
+		return m
+	}
+
+	var (
+		a = newMap("Foo north=Bar", "Bar east=Baz")
+		b = newMap("Baz west=Bar", "Bar south=Foo")
+		c = newMap("Foo north=Bar", "Bar west=Baz")
+	)
+
+	assert.True(t, MapsEquivalent(a, b))
+	assert.True(t, MapsEquivalent(a, a.Clone()))
+	assert.Equal(t, a.Canonical(), b.Canonical())
+
+	assert.False(t, MapsEquivalent(a, c))
+	assert.False(t, MapsEquivalent(a, newMap("Foo north=Bar")))
+}
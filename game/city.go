@@ -2,6 +2,8 @@ package game
 
 import (
 	"fmt"
+	"math/rand"
+	"sort"
 	"sync"
 
 	"github.com/hashicorp/go-hclog"
@@ -12,8 +14,13 @@ type direction int
 const (
 	numDirections   = 4 // There are only 4 directions
 	maxInvaderCount = 2 // There can only be 2 invaders at the same time
+
+	defaultTravelCost = 1 // Roads without an explicit cost take a single move to travel
 )
 
+// DestructionThreshold is the number of invaders that destroy a city
+const DestructionThreshold = maxInvaderCount
+
 // Possible directions
 const (
 	north direction = iota
@@ -22,6 +29,9 @@ const (
 	west
 )
 
+// directions holds all possible directions, in their canonical order
+var directions = []direction{north, south, east, west}
+
 // getOpposite returns the opposite direction for the given
 // direction
 func (d direction) getOpposite() direction {
@@ -51,6 +61,18 @@ func (d direction) getName() string {
 	}
 }
 
+// directionFromName returns the direction with the given name,
+// and a flag indicating if the direction name is valid
+func directionFromName(name string) (direction, bool) {
+	for _, direction := range directions {
+		if direction.getName() == name {
+			return direction, true
+		}
+	}
+
+	return north, false
+}
+
 // neighbors holds information on the adjacent cities
 type neighbors map[direction]*city
 
@@ -58,13 +80,19 @@ type neighbors map[direction]*city
 type city struct {
 	sync.RWMutex
 
-	name      string       // the name of the city
-	neighbors neighbors    // the adjacent neighboring cities
-	log       hclog.Logger // a logger instance
+	name      string            // the name of the city
+	neighbors neighbors         // the adjacent neighboring cities
+	costs     map[direction]int // the travel costs of the roads, if different from the default cost
+	declared  []direction       // the directions in the order they were declared in the input, if tracked
+	log       hclog.Logger      // a logger instance
+
+	destructionListener func(Destruction) // the listener notified of the city destruction, if any
+	collision           CollisionStrategy // the outcome of two aliens meeting in the city
 
 	destroyed bool             // flag indicating if the city has been destroyed
 	invaders  map[int]struct{} // set of currently present invaders
 	sieges    map[int]struct{} // set of currently present sieges. Sieges act as "reservations" for invasions
+	defeated  map[int]struct{} // set of aliens killed in the city in a fight, which can't leave it
 }
 
 // withLogger sets a specific city logger
@@ -74,13 +102,30 @@ func withLogger(log hclog.Logger) func(*city) {
 	}
 }
 
+// withDestructionListener sets a specific city destruction listener
+func withDestructionListener(listener func(Destruction)) func(*city) {
+	return func(c *city) {
+		c.destructionListener = listener
+	}
+}
+
+// withCollisionStrategy sets a specific city collision strategy
+func withCollisionStrategy(strategy CollisionStrategy) func(*city) {
+	return func(c *city) {
+		c.collision = strategy
+	}
+}
+
 // newCity generates a new city instance
 func newCity(name string, opts ...func(*city)) *city {
 	c := &city{
 		name:      name,
 		neighbors: make(map[direction]*city),
+		costs:     make(map[direction]int),
 		invaders:  make(map[int]struct{}),
 		sieges:    make(map[int]struct{}),
+		defeated:  make(map[int]struct{}),
+		collision: DefaultCollisionStrategy,
 		log:       hclog.NewNullLogger(),
 	}
 
@@ -95,19 +140,107 @@ func newCity(name string, opts ...func(*city)) *city {
 // Additionally, it overwrites the previous neighbor entry, if any
 func (c *city) addNeighbor(direction direction, city *city) {
 	c.neighbors[direction] = city
+
+	// The new road has the default travel cost
+	delete(c.costs, direction)
+}
+
+// setCost sets the travel cost of the road in the specified direction
+func (c *city) setCost(direction direction, cost int) {
+	if cost == defaultTravelCost {
+		delete(c.costs, direction)
+
+		return
+	}
+
+	c.costs[direction] = cost
+}
+
+// getCost returns the travel cost of the road in the specified direction
+func (c *city) getCost(direction direction) int {
+	if cost, ok := c.costs[direction]; ok {
+		return cost
+	}
+
+	return defaultTravelCost
+}
+
+// costTo returns the travel cost of the road to the given neighbor
+func (c *city) costTo(neighbor *city) int {
+	for direction, adjacent := range c.neighbors {
+		if adjacent == neighbor {
+			return c.getCost(direction)
+		}
+	}
+
+	return defaultTravelCost
+}
+
+// declareDirection records that the direction was declared
+// in the city's input line, keeping the declaration order
+func (c *city) declareDirection(direction direction) {
+	for _, declared := range c.declared {
+		if declared == direction {
+			return
+		}
+	}
+
+	c.declared = append(c.declared, direction)
+}
+
+// outputDirections returns the order in which the city's neighbors are output.
+// The declared directions come first, followed by the rest in the fixed
+// north, south, east, west order
+func (c *city) outputDirections() []direction {
+	if len(c.declared) == 0 {
+		return directions
+	}
+
+	ordered := make([]direction, 0, len(directions))
+	ordered = append(ordered, c.declared...)
+
+	for _, direction := range directions {
+		isDeclared := false
+
+		for _, declared := range c.declared {
+			if declared == direction {
+				isDeclared = true
+
+				break
+			}
+		}
+
+		if !isDeclared {
+			ordered = append(ordered, direction)
+		}
+	}
+
+	return ordered
 }
 
 // removeNeighbor removes a neighboring city in the
 // specified direction
 func (c *city) removeNeighbor(direction direction) {
 	delete(c.neighbors, direction)
+	delete(c.costs, direction)
+}
+
+// isNeighbor checks if the given city is adjacent to the city
+func (c *city) isNeighbor(other *city) bool {
+	for _, neighbor := range c.neighbors {
+		if neighbor == other {
+			return true
+		}
+	}
+
+	return false
 }
 
 // hasAccessibleNeighbors checks travel is possible to
 // neighbors of a given city
 func (c *city) hasAccessibleNeighbors() bool {
 	for _, neighbor := range c.neighbors {
-		if !neighbor.isDestroyed() {
+		if neighbor.isAccessible() {
 			return true
 		}
 	}
@@ -122,24 +255,47 @@ func (c *city) hasAccessibleNeighbors() bool {
 //   - the city doesn't have 2 invaders present
 // [Thread safe]
 func (c *city) addInvader(alienID int) {
+	if destruction := c.placeInvader(alienID); destruction != nil {
+		c.notifyDestruction(*destruction)
+	}
+}
+
+// placeInvader adds an invader to the city, under the city lock.
+// Returns the destruction of the city, if the invader destroyed it
+func (c *city) placeInvader(alienID int) *Destruction {
 	c.Lock()
 	defer c.Unlock()
 
 	// Check if this alien has laid siege beforehand
 	_, hasSiege := c.sieges[alienID]
 	if !hasSiege {
-		return
+		return nil
+	}
+
+	// A repeated invasion doesn't change the number of invaders,
+	// and points to a logic bug in the alien movement
+	if _, isInvader := c.invaders[alienID]; isInvader {
+		c.log.Warn(
+			fmt.Sprintf("Alien %d is already invading the city", alienID),
+		)
+
+		return nil
 	}
 
 	// Increase the number of invaders in a city
 	c.invaders[alienID] = struct{}{}
 
-	// Check if the city is destroyed
-	if c.numInvaders() == maxInvaderCount {
+	// Check if the city is destroyed, only on the
+	// transition to the max invader count
+	if !c.destroyed && c.numInvaders() == maxInvaderCount {
 		// Mark the city as destroyed, print the invaders
 		c.destroyed = true
-		c.printInvaders()
+		destruction := c.printInvaders()
+
+		return &destruction
 	}
+
+	return nil
 }
 
 // removeInvader removes an invader from the city.
@@ -156,6 +312,11 @@ func (c *city) removeInvader(alienID int) bool {
 		return false
 	}
 
+	// Aliens killed in a fight can't leave the city either
+	if _, isDefeated := c.defeated[alienID]; isDefeated {
+		return false
+	}
+
 	delete(c.invaders, alienID)
 	delete(c.sieges, alienID)
 
@@ -172,8 +333,9 @@ func (c *city) numSieges() int {
 	return len(c.sieges)
 }
 
-// printInvaders prints the current invaders in the city [NOT Thread safe]
-func (c *city) printInvaders() {
+// printInvaders prints the current invaders in the city,
+// and returns the destruction they caused [NOT Thread safe]
+func (c *city) printInvaders() Destruction {
 	invaders := make([]int, len(c.invaders))
 
 	i := 0
@@ -183,6 +345,8 @@ func (c *city) printInvaders() {
 		i++
 	}
 
+	sort.Ints(invaders)
+
 	c.log.Info(
 		fmt.Sprintf(
 			"City has been destroyed by aliens %d and %d!",
@@ -190,6 +354,20 @@ func (c *city) printInvaders() {
 			invaders[1],
 		),
 	)
+
+	return Destruction{
+		City:   c.name,
+		Aliens: invaders,
+	}
+}
+
+// notifyDestruction notifies the destruction listener of the city destruction, if the
+// listener is set. It must be called without holding the city lock, since the listener
+// is free to query the map
+func (c *city) notifyDestruction(destruction Destruction) {
+	if c.destructionListener != nil {
+		c.destructionListener(destruction)
+	}
 }
 
 // isDestroyed returns a flag indicating if a city has been
@@ -201,6 +379,16 @@ func (c *city) isDestroyed() bool {
 	return c.destroyed
 }
 
+// isAccessible checks if aliens can move to the city. A city spared by the collision
+// strategy is not accessible while the aliens that met in it are still there,
+// otherwise the aliens trapped around it would wait for each other for good [Thread safe]
+func (c *city) isAccessible() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return !c.destroyed && c.numInvaders() < maxInvaderCount
+}
+
 // laySiege attempts to lay siege on the city.
 // Returns a flag indicating if the siege was successful
 func (c *city) laySiege(id int) bool {
@@ -216,6 +404,84 @@ func (c *city) laySiege(id int) bool {
 	return true
 }
 
+// tryInvade lays siege to the city (unless the alien already holds one), and invades it,
+// as a single operation. Since there are at most 2 sieges, there are at most 2 invaders,
+// and no other alien can take the siege between the two steps. The meeting with
+// the alien already in the city is resolved by the city's collision strategy, where the
+// random number generator picks the winner of a fight. The destruction listener is
+// notified once the city lock is released.
+// Returns flags indicating if the alien invaded the city, and if the invasion destroyed it
+// [Thread safe]
+func (c *city) tryInvade(id int, rng *rand.Rand) (invaded bool, destroyed bool) {
+	invaded, destruction := c.invade(id, rng)
+	if destruction == nil {
+		return invaded, false
+	}
+
+	c.notifyDestruction(*destruction)
+
+	return invaded, true
+}
+
+// invade lays siege to the city and invades it, under the city lock.
+// Returns a flag indicating if the alien invaded the city,
+// and the destruction of the city, if the invasion destroyed it
+func (c *city) invade(id int, rng *rand.Rand) (bool, *Destruction) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.destroyed {
+		return false, nil
+	}
+
+	if _, hasSiege := c.sieges[id]; !hasSiege {
+		if c.numSieges() == maxInvaderCount {
+			return false, nil
+		}
+
+		c.sieges[id] = struct{}{}
+	}
+
+	// A repeated invasion doesn't change the number of invaders,
+	// and points to a logic bug in the alien movement
+	if _, isInvader := c.invaders[id]; isInvader {
+		c.log.Warn(
+			fmt.Sprintf("Alien %d is already invading the city", id),
+		)
+
+		return true, nil
+	}
+
+	// The aliens meet on the transition to the max invader count
+	if c.numInvaders() == maxInvaderCount-1 {
+		return c.collide(id, rng)
+	}
+
+	c.invaders[id] = struct{}{}
+
+	return true, nil
+}
+
+// isDefeated checks if the given alien was killed in the city in a fight [Thread safe]
+func (c *city) isDefeated(id int) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	_, ok := c.defeated[id]
+
+	return ok
+}
+
+// hasSiege checks if the given alien holds a siege on the city [Thread safe]
+func (c *city) hasSiege(id int) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	_, ok := c.sieges[id]
+
+	return ok
+}
+
 // liftSiege removes a siege from the city
 func (c *city) liftSiege(id int) {
 	c.Lock()
@@ -1,9 +1,12 @@
 package game
 
 import (
+	"bytes"
 	"fmt"
+	"sync"
 	"testing"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -226,6 +229,209 @@ func TestCity_Invade(t *testing.T) {
 	}
 }
 
+// TestCity_AddInvader_Repeated makes sure re-adding a present
+// invader is reported, and never causes a spurious destruction
+func TestCity_AddInvader_Repeated(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name            string
+		initialInvaders []int
+		invader         int
+
+		expectedDestructions int
+	}{
+		{
+			"lone invader re-added",
+			[]int{0},
+			0,
+			0,
+		},
+		{
+			"invader of a destroyed city re-added",
+			[]int{0, 1},
+			1,
+			1,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				logs         bytes.Buffer
+				destructions int
+			)
+
+			// Create the city
+			c := newCity(
+				"city name",
+				withLogger(hclog.New(&hclog.LoggerOptions{Output: &logs})),
+				withDestructionListener(func(Destruction) {
+					destructions++
+				}),
+			)
+
+			// Add initial invaders
+			for _, invader := range testCase.initialInvaders {
+				assert.True(t, c.laySiege(invader))
+
+				c.addInvader(invader)
+			}
+
+			c.addInvader(testCase.invader)
+
+			// Make sure the invader count and the destruction are unchanged
+			assert.Len(t, c.invaders, len(testCase.initialInvaders))
+			assert.Equal(t, len(testCase.initialInvaders) == maxInvaderCount, c.destroyed)
+			assert.Equal(t, testCase.expectedDestructions, destructions)
+
+			assert.Contains(
+				t,
+				logs.String(),
+				fmt.Sprintf("Alien %d is already invading the city", testCase.invader),
+			)
+		})
+	}
+}
+
+// TestCity_TryInvade makes sure the siege and the invasion
+// happen together, and only the max number of invaders get in
+func TestCity_TryInvade(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name            string
+		initialSieges   []int
+		initialInvaders []int
+		invader         int
+
+		expectedInvaded   bool
+		expectedDestroyed bool
+	}{
+		{
+			"first invader",
+			[]int{},
+			[]int{},
+			0,
+			true,
+			false,
+		},
+		{
+			"second invader destroys the city",
+			[]int{0},
+			[]int{0},
+			1,
+			true,
+			true,
+		},
+		{
+			"invader holding a siege",
+			[]int{0, 1},
+			[]int{0},
+			1,
+			true,
+			true,
+		},
+		{
+			"sieges taken by other aliens",
+			[]int{0, 1},
+			[]int{},
+			2,
+			false,
+			false,
+		},
+		{
+			"city destroyed",
+			[]int{0, 1},
+			[]int{0, 1},
+			2,
+			false,
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newCity("city name")
+
+			for _, id := range testCase.initialSieges {
+				assert.True(t, c.laySiege(id))
+			}
+
+			for _, id := range testCase.initialInvaders {
+				c.addInvader(id)
+			}
+
+			invaded, destroyed := c.tryInvade(testCase.invader, nil)
+
+			assert.Equal(t, testCase.expectedInvaded, invaded)
+			assert.Equal(t, testCase.expectedDestroyed, destroyed)
+			assert.Equal(t, testCase.expectedInvaded, c.hasSiege(testCase.invader))
+
+			// There are never more invaders than sieges
+			assert.LessOrEqual(t, len(c.invaders), len(c.sieges))
+			assert.LessOrEqual(t, len(c.sieges), maxInvaderCount)
+		})
+	}
+}
+
+// TestCity_TryInvade_Concurrent makes sure only the max number of
+// concurrent invaders get in, and the city is destroyed exactly once
+func TestCity_TryInvade_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const numAliens = 50
+
+	var (
+		wg           sync.WaitGroup
+		mux          sync.Mutex
+		invaded      int
+		destroyed    int
+		destructions int
+	)
+
+	c := newCity("city name", withDestructionListener(func(Destruction) {
+		destructions++
+	}))
+
+	for id := 0; id < numAliens; id++ {
+		wg.Add(1)
+
+		go func(id int) {
+			defer wg.Done()
+
+			alienInvaded, alienDestroyed := c.tryInvade(id, nil)
+
+			mux.Lock()
+			defer mux.Unlock()
+
+			if alienInvaded {
+				invaded++
+			}
+
+			if alienDestroyed {
+				destroyed++
+			}
+		}(id)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, maxInvaderCount, invaded)
+	assert.Equal(t, 1, destroyed)
+	assert.Equal(t, 1, destructions)
+	assert.Len(t, c.invaders, maxInvaderCount)
+	assert.Len(t, c.sieges, maxInvaderCount)
+}
+
 // TestCity_RemoveInvader makes sure invaders are properly removed
 // from the city
 func TestCity_RemoveInvader(t *testing.T) {
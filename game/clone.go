@@ -0,0 +1,115 @@
+package game
+
+import (
+	"fmt"
+)
+
+// copyCities creates an independent copy of the earth map, containing only
+// the cities accepted by the filter and the roads between them.
+// The copy shares the logger and configuration with the original map
+func (m *EarthMap) copyCities(include func(*city) bool) *EarthMap {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	copied := &EarthMap{
+		log:         m.log,
+		cityMap:     make(map[string]*city),
+		prunedCount: m.prunedCount,
+		config:      m.config,
+		rng:         newRand(m.config.seed),
+	}
+
+	// Copy over the city state
+	for name, original := range m.cityMap {
+		if !include(original) {
+			continue
+		}
+
+		c := newCity(name, copied.cityOptions(original.log)...)
+
+		original.RLock()
+
+		c.destroyed = original.destroyed
+		c.declared = append(c.declared, original.declared...)
+
+		for id := range original.invaders {
+			c.invaders[id] = struct{}{}
+		}
+
+		for id := range original.sieges {
+			c.sieges[id] = struct{}{}
+		}
+
+		for id := range original.defeated {
+			c.defeated[id] = struct{}{}
+		}
+
+		original.RUnlock()
+
+		copied.cityMap[name] = c
+	}
+
+	// Relink the roads between the copied cities
+	for name, c := range copied.cityMap {
+		for direction, neighbor := range m.cityMap[name].neighbors {
+			if copiedNeighbor, ok := copied.cityMap[neighbor.name]; ok {
+				c.addNeighbor(direction, copiedNeighbor)
+				c.setCost(direction, m.cityMap[name].getCost(direction))
+			}
+		}
+	}
+
+	return copied
+}
+
+// Clone creates a deep copy of the earth map, which
+// can be used independently of the original map
+func (m *EarthMap) Clone() *EarthMap {
+	return m.copyCities(func(*city) bool {
+		return true
+	})
+}
+
+// Subgraph creates an independent map containing all cities within
+// radius hops of the center city, and only the roads between the included cities
+func (m *EarthMap) Subgraph(center string, radius int) (*EarthMap, error) {
+	m.mux.RLock()
+
+	centerCity := m.getCity(center)
+	if centerCity == nil {
+		m.mux.RUnlock()
+
+		return nil, fmt.Errorf("%w: %s", ErrCityNotFound, center)
+	}
+
+	// Gather the neighborhood using a breadth-first traversal
+	var (
+		included = map[*city]struct{}{centerCity: {}}
+		frontier = []*city{centerCity}
+	)
+
+	for hop := 0; hop < radius && len(frontier) > 0; hop++ {
+		next := make([]*city, 0)
+
+		for _, c := range frontier {
+			for _, neighbor := range c.neighbors {
+				if _, seen := included[neighbor]; seen {
+					continue
+				}
+
+				included[neighbor] = struct{}{}
+				next = append(next, neighbor)
+			}
+		}
+
+		frontier = next
+	}
+
+	m.mux.RUnlock()
+
+	return m.copyCities(func(c *city) bool {
+		_, ok := included[c]
+
+		return ok
+	}), nil
+}
@@ -0,0 +1,123 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// newLineMap creates a line graph map A-B-C-D-E,
+// with each city being east of the previous one
+func newLineMap() *EarthMap {
+	m := NewEarthMap(hclog.NewNullLogger())
+
+	m.InitMap(stream.NewSliceReader([]string{
+		"A east=B",
+		"B east=C",
+		"C east=D",
+		"D east=E",
+	}))
+
+	return m
+}
+
+// TestClone_Clone makes sure the cloned map is
+// an independent deep copy of the original
+func TestClone_Clone(t *testing.T) {
+	t.Parallel()
+
+	original := newLineMap()
+	original.getCity("B").destroyed = true
+	original.getCity("C").invaders[1] = struct{}{}
+
+	clone := original.Clone()
+
+	// Make sure the clone matches the original
+	assert.Equal(t, original.toJSONMap(), clone.toJSONMap())
+	assert.Len(t, clone.getCity("C").invaders, 1)
+
+	// Make sure the clone doesn't alias the original
+	for name, c := range clone.cityMap {
+		assert.NotSame(t, original.getCity(name), c)
+
+		for direction, neighbor := range c.neighbors {
+			assert.Same(t, clone.getCity(neighbor.name), neighbor)
+			assert.NotSame(t, original.getCity(name).neighbors[direction], neighbor)
+		}
+	}
+
+	// Make sure changes to the clone are not reflected in the original
+	_, err := clone.RemoveCity("C", false)
+	assert.NoError(t, err)
+
+	clone.getCity("A").destroyed = true
+
+	assert.Len(t, original.cityMap, 5)
+	assert.False(t, original.getCity("A").destroyed)
+	assert.Equal(t, original.getCity("C"), original.getCity("B").neighbors[east])
+}
+
+// TestClone_Subgraph makes sure the subgraph contains exactly
+// the cities within the given radius
+func TestClone_Subgraph(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		radius int
+
+		expectedCities []string
+	}{
+		{
+			"radius 0",
+			0,
+			[]string{"C"},
+		},
+		{
+			"radius 1",
+			1,
+			[]string{"B", "C", "D"},
+		},
+		{
+			"radius 2",
+			2,
+			[]string{"A", "B", "C", "D", "E"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			original := newLineMap()
+
+			subgraph, err := original.Subgraph("C", testCase.radius)
+			if err != nil {
+				t.Fatalf("unable to extract subgraph, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedCities, subgraph.Cities())
+
+			// Make sure only the roads between included cities are present
+			assert.NoError(t, subgraph.CheckInvariants())
+
+			// Make sure the original map is unchanged
+			assert.Len(t, original.cityMap, 5)
+		})
+	}
+}
+
+// TestClone_Subgraph_UnknownCenter makes sure an unknown
+// subgraph center city is rejected
+func TestClone_Subgraph_UnknownCenter(t *testing.T) {
+	t.Parallel()
+
+	subgraph, err := newLineMap().Subgraph("Z", 1)
+
+	assert.ErrorIs(t, err, ErrCityNotFound)
+	assert.Nil(t, subgraph)
+}
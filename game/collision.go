@@ -0,0 +1,102 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// CollisionStrategy decides the outcome of two aliens meeting in a city
+type CollisionStrategy string
+
+// Define the collision strategies
+const (
+	CollisionDestroy     CollisionStrategy = "destroy"      // the city is destroyed, and both aliens die
+	CollisionWinnerStays CollisionStrategy = "winner-stays" // a random alien survives and stays, and the city is spared
+	CollisionFlee        CollisionStrategy = "flee"         // both aliens survive and move on, and the city is spared
+
+	// DefaultCollisionStrategy is the collision strategy used if none is chosen
+	DefaultCollisionStrategy = CollisionDestroy
+)
+
+var (
+	ErrUnknownCollisionStrategy = errors.New("unknown collision strategy")
+)
+
+// CollisionStrategies returns the sorted names of the collision strategies
+func CollisionStrategies() []string {
+	return []string{
+		string(CollisionDestroy),
+		string(CollisionFlee),
+		string(CollisionWinnerStays),
+	}
+}
+
+// ParseCollisionStrategy returns the collision strategy with the given name
+func ParseCollisionStrategy(name string) (CollisionStrategy, error) {
+	for _, strategy := range CollisionStrategies() {
+		if name == strategy {
+			return CollisionStrategy(name), nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"%w: %s (available: %s)",
+		ErrUnknownCollisionStrategy,
+		name,
+		strings.Join(CollisionStrategies(), ", "),
+	)
+}
+
+// collide resolves the arrival of the alien in the city that already has an invader,
+// according to the city's collision strategy. The random number generator picks the
+// winner of a fight. Returns a flag indicating if the alien invaded the city,
+// and the destruction of the city, if the collision destroyed it [NOT Thread safe]
+func (c *city) collide(id int, rng *rand.Rand) (bool, *Destruction) {
+	switch c.collision {
+	case CollisionWinnerStays:
+		return c.fight(id, rng), nil
+	case CollisionFlee:
+		// Both aliens leave the city on their next move
+		c.invaders[id] = struct{}{}
+
+		invaders := sortedIDs(c.invaders)
+
+		c.log.Info(fmt.Sprintf("Aliens %d and %d met in the city, and flee", invaders[0], invaders[1]))
+
+		return true, nil
+	default:
+		c.invaders[id] = struct{}{}
+		c.destroyed = true
+		destruction := c.printInvaders()
+
+		return true, &destruction
+	}
+}
+
+// fight makes the arriving alien fight the alien in the city, where a random one
+// survives and stays in the city, and the other one dies. The alien killed in the city
+// can't leave it anymore. Returns a flag indicating if the arriving alien won [NOT Thread safe]
+func (c *city) fight(id int, rng *rand.Rand) bool {
+	resident := sortedIDs(c.invaders)[0]
+
+	winner, loser := id, resident
+	if rng.Intn(2) == 0 {
+		winner, loser = resident, id
+	}
+
+	c.log.Info(fmt.Sprintf("Alien %d has killed alien %d in a fight over the city", winner, loser))
+
+	delete(c.sieges, loser)
+
+	if loser == id {
+		return false
+	}
+
+	delete(c.invaders, resident)
+	c.defeated[resident] = struct{}{}
+	c.invaders[id] = struct{}{}
+
+	return true
+}
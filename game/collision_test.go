@@ -0,0 +1,259 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestParseCollisionStrategy makes sure the collision
+// strategies are parsed, and unknown ones are reported
+func TestParseCollisionStrategy(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range CollisionStrategies() {
+		strategy, err := ParseCollisionStrategy(name)
+
+		assert.NoError(t, err)
+		assert.Equal(t, CollisionStrategy(name), strategy)
+	}
+
+	_, err := ParseCollisionStrategy("truce")
+
+	assert.ErrorIs(t, err, ErrUnknownCollisionStrategy)
+	assert.ErrorContains(t, err, "destroy, flee, winner-stays")
+}
+
+// TestCity_TryInvade_Collision makes sure the meeting of two
+// aliens in a city is resolved by the collision strategy
+func TestCity_TryInvade_Collision(t *testing.T) {
+	t.Parallel()
+
+	t.Run("destroy", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCity("Foo", withCollisionStrategy(CollisionDestroy))
+		c.tryInvade(0, nil)
+
+		invaded, destroyed := c.tryInvade(1, nil)
+
+		assert.True(t, invaded)
+		assert.True(t, destroyed)
+		assert.False(t, c.removeInvader(0))
+	})
+
+	t.Run("flee", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCity("Foo", withCollisionStrategy(CollisionFlee))
+		c.tryInvade(0, nil)
+
+		invaded, destroyed := c.tryInvade(1, nil)
+
+		assert.True(t, invaded)
+		assert.False(t, destroyed)
+		assert.False(t, c.isDestroyed())
+		assert.Len(t, c.invaders, 2)
+
+		// Both aliens can leave the city
+		assert.True(t, c.removeInvader(0))
+		assert.True(t, c.removeInvader(1))
+	})
+
+	t.Run("winner stays", func(t *testing.T) {
+		t.Parallel()
+
+		outcomes := make(map[bool]int)
+
+		for seed := int64(0); seed < 20; seed++ {
+			c := newCity("Foo", withCollisionStrategy(CollisionWinnerStays))
+			c.tryInvade(0, nil)
+
+			// The resident alien wins if the first coin flip is 0
+			residentWins := newRand(seed).Intn(2) == 0
+
+			invaded, destroyed := c.tryInvade(1, newRand(seed))
+
+			assert.Equal(t, !residentWins, invaded)
+			assert.False(t, destroyed)
+			assert.False(t, c.isDestroyed())
+			assert.Len(t, c.invaders, 1)
+			assert.Len(t, c.sieges, 1)
+
+			if residentWins {
+				assert.Contains(t, c.invaders, 0)
+				assert.False(t, c.isDefeated(0))
+			} else {
+				// The resident alien is killed, and can't leave the city
+				assert.Contains(t, c.invaders, 1)
+				assert.True(t, c.isDefeated(0))
+				assert.False(t, c.removeInvader(0))
+			}
+
+			outcomes[residentWins]++
+		}
+
+		// Either alien can win the fight
+		assert.Len(t, outcomes, 2)
+	})
+}
+
+// TestStepper_CollisionStrategy makes sure the collision strategy decides
+// how many aliens survive, when two aliens are bound to meet
+func TestStepper_CollisionStrategy(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		strategy          CollisionStrategy
+		expectedSurvivors int
+		expectedDestroyed int
+	}{
+		{CollisionDestroy, 0, 1},
+		{CollisionWinnerStays, 1, 0},
+		{CollisionFlee, 2, 0},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(string(testCase.strategy), func(t *testing.T) {
+			t.Parallel()
+
+			for seed := int64(0); seed < 10; seed++ {
+				// The aliens either land in the same city, or
+				// meet in the first tick, swapping the cities
+				m := newStepperMap(
+					t,
+					[]string{"Foo east=Bar"},
+					WithSeed(seed),
+					WithMaxMoves(10),
+					WithCollisionStrategy(testCase.strategy),
+				)
+
+				s, err := m.NewStepper(2)
+				if err != nil {
+					t.Fatalf("unable to create the stepper, %v", err)
+				}
+
+				s.Run()
+
+				result := s.Conclude()
+
+				assert.Equal(t, testCase.expectedSurvivors, result.SurvivingAliens)
+				assert.Equal(t, testCase.expectedDestroyed, result.CitiesDestroyed)
+				assert.NoError(t, m.CheckInvariants())
+			}
+		})
+	}
+}
+
+// TestMap_SimulateInvasion_CollisionStrategy makes sure the cities are
+// only destroyed with the default collision strategy, and no alien dies
+// when the aliens flee from each other
+func TestMap_SimulateInvasion_CollisionStrategy(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"A east=B south=D",
+		"B east=C south=E",
+		"C south=F",
+		"D east=E",
+		"E east=F",
+	}
+
+	testTable := []struct {
+		strategy CollisionStrategy
+		verify   func(t *testing.T, result SimulationResult)
+	}{
+		{
+			CollisionDestroy,
+			func(t *testing.T, result SimulationResult) {
+				t.Helper()
+
+				// Each destroyed city takes two aliens with it
+				assert.Greater(t, result.CitiesDestroyed, 0)
+				assert.LessOrEqual(t, result.SurvivingAliens, result.Aliens-2*result.CitiesDestroyed)
+			},
+		},
+		{
+			CollisionWinnerStays,
+			func(t *testing.T, result SimulationResult) {
+				t.Helper()
+
+				assert.Equal(t, 0, result.CitiesDestroyed)
+				assert.GreaterOrEqual(t, result.SurvivingAliens, 1)
+				assert.LessOrEqual(t, result.SurvivingAliens, result.Aliens)
+			},
+		},
+		{
+			CollisionFlee,
+			func(t *testing.T, result SimulationResult) {
+				t.Helper()
+
+				assert.Equal(t, 0, result.CitiesDestroyed)
+				assert.Equal(t, result.Aliens, result.SurvivingAliens)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(string(testCase.strategy), func(t *testing.T) {
+			t.Parallel()
+
+			m := NewEarthMap(
+				hclog.NewNullLogger(),
+				WithSeed(42),
+				WithMaxMoves(100),
+				WithDebugChecks(),
+				WithCollisionStrategy(testCase.strategy),
+			)
+
+			assert.NoError(t, m.InitMap(stream.NewSliceReader(lines)))
+
+			ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelFn()
+
+			result, err := m.SimulateInvasion(ctx, 6)
+
+			assert.NoError(t, err)
+			testCase.verify(t, result)
+			assert.NoError(t, m.CheckInvariants())
+		})
+	}
+}
+
+// TestStepper_CollisionFlee_Trapped makes sure the aliens surrounded by the cities
+// the other aliens fled into are trapped, instead of waiting for each other for good
+func TestStepper_CollisionFlee_Trapped(t *testing.T) {
+	t.Parallel()
+
+	for seed := int64(0); seed < 10; seed++ {
+		// Both cities hold two aliens that met in them,
+		// so no alien can move to the other city
+		m := newStepperMap(
+			t,
+			[]string{"Foo east=Bar"},
+			WithSeed(seed),
+			WithMaxMoves(10),
+			WithCollisionStrategy(CollisionFlee),
+		)
+
+		s, err := m.NewStepper(4)
+		if err != nil {
+			t.Fatalf("unable to create the stepper, %v", err)
+		}
+
+		s.Run()
+
+		result := s.Conclude()
+
+		assert.Equal(t, 0, result.CitiesDestroyed)
+		assert.NoError(t, m.CheckInvariants())
+	}
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"sort"
+)
+
+// MapDiff is the difference between two maps,
+// such as the map before and after the invasion
+type MapDiff struct {
+	DestroyedCities []string      `json:"destroyedCities"` // the sorted names of the cities destroyed or missing after
+	SeveredRoads    []SeveredRoad `json:"severedRoads"`    // the roads out of the surviving cities missing after
+}
+
+// SeveredRoad is a road out of a surviving city that is no longer on the map
+type SeveredRoad struct {
+	City      string `json:"city"`      // the name of the surviving city
+	Direction string `json:"direction"` // the direction of the road (north, south, east or west)
+	Neighbor  string `json:"neighbor"`  // the name of the city the road led to
+}
+
+// DiffMaps compares the map before the invasion to the map after it (for example,
+// a clone of the map and the map itself after the simulation). The cities that are destroyed,
+// whether pruned out or not, are reported as destroyed, and the roads out of the surviving
+// cities that are no longer there are reported as severed. The roads out of the destroyed
+// cities are not reported, since they are gone along with the cities. Neither map is changed
+func DiffMaps(before, after *EarthMap) MapDiff {
+	before.mux.RLock()
+	defer before.mux.RUnlock()
+
+	after.mux.RLock()
+	defer after.mux.RUnlock()
+
+	diff := MapDiff{
+		DestroyedCities: make([]string, 0),
+		SeveredRoads:    make([]SeveredRoad, 0),
+	}
+
+	names := make([]string, 0, len(before.cityMap))
+	for name := range before.cityMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		beforeCity := before.cityMap[name]
+		if beforeCity.isDestroyed() {
+			// The city was destroyed before the comparison started
+			continue
+		}
+
+		afterCity := after.getCity(name)
+		if afterCity == nil || afterCity.isDestroyed() {
+			diff.DestroyedCities = append(diff.DestroyedCities, name)
+
+			continue
+		}
+
+		for _, direction := range directions {
+			neighbor := beforeCity.neighbors[direction]
+			if neighbor == nil || neighbor.isDestroyed() {
+				continue
+			}
+
+			afterNeighbor := afterCity.neighbors[direction]
+			if afterNeighbor != nil && afterNeighbor.name == neighbor.name && !afterNeighbor.isDestroyed() {
+				continue
+			}
+
+			diff.SeveredRoads = append(diff.SeveredRoads, SeveredRoad{
+				City:      name,
+				Direction: direction.getName(),
+				Neighbor:  neighbor.name,
+			})
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestDiffMaps makes sure the destroyed cities and the
+// severed roads of the surviving cities are reported
+func TestDiffMaps(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		change   func(before, after *EarthMap)
+		expected MapDiff
+	}{
+		{
+			"unchanged map",
+			func(_, _ *EarthMap) {},
+			MapDiff{
+				DestroyedCities: []string{},
+				SeveredRoads:    []SeveredRoad{},
+			},
+		},
+		{
+			"pruned city",
+			func(_, after *EarthMap) {
+				_, _ = after.RemoveCity("C", false)
+			},
+			MapDiff{
+				DestroyedCities: []string{"C"},
+				SeveredRoads: []SeveredRoad{
+					{City: "B", Direction: "east", Neighbor: "C"},
+					{City: "D", Direction: "west", Neighbor: "C"},
+				},
+			},
+		},
+		{
+			"destroyed city",
+			func(_, after *EarthMap) {
+				after.getCity("C").destroyed = true
+			},
+			MapDiff{
+				DestroyedCities: []string{"C"},
+				SeveredRoads: []SeveredRoad{
+					{City: "B", Direction: "east", Neighbor: "C"},
+					{City: "D", Direction: "west", Neighbor: "C"},
+				},
+			},
+		},
+		{
+			"removed road",
+			func(_, after *EarthMap) {
+				after.getCity("A").removeNeighbor(east)
+			},
+			MapDiff{
+				DestroyedCities: []string{},
+				SeveredRoads: []SeveredRoad{
+					{City: "A", Direction: "east", Neighbor: "B"},
+				},
+			},
+		},
+		{
+			"city destroyed before",
+			func(before, after *EarthMap) {
+				before.getCity("E").destroyed = true
+
+				_, _ = after.RemoveCity("E", false)
+			},
+			MapDiff{
+				DestroyedCities: []string{},
+				SeveredRoads:    []SeveredRoad{},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			before := newLineMap()
+			after := before.Clone()
+
+			testCase.change(before, after)
+
+			assert.Equal(t, testCase.expected, DiffMaps(before, after))
+		})
+	}
+}
+
+// TestDiffMaps_Simulation makes sure the diff between the map and its clone
+// invaded by the aliens matches the outcome of the invasion
+func TestDiffMaps_Simulation(t *testing.T) {
+	t.Parallel()
+
+	original := NewEarthMap(hclog.NewNullLogger(), WithSeed(42))
+
+	assert.NoError(t, original.InitMap(stream.NewSliceReader([]string{
+		"A east=B south=D",
+		"B east=C south=E",
+		"C south=F",
+		"D east=E south=G",
+		"E east=F south=H",
+		"F south=I",
+		"G east=H",
+		"H east=I",
+	})))
+
+	invaded := original.Clone()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	result, err := invaded.SimulateInvasion(ctx, 6)
+	assert.NoError(t, err)
+
+	diff := DiffMaps(original, invaded)
+
+	// The destroyed cities are the ones no longer on the invaded map
+	surviving := make(map[string]struct{})
+	for _, name := range invaded.Cities() {
+		surviving[name] = struct{}{}
+	}
+
+	destroyed := make([]string, 0)
+
+	for _, name := range original.Cities() {
+		if _, ok := surviving[name]; !ok {
+			destroyed = append(destroyed, name)
+		}
+	}
+
+	assert.Equal(t, destroyed, diff.DestroyedCities)
+	assert.Len(t, diff.DestroyedCities, result.CitiesDestroyed)
+
+	// The only severed roads are the ones into the destroyed cities
+	expectedRoads := make([]SeveredRoad, 0)
+
+	for _, name := range original.Cities() {
+		if _, ok := surviving[name]; !ok {
+			continue
+		}
+
+		for _, direction := range directions {
+			neighbor := original.getCity(name).neighbors[direction]
+			if neighbor == nil {
+				continue
+			}
+
+			if _, ok := surviving[neighbor.name]; !ok {
+				expectedRoads = append(expectedRoads, SeveredRoad{
+					City:      name,
+					Direction: direction.getName(),
+					Neighbor:  neighbor.name,
+				})
+			}
+		}
+	}
+
+	assert.Equal(t, expectedRoads, diff.SeveredRoads)
+	assert.True(t, sort.StringsAreSorted(diff.DestroyedCities))
+
+	// The original map is left untouched by the invasion
+	assert.Len(t, original.Cities(), 9)
+	assert.Empty(t, original.DestroyedCities())
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// WriteOutputFormat writes the current map layout to the specified output stream,
+// in the given output format. The text format is written like with WriteOutput,
+// the JSON format uses the map JSON schema, and the graph formats are encoded
+// from the map cities, so the travel costs are kept apart from the neighbor names.
+// The output stream is flushed, but not closed
+func (m *EarthMap) WriteOutputFormat(writer stream.OutputWriter, format stream.Format) error {
+	var (
+		encoded string
+		err     error
+	)
+
+	switch format {
+	case stream.FormatText:
+		return m.WriteOutput(writer)
+	case stream.FormatJSON:
+		encoded, err = m.encodeJSONOutput()
+	default:
+		encoded, err = stream.EncodeMap(m.mapCities(), format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to encode the output, %w", err)
+	}
+
+	if err := writer.Write(encoded); err != nil {
+		return fmt.Errorf("unable to write to output stream, %w", err)
+	}
+
+	return writer.Flush()
+}
+
+// encodeJSONOutput encodes the map in the JSON schema, indented for reading,
+// with the cities in the output order
+func (m *EarthMap) encodeJSONOutput() (string, error) {
+	cities := m.sortedCities()
+
+	jm := jsonMap{
+		Cities: make([]jsonCity, 0, len(cities)),
+	}
+
+	for _, city := range cities {
+		jm.Cities = append(jm.Cities, toJSONCity(city))
+	}
+
+	encoded, err := json.MarshalIndent(jm, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded) + "\n", nil
+}
+
+// mapCities converts the cities to the form the graph formats encode,
+// in the output order
+func (m *EarthMap) mapCities() []stream.MapCity {
+	cities := m.sortedCities()
+
+	mapCities := make([]stream.MapCity, 0, len(cities))
+
+	for _, city := range cities {
+		jc := toJSONCity(city)
+
+		mapCities = append(mapCities, stream.MapCity{
+			Name:      jc.Name,
+			Neighbors: jc.Neighbors,
+			Costs:     jc.Costs,
+		})
+	}
+
+	return mapCities
+}
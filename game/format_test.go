@@ -0,0 +1,146 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// newTravelCostMap creates an earth map with a road that has a non-default travel cost
+func newTravelCostMap(t *testing.T) *EarthMap {
+	t.Helper()
+
+	earthMap := NewEarthMap(hclog.NewNullLogger(), WithTravelCosts())
+
+	cityInputs := []string{
+		"Foo north=Bar:3 east=Bee",
+		"Bar south=Foo:3",
+		"Bee west=Foo",
+	}
+
+	if err := earthMap.InitMap(stream.NewSliceReader(cityInputs)); err != nil {
+		t.Fatalf("unable to initialize the map, %v", err)
+	}
+
+	return earthMap
+}
+
+// TestMap_WriteOutputFormat makes sure the map is encoded into each
+// output format, with the travel costs apart from the neighbor names
+func TestMap_WriteOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name           string
+		format         stream.Format
+		expectedOutput string
+	}{
+		{
+			"text",
+			stream.FormatText,
+			"Bar south=Foo:3\nBee west=Foo\nFoo north=Bar:3 east=Bee\n",
+		},
+		{
+			"json",
+			stream.FormatJSON,
+			`{
+  "cities": [
+    {
+      "name": "Bar",
+      "neighbors": {
+        "south": "Foo"
+      },
+      "costs": {
+        "south": 3
+      }
+    },
+    {
+      "name": "Bee",
+      "neighbors": {
+        "west": "Foo"
+      }
+    },
+    {
+      "name": "Foo",
+      "neighbors": {
+        "east": "Bee",
+        "north": "Bar"
+      },
+      "costs": {
+        "north": 3
+      }
+    }
+  ]
+}
+`,
+		},
+		{
+			"dot",
+			stream.FormatDOT,
+			"graph earth {\n" +
+				"  \"Bar\" -- \"Foo\" [label=\"south\", cost=3];\n" +
+				"  \"Bee\" -- \"Foo\" [label=\"west\"];\n" +
+				"}\n",
+		},
+		{
+			"csv",
+			stream.FormatCSV,
+			"city,north,south,east,west,north_cost,south_cost,east_cost,west_cost\n" +
+				"Bar,,Foo,,,,3,,\n" +
+				"Bee,,,,Foo,,,,\n" +
+				"Foo,Bar,,Bee,,3,,,\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			writer := stream.NewSliceWriter()
+
+			assert.NoError(t, newTravelCostMap(t).WriteOutputFormat(writer, testCase.format))
+			assert.Equal(t, testCase.expectedOutput, strings.Join(writer.Lines(), ""))
+		})
+	}
+}
+
+// TestMap_WriteOutputFormat_RoundTrip makes sure the JSON output
+// is read back into the same map, along with the travel costs
+func TestMap_WriteOutputFormat_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	writer := stream.NewSliceWriter()
+
+	assert.NoError(t, newTravelCostMap(t).WriteOutputFormat(writer, stream.FormatJSON))
+
+	reader, err := stream.NewJSONReader(strings.NewReader(strings.Join(writer.Lines(), "")))
+	if err != nil {
+		t.Fatalf("unable to create the JSON reader, %v", err)
+	}
+
+	earthMap := NewEarthMap(hclog.NewNullLogger(), WithTravelCosts())
+	assert.NoError(t, earthMap.InitMap(reader))
+
+	foo, bar := earthMap.getCity("Foo"), earthMap.getCity("Bar")
+	if !assert.NotNil(t, foo) || !assert.NotNil(t, bar) {
+		return
+	}
+
+	assert.Equal(t, 3, foo.costTo(bar))
+	assert.Nil(t, earthMap.getCity("Bar:3"))
+}
+
+// TestMap_WriteOutputFormat_Unsupported makes sure
+// unknown output formats are rejected
+func TestMap_WriteOutputFormat_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	err := newTravelCostMap(t).WriteOutputFormat(stream.NewSliceWriter(), stream.Format("xml"))
+
+	assert.ErrorIs(t, err, stream.ErrUnsupportedFormat)
+}
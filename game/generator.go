@@ -0,0 +1,116 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// gridCityName returns the deterministic name of the grid city
+// located at the given coordinates
+func gridCityName(x, y int) string {
+	return fmt.Sprintf("C_%d_%d", x, y)
+}
+
+// GenerateGridMap generates a regular lattice earth map of the given dimensions.
+// City (x,y) is connected to the cities at the adjacent coordinates, where
+// north decreases y, south increases y, east increases x and west decreases x.
+// Cities are named using their coordinates, for example C_3_7
+func GenerateGridMap(width, height int) *EarthMap {
+	m := NewEarthMap(hclog.NewNullLogger())
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			city := m.getOrAddCity(gridCityName(x, y))
+
+			// Link the city with the previously added
+			// neighbors to the west and north
+			if x > 0 {
+				m.addRoad(city, west, m.getCity(gridCityName(x-1, y)))
+			}
+
+			if y > 0 {
+				m.addRoad(city, north, m.getCity(gridCityName(x, y-1)))
+			}
+		}
+	}
+
+	return m
+}
+
+// latticeRoad is a potential road between two adjacent lattice cities,
+// where the neighbor lies in the given direction of the city
+type latticeRoad struct {
+	city      *city
+	direction direction
+	neighbor  *city
+}
+
+// GenerateRandomMap generates an earth map with the given number of cities,
+// laid out row by row on a square lattice. Each road between adjacent cities
+// is kept with the probability of the given density, so a density of 1
+// produces a full grid. If the map needs to be connected, a random spanning
+// tree of roads is kept first, regardless of the density.
+// Cities are named using their coordinates, for example C_3_7
+func GenerateRandomMap(numCities int, density float64, seed int64, connected bool) *EarthMap {
+	var (
+		m     = NewEarthMap(hclog.NewNullLogger())
+		rng   = newRand(seed)
+		width = int(math.Ceil(math.Sqrt(float64(numCities))))
+		roads = make([]latticeRoad, 0)
+	)
+
+	// Place the cities on the lattice, and gather the
+	// potential roads to the neighbors to the west and north
+	for i := 0; i < numCities; i++ {
+		x, y := i%width, i/width
+
+		city := m.getOrAddCity(gridCityName(x, y))
+
+		if x > 0 {
+			roads = append(roads, latticeRoad{city, west, m.getCity(gridCityName(x-1, y))})
+		}
+
+		if y > 0 {
+			roads = append(roads, latticeRoad{city, north, m.getCity(gridCityName(x, y-1))})
+		}
+	}
+
+	rng.Shuffle(len(roads), func(i, j int) {
+		roads[i], roads[j] = roads[j], roads[i]
+	})
+
+	// Keep track of the connected city groups, using a union-find structure
+	parents := make(map[*city]*city)
+
+	var find func(c *city) *city
+	find = func(c *city) *city {
+		parent, ok := parents[c]
+		if !ok || parent == c {
+			return c
+		}
+
+		root := find(parent)
+		parents[c] = root
+
+		return root
+	}
+
+	for _, road := range roads {
+		cityRoot, neighborRoot := find(road.city), find(road.neighbor)
+
+		// Roads joining separate groups are always kept for connected maps
+		joinsGroups := connected && cityRoot != neighborRoot
+
+		if !joinsGroups && rng.Float64() >= density {
+			continue
+		}
+
+		parents[cityRoot] = neighborRoot
+
+		m.addRoad(road.city, road.direction, road.neighbor)
+	}
+
+	return m
+}
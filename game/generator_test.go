@@ -0,0 +1,154 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenerator_GridMap makes sure the generated grid map
+// has the correct lattice topology
+func TestGenerator_GridMap(t *testing.T) {
+	t.Parallel()
+
+	var (
+		width  = 5
+		height = 4
+	)
+
+	m := GenerateGridMap(width, height)
+
+	// Make sure all cities are present
+	assert.Len(t, m.cityMap, width*height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			city := m.getCity(gridCityName(x, y))
+			if city == nil {
+				t.Fatalf("city %s not present in city map", gridCityName(x, y))
+			}
+
+			var (
+				onVerticalEdge   = x == 0 || x == width-1
+				onHorizontalEdge = y == 0 || y == height-1
+			)
+
+			switch {
+			case onVerticalEdge && onHorizontalEdge:
+				// Corner cities have exactly 2 neighbors
+				assert.Len(t, city.neighbors, 2)
+			case onVerticalEdge || onHorizontalEdge:
+				// Edge cities have exactly 3 neighbors
+				assert.Len(t, city.neighbors, 3)
+			default:
+				// Interior cities have exactly 4 neighbors
+				assert.Len(t, city.neighbors, 4)
+			}
+
+			// Make sure the links point to the adjacent coordinates
+			for direction, neighbor := range city.neighbors {
+				assert.Equal(t, city, neighbor.neighbors[direction.getOpposite()])
+			}
+
+			if x > 0 {
+				assert.Equal(t, gridCityName(x-1, y), city.neighbors[west].name)
+			}
+
+			if y > 0 {
+				assert.Equal(t, gridCityName(x, y-1), city.neighbors[north].name)
+			}
+		}
+	}
+}
+
+// TestGenerator_GridMap_Empty makes sure invalid
+// grid dimensions produce an empty map
+func TestGenerator_GridMap_Empty(t *testing.T) {
+	t.Parallel()
+
+	assert.Len(t, GenerateGridMap(0, 10).cityMap, 0)
+	assert.Len(t, GenerateGridMap(10, -1).cityMap, 0)
+}
+
+// TestGenerator_RandomMap makes sure the generated random map
+// respects the density and connectivity settings
+func TestGenerator_RandomMap(t *testing.T) {
+	t.Parallel()
+
+	numCities := 50
+
+	t.Run("full density", func(t *testing.T) {
+		t.Parallel()
+
+		m := GenerateRandomMap(numCities, 1, 42, false)
+		report := m.Validate()
+
+		// 6 full rows of 8 cities, and a last row of 2 cities
+		assert.Equal(t, numCities, report.Cities)
+		assert.Equal(t, (6*7+1)+(5*8+2), report.Roads)
+		assert.Equal(t, 1, report.Components)
+		assert.False(t, report.HasErrors())
+	})
+
+	t.Run("connected spanning tree", func(t *testing.T) {
+		t.Parallel()
+
+		m := GenerateRandomMap(numCities, 0, 42, true)
+		report := m.Validate()
+
+		assert.Equal(t, numCities, report.Cities)
+		assert.Equal(t, numCities-1, report.Roads)
+		assert.Equal(t, 1, report.Components)
+		assert.False(t, report.HasErrors())
+	})
+
+	t.Run("no roads", func(t *testing.T) {
+		t.Parallel()
+
+		report := GenerateRandomMap(numCities, 0, 42, false).Validate()
+
+		assert.Equal(t, 0, report.Roads)
+		assert.Equal(t, numCities, report.Components)
+	})
+
+	t.Run("deterministic seed", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(
+			t,
+			MapsEquivalent(
+				GenerateRandomMap(numCities, 0.5, 42, true),
+				GenerateRandomMap(numCities, 0.5, 42, true),
+			),
+		)
+	})
+}
+
+// BenchmarkGenerator_GridMap_SimulateInvasion runs the invasion
+// simulation with 1k aliens on a 100x100 grid map
+func BenchmarkGenerator_GridMap_SimulateInvasion(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+
+		m := GenerateGridMap(100, 100)
+
+		b.StartTimer()
+
+		m.SimulateInvasion(context.Background(), 1000)
+	}
+}
+
+// BenchmarkGenerator_GridMap_SimulateInvasion_ManyAliens runs the invasion
+// simulation with 100k aliens on a 100x100 grid map
+func BenchmarkGenerator_GridMap_SimulateInvasion_ManyAliens(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+
+		m := GenerateGridMap(100, 100)
+
+		b.StartTimer()
+
+		m.SimulateInvasion(context.Background(), 100_000)
+	}
+}
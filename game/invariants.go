@@ -0,0 +1,117 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrDanglingNeighbor     = errors.New("neighbor is not present in the city map")
+	ErrAsymmetricRoad       = errors.New("road is not linked back in the opposite direction")
+	ErrInvaderOverflow      = errors.New("city has too many invaders")
+	ErrSiegeOverflow        = errors.New("city has too many sieges")
+	ErrOrphanedSiege        = errors.New("siege is not held by an invader of the city")
+	ErrDestroyedNotDetached = errors.New("destroyed city has not been detached from the map")
+)
+
+// CheckInvariants verifies the structural consistency of the earth map:
+//   - every neighbor resolves to a city present in the city map
+//   - every road is linked back from the neighbor in the opposite direction
+//   - the invader and siege sets do not exceed the invader threshold
+//   - every siege is held by an invader of the city, since the sieges
+//     are only held in between the moves of the aliens
+//   - destroyed cities have been detached from the map, unless
+//     auto-pruning is disabled
+//
+// Returns the first violated invariant, if any
+func (m *EarthMap) CheckInvariants() error {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Check the cities in a stable order, so the
+	// reported violation is deterministic
+	names := make([]string, 0, len(m.cityMap))
+	for name := range m.cityMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := m.checkCityInvariants(m.cityMap[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCityInvariants verifies the invariants of a single city on the map.
+// The aliens need to be stopped, so no siege is held in between the moves
+func (m *EarthMap) checkCityInvariants(c *city) error {
+	c.RLock()
+	defer c.RUnlock()
+
+	if c.destroyed && !m.config.keepDestroyed {
+		return fmt.Errorf("%w: %s", ErrDestroyedNotDetached, c.name)
+	}
+
+	if c.numInvaders() > maxInvaderCount {
+		return fmt.Errorf("%w: %s has %d", ErrInvaderOverflow, c.name, c.numInvaders())
+	}
+
+	if c.numSieges() > maxInvaderCount {
+		return fmt.Errorf("%w: %s has %d", ErrSiegeOverflow, c.name, c.numSieges())
+	}
+
+	for _, id := range sortedIDs(c.sieges) {
+		if _, isInvader := c.invaders[id]; !isInvader {
+			return fmt.Errorf("%w: alien %d in %s", ErrOrphanedSiege, id, c.name)
+		}
+	}
+
+	for _, direction := range directions {
+		neighbor, ok := c.neighbors[direction]
+		if !ok {
+			continue
+		}
+
+		// Make sure the neighbor is an active city on the map
+		if m.cityMap[neighbor.name] != neighbor {
+			return fmt.Errorf(
+				"%w: %s neighbor %s of %s",
+				ErrDanglingNeighbor,
+				direction.getName(),
+				neighbor.name,
+				c.name,
+			)
+		}
+
+		// Make sure the neighbor links back to the city
+		if neighbor.neighbors[direction.getOpposite()] != c {
+			return fmt.Errorf(
+				"%w: %s %s of %s",
+				ErrAsymmetricRoad,
+				neighbor.name,
+				direction.getName(),
+				c.name,
+			)
+		}
+	}
+
+	return nil
+}
+
+// sortedIDs returns the sorted alien IDs of the set
+func sortedIDs(set map[int]struct{}) []int {
+	ids := make([]int, 0, len(set))
+
+	for id := range set {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestInvariants_CheckInvariants makes sure each map invariant
+// violation is detected and reported
+func TestInvariants_CheckInvariants(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name    string
+		corrupt func(m *EarthMap)
+
+		expectedErr error
+	}{
+		{
+			"valid map",
+			func(m *EarthMap) {},
+			nil,
+		},
+		{
+			"dangling neighbor",
+			func(m *EarthMap) {
+				// Drop the city from the lookup reference only,
+				// leaving the neighbor pointers stale
+				delete(m.cityMap, "Bar")
+			},
+			ErrDanglingNeighbor,
+		},
+		{
+			"asymmetric road",
+			func(m *EarthMap) {
+				m.getCity("Bar").removeNeighbor(south)
+			},
+			ErrAsymmetricRoad,
+		},
+		{
+			"invader overflow",
+			func(m *EarthMap) {
+				city := m.getCity("Foo")
+
+				for id := 0; id <= maxInvaderCount; id++ {
+					city.invaders[id] = struct{}{}
+				}
+			},
+			ErrInvaderOverflow,
+		},
+		{
+			"siege overflow",
+			func(m *EarthMap) {
+				city := m.getCity("Foo")
+
+				for id := 0; id <= maxInvaderCount; id++ {
+					city.sieges[id] = struct{}{}
+				}
+			},
+			ErrSiegeOverflow,
+		},
+		{
+			"orphaned siege",
+			func(m *EarthMap) {
+				m.getCity("Foo").laySiege(0)
+			},
+			ErrOrphanedSiege,
+		},
+		{
+			"destroyed city not detached",
+			func(m *EarthMap) {
+				m.getCity("Bar").destroyed = true
+			},
+			ErrDestroyedNotDetached,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewEarthMap(hclog.NewNullLogger())
+
+			m.InitMap(stream.NewSliceReader([]string{
+				"Foo north=Bar",
+				"Bar east=Baz",
+			}))
+
+			// Corrupt the map state
+			testCase.corrupt(m)
+
+			assert.ErrorIs(t, m.CheckInvariants(), testCase.expectedErr)
+		})
+	}
+}
+
+// TestInvariants_SimulateInvasion makes sure the map invariants
+// hold after an invasion simulation with debug checks enabled
+func TestInvariants_SimulateInvasion(t *testing.T) {
+	t.Parallel()
+
+	m := GenerateGridMap(5, 5)
+	WithDebugChecks()(m)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	m.SimulateInvasion(ctx, 20)
+
+	assert.NoError(t, m.CheckInvariants())
+}
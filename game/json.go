@@ -0,0 +1,193 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+var (
+	ErrDuplicateCity    = errors.New("duplicate city definition")
+	ErrInvalidCityName  = errors.New("invalid city name")
+	ErrInvalidDirection = errors.New("invalid direction")
+	ErrUnknownNeighbor  = errors.New("neighbor is not a defined city")
+	ErrInvalidCost      = errors.New("invalid travel cost")
+)
+
+// jsonMap is the stable JSON schema of the earth map
+type jsonMap struct {
+	Cities []jsonCity `json:"cities"`
+}
+
+// jsonCity is the stable JSON schema of a single city.
+// The neighbors, and the travel costs of the roads that
+// differ from the default cost, are keyed by the direction name
+type jsonCity struct {
+	Name      string            `json:"name"`
+	Neighbors map[string]string `json:"neighbors,omitempty"`
+	Costs     map[string]int    `json:"costs,omitempty"`
+	Destroyed bool              `json:"destroyed,omitempty"`
+}
+
+// toJSONCity converts the city to its JSON schema
+func toJSONCity(c *city) jsonCity {
+	jc := jsonCity{
+		Name:      c.name,
+		Destroyed: c.isDestroyed(),
+	}
+
+	if len(c.neighbors) > 0 {
+		jc.Neighbors = make(map[string]string, len(c.neighbors))
+
+		for direction, neighbor := range c.neighbors {
+			jc.Neighbors[direction.getName()] = neighbor.name
+		}
+	}
+
+	if len(c.costs) > 0 {
+		jc.Costs = make(map[string]int, len(c.costs))
+
+		for direction, cost := range c.costs {
+			jc.Costs[direction.getName()] = cost
+		}
+	}
+
+	return jc
+}
+
+// toJSONMap converts the earth map to its JSON schema,
+// with the cities sorted by name
+func (m *EarthMap) toJSONMap() jsonMap {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	names := make([]string, 0, len(m.cityMap))
+	for name := range m.cityMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	cities := make([]jsonCity, 0, len(names))
+
+	for _, name := range names {
+		cities = append(cities, toJSONCity(m.cityMap[name]))
+	}
+
+	return jsonMap{
+		Cities: cities,
+	}
+}
+
+// fromJSONMap rebuilds the earth map cities from the JSON schema,
+// reconstructing the neighbor links and validating referential integrity
+func (m *EarthMap) fromJSONMap(jm jsonMap) error {
+	cityMap := make(map[string]*city, len(jm.Cities))
+
+	// Create all the defined cities
+	for _, jc := range jm.Cities {
+		if jc.Name == "" {
+			return ErrInvalidCityName
+		}
+
+		if _, exists := cityMap[jc.Name]; exists {
+			return fmt.Errorf("%w: %s", ErrDuplicateCity, jc.Name)
+		}
+
+		c := newCity(jc.Name, m.cityOptions(m.log.Named(jc.Name))...)
+		c.destroyed = jc.Destroyed
+
+		cityMap[jc.Name] = c
+	}
+
+	// Link the cities with their neighbors
+	for _, jc := range jm.Cities {
+		c := cityMap[jc.Name]
+
+		for directionName, neighborName := range jc.Neighbors {
+			direction, valid := directionFromName(directionName)
+			if !valid {
+				return fmt.Errorf("%w: %s for city %s", ErrInvalidDirection, directionName, jc.Name)
+			}
+
+			neighbor, exists := cityMap[neighborName]
+			if !exists {
+				return fmt.Errorf("%w: %s for city %s", ErrUnknownNeighbor, neighborName, jc.Name)
+			}
+
+			c.addNeighbor(direction, neighbor)
+		}
+	}
+
+	// Set the travel costs of the linked roads
+	for _, jc := range jm.Cities {
+		c := cityMap[jc.Name]
+
+		for directionName, cost := range jc.Costs {
+			direction, valid := directionFromName(directionName)
+			if !valid {
+				return fmt.Errorf("%w: %s for city %s", ErrInvalidDirection, directionName, jc.Name)
+			}
+
+			if _, linked := c.neighbors[direction]; !linked || cost <= 0 {
+				return fmt.Errorf("%w: %d %s of %s", ErrInvalidCost, cost, directionName, jc.Name)
+			}
+
+			c.setCost(direction, cost)
+		}
+	}
+
+	// Make sure every road is linked back from the neighbor
+	for _, c := range cityMap {
+		for direction, neighbor := range c.neighbors {
+			if neighbor.neighbors[direction.getOpposite()] != c {
+				return fmt.Errorf(
+					"%w: %s %s of %s",
+					ErrAsymmetricRoad,
+					neighbor.name,
+					direction.getName(),
+					c.name,
+				)
+			}
+		}
+	}
+
+	m.mux.Lock()
+	m.cityMap = cityMap
+	m.mux.Unlock()
+
+	return nil
+}
+
+// MarshalJSON encodes the earth map using a stable schema,
+// with cities sorted by name. The logger is not serialized
+func (m *EarthMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.toJSONMap())
+}
+
+// UnmarshalJSON decodes the earth map from its JSON schema,
+// replacing any cities already present on the map
+func (m *EarthMap) UnmarshalJSON(data []byte) error {
+	var jm jsonMap
+
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return err
+	}
+
+	// The map could be unmarshalled into a zero value instance
+	if m.log == nil {
+		m.log = hclog.NewNullLogger()
+	}
+
+	// A zero value instance also lacks the configuration defaults,
+	// and the random number generator used by the simulation
+	if m.rng == nil {
+		m.config = defaultConfig()
+		m.rng = newRand(m.config.seed)
+	}
+
+	return m.fromJSONMap(jm)
+}
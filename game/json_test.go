@@ -0,0 +1,164 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestJSON_RoundTrip makes sure the earth map is unchanged
+// after being marshalled and unmarshalled
+func TestJSON_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+
+	m.InitMap(stream.NewSliceReader([]string{
+		"Foo north=Bar west=Baz south=Qu-ux",
+		"Bar south=Foo west=Bee",
+		"Lonely",
+	}))
+
+	m.getCity("Bee").destroyed = true
+
+	// Embed the map inside a larger payload
+	type payload struct {
+		Name string    `json:"name"`
+		Map  *EarthMap `json:"map"`
+	}
+
+	data, err := json.Marshal(payload{
+		Name: "earth",
+		Map:  m,
+	})
+	if err != nil {
+		t.Fatalf("unable to marshal map, %v", err)
+	}
+
+	var decoded payload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unable to unmarshal map, %v", err)
+	}
+
+	// Make sure the maps are canonically equal
+	assert.Equal(t, "earth", decoded.Name)
+	assert.Equal(t, m.toJSONMap(), decoded.Map.toJSONMap())
+
+	// Make sure the neighbor pointers are reconstructed
+	foo := decoded.Map.getCity("Foo")
+	bar := decoded.Map.getCity("Bar")
+
+	assert.Equal(t, bar, foo.neighbors[north])
+	assert.Equal(t, foo, bar.neighbors[south])
+	assert.True(t, decoded.Map.getCity("Bee").destroyed)
+
+	// Make sure the encoding is stable
+	encoded, err := json.Marshal(decoded.Map)
+	if err != nil {
+		t.Fatalf("unable to marshal map, %v", err)
+	}
+
+	original, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unable to marshal map, %v", err)
+	}
+
+	assert.JSONEq(t, string(original), string(encoded))
+	assert.Equal(t, original, encoded)
+}
+
+// TestJSON_Unmarshal_Invalid makes sure invalid JSON maps
+// are rejected
+func TestJSON_Unmarshal_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		input string
+
+		expectedErr error
+	}{
+		{
+			"empty city name",
+			`{"cities":[{"name":""}]}`,
+			ErrInvalidCityName,
+		},
+		{
+			"duplicate city",
+			`{"cities":[{"name":"Foo"},{"name":"Foo"}]}`,
+			ErrDuplicateCity,
+		},
+		{
+			"invalid direction",
+			`{"cities":[{"name":"Foo","neighbors":{"up":"Bar"}},{"name":"Bar"}]}`,
+			ErrInvalidDirection,
+		},
+		{
+			"unknown neighbor",
+			`{"cities":[{"name":"Foo","neighbors":{"north":"Bar"}}]}`,
+			ErrUnknownNeighbor,
+		},
+		{
+			"asymmetric road",
+			`{"cities":[{"name":"Foo","neighbors":{"north":"Bar"}},{"name":"Bar"}]}`,
+			ErrAsymmetricRoad,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m EarthMap
+
+			assert.ErrorIs(
+				t,
+				json.Unmarshal([]byte(testCase.input), &m),
+				testCase.expectedErr,
+			)
+		})
+	}
+}
+
+// TestJSON_Unmarshal_Simulate makes sure a map unmarshalled
+// into a zero value instance can be simulated on
+func TestJSON_Unmarshal_Simulate(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+
+	m.InitMap(stream.NewSliceReader([]string{
+		"Foo",
+	}))
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unable to marshal map, %v", err)
+	}
+
+	var decoded EarthMap
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unable to unmarshal map, %v", err)
+	}
+
+	// Make sure the configuration defaults are in place
+	assert.Equal(t, DefaultMaxMoves, decoded.config.maxMoves)
+	assert.Equal(t, DefaultMaxCities, decoded.config.maxCities)
+	assert.Equal(t, DefaultMaxAliens, decoded.config.maxAliens)
+	assert.Equal(t, DefaultCollisionStrategy, decoded.config.collision)
+
+	// Both aliens land in the only city, and destroy it
+	result, err := decoded.SimulateInvasion(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unable to simulate the invasion, %v", err)
+	}
+
+	assert.Equal(t, 2, result.Aliens)
+	assert.Equal(t, 1, result.CitiesDestroyed)
+}
@@ -2,12 +2,17 @@ package game
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/zivkovicmilos/alien-invasion/stream"
@@ -18,15 +23,26 @@ import (
 var (
 	cityNameRegex = regexp.MustCompile(`^[^ ]+`)
 
-	northRegex = regexp.MustCompile(`north=([^ ]+)`)
-	southRegex = regexp.MustCompile(`south=([^ ]+)`)
-	eastRegex  = regexp.MustCompile(`east=([^ ]+)`)
-	westRegex  = regexp.MustCompile(`west=([^ ]+)`)
+	// The directions need to start a new word, so neighbor names
+	// containing a direction (ex. Foo north=Xsouth=Bar) are not split apart
+	northRegex = regexp.MustCompile(` north=([^ ]+)`)
+	southRegex = regexp.MustCompile(` south=([^ ]+)`)
+	eastRegex  = regexp.MustCompile(` east=([^ ]+)`)
+	westRegex  = regexp.MustCompile(` west=([^ ]+)`)
 )
 
-// Defines the max move count for each alien on the map
+var (
+	ErrCityNotFound    = errors.New("city not found on the map")
+	ErrInvalidCityLine = errors.New("invalid city input line")
+	ErrMapTooLarge     = errors.New("map exceeds the max city count")
+	ErrTooManyAliens   = errors.New("alien count exceeds the max alien count")
+	ErrInvalidAliens   = errors.New("alien count must be a positive number")
+)
+
+// Defines how often the simulation checks if further destruction
+// is possible, when early termination is enabled
 const (
-	maxMoveCount = 10000
+	stalemateCheckInterval = 10 * time.Millisecond
 )
 
 // getDirectionRegex returns the specific direction regex for the input line
@@ -45,77 +61,230 @@ func getDirectionRegex(direction direction) *regexp.Regexp {
 
 // EarthMap keeps track of all active Earth cities
 type EarthMap struct {
+	// The progress counters are kept first, so they are
+	// 64-bit aligned for the atomic operations on 32-bit platforms
+	progress progressCounters
+
 	log hclog.Logger
 
-	cityMap map[string]*city
+	mux         sync.RWMutex     // guards the city map against concurrent readers
+	cityMap     map[string]*city // the active cities on the map
+	prunedCount int              // the number of destroyed cities pruned out of the map
+
+	config config     // the map configuration, set using options
+	rng    *rand.Rand // the random number generator, seeded with the configured seed
 }
 
 // NewEarthMap creates a new instance of the earth map
-func NewEarthMap(log hclog.Logger) *EarthMap {
-	return &EarthMap{
+func NewEarthMap(log hclog.Logger, opts ...Option) *EarthMap {
+	m := &EarthMap{
 		log:     log.Named("earth-map"),
 		cityMap: make(map[string]*city),
+		config:  defaultConfig(),
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.rng = newRand(m.config.seed)
+
+	return m
 }
 
-// InitMap initializes the city map using the specified reader
-func (m *EarthMap) InitMap(reader stream.InputReader) {
-	directions := []direction{north, south, east, west}
+// InitMap initializes the city map using the specified reader, skipping the comment lines.
+// Returns an error if the map exceeds the configured max city count,
+// if strict parsing is enabled and an input line is invalid,
+// or if the reader reports a read error (ex. a line that is too long),
+// so a map cut short is never simulated
+func (m *EarthMap) InitMap(reader stream.InputReader) error {
+	// The line numbers in the diagnostics are taken from the reader, if it keeps track of them,
+	// and fall back to the count of the lines read otherwise
+	positioned, isPositioned := reader.(stream.PositionedReader)
 
 	// Read each city from the input stream, until it is depleted
-	for reader.HasMoreCities() {
-		cityLine := reader.ReadCity()
+	for readCount := 1; reader.HasMoreCities(); readCount++ {
+		cityLine, err := reader.ReadCity()
 
-		// Grab the city name
-		cityNameMatch := cityNameRegex.FindStringSubmatch(cityLine)
-		if len(cityNameMatch) == 0 {
+		lineNum := readCount
+		if isPositioned {
+			lineNum = positioned.LineNumber()
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to read line %d, %w", lineNum, err)
+		}
+
+		// The trailing whitespace, like the carriage return of a Windows line ending
+		// the reader didn't drop, would otherwise end up in the last neighbor name
+		cityLine = strings.TrimRightFunc(cityLine, unicode.IsSpace)
+
+		// The comment lines hold the map metadata, not cities
+		if stream.IsComment(cityLine) {
+			continue
+		}
+
+		if m.config.strictParsing {
+			if err := m.addStrictCityLine(cityLine); err != nil {
+				return fmt.Errorf("unable to parse line %d, %w", lineNum, err)
+			}
+		} else if err := m.addCityLine(cityLine); err != nil {
 			// The assumption is that invalid city lines are skipped
 			m.log.Error(
-				fmt.Sprintf("Invalid city input line: %s", cityLine),
+				fmt.Sprintf("Invalid city input line %d: %s", lineNum, cityLine),
 			)
+		}
 
-			continue
+		// Make sure the map doesn't grow unbounded
+		if m.config.maxCities > 0 && len(m.cityMap) > m.config.maxCities {
+			return fmt.Errorf("%w: more than %d cities", ErrMapTooLarge, m.config.maxCities)
 		}
+	}
 
-		// Create a new instance of a city
-		cityName := cityNameMatch[0]
-		city := newCity(cityName, withLogger(m.log.Named(cityName)))
+	// Make sure the reading wasn't cut short, ex. by a line that is too long
+	if err := reader.Err(); err != nil {
+		return fmt.Errorf("unable to read the map, %w", err)
+	}
 
-		// Add the current city to the earth map
-		m.addCity(city)
+	m.log.Info(
+		fmt.Sprintf("Map initialized with %d cities", len(m.cityMap)),
+	)
 
-		// Check if there are neighboring cities from the input line
-		for _, direction := range directions {
-			match := getDirectionRegex(direction).FindStringSubmatch(cityLine)
+	// The aliens placed in isolated cities can't move, so placing them there is wasteful
+	if isolated := m.IsolatedCities(); len(isolated) > 0 {
+		m.log.Warn(
+			fmt.Sprintf(
+				"The map has %d isolated cities, which can't be destroyed: %s",
+				len(isolated),
+				strings.Join(isolated, ", "),
+			),
+		)
+	}
 
-			if len(match) == 0 {
-				// No neighbors found for this direction
-				continue
-			}
+	return nil
+}
 
-			// Grab the neighbor from the city map if it's present, otherwise create it
-			neighbor := m.getOrAddCity(match[1])
+// addCityLine parses a single city input line, and adds the city
+// along with its neighbors to the earth map
+func (m *EarthMap) addCityLine(cityLine string) error {
+	// Grab the city name
+	cityNameMatch := cityNameRegex.FindStringSubmatch(cityLine)
+	if len(cityNameMatch) == 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidCityLine, cityLine)
+	}
+
+	// roadDefinition is a single road declared by the input line
+	type roadDefinition struct {
+		direction    direction
+		neighborName string
+		cost         int
+	}
+
+	var (
+		// Keep track of where each direction was declared,
+		// in case the declaration order needs to be preserved
+		declaredAt = make(map[direction]int)
 
-			// Add the current city as a new neighbor
-			neighbor.addNeighbor(direction.getOpposite(), city)
+		// The roads are parsed before any city is added, so
+		// an invalid line doesn't leave a part of it on the map
+		roads = make([]roadDefinition, 0, len(directions))
+	)
+
+	// Check if there are neighboring cities from the input line
+	for _, direction := range directions {
+		matches := getDirectionRegex(direction).FindAllStringSubmatchIndex(cityLine, -1)
+
+		if len(matches) == 0 {
+			// No neighbors found for this direction
+			continue
+		}
 
-			// Add the new neighbor to the current city
-			city.addNeighbor(direction, neighbor)
+		// The first declaration of the direction is used
+		match := matches[0]
 
-			m.log.Debug(
+		// A repeated direction is almost always a mistake in the input map
+		if len(matches) > 1 {
+			m.log.Warn(
 				fmt.Sprintf(
-					"Added %s as a %s neighbor of %s",
-					neighbor.name,
+					"City %s declares the %s direction %d times, using the first neighbor %s",
+					cityNameMatch[0],
 					direction.getName(),
-					city.name,
+					len(matches),
+					cityLine[match[2]:match[3]],
 				),
 			)
 		}
+
+		declaredAt[direction] = match[0]
+
+		road := roadDefinition{
+			direction:    direction,
+			neighborName: cityLine[match[2]:match[3]],
+			cost:         defaultTravelCost,
+		}
+
+		// Split off the road travel cost, if any
+		if m.config.travelCosts {
+			var err error
+
+			road.neighborName, road.cost, err = splitTravelCost(road.neighborName)
+			if err != nil {
+				return err
+			}
+		}
+
+		roads = append(roads, road)
 	}
 
-	m.log.Info(
-		fmt.Sprintf("Map initialized with %d cities", len(m.cityMap)),
-	)
+	// Grab the city if it was already referenced as a neighbor,
+	// otherwise create it and add it to the earth map.
+	// Recreating an already referenced city would leave stale neighbor links
+	city := m.getOrAddCity(cityNameMatch[0])
+
+	for _, road := range roads {
+		// Grab the neighbor from the city map if it's present, otherwise create it
+		neighbor := m.getOrAddCity(road.neighborName)
+
+		// Link the current city and the neighbor
+		m.addRoad(city, road.direction, neighbor)
+
+		city.setCost(road.direction, road.cost)
+		neighbor.setCost(road.direction.getOpposite(), road.cost)
+	}
+
+	if m.config.preserveOrder {
+		declared := make([]direction, 0, len(declaredAt))
+		for direction := range declaredAt {
+			declared = append(declared, direction)
+		}
+
+		sort.Slice(declared, func(i, j int) bool {
+			return declaredAt[declared[i]] < declaredAt[declared[j]]
+		})
+
+		for _, direction := range declared {
+			city.declareDirection(direction)
+		}
+	}
+
+	return nil
+}
+
+// splitTravelCost splits the neighbor definition (ex. Bar:3) into the neighbor name
+// and the road travel cost. A definition without a cost uses the default travel cost,
+// while a cost that is not a positive whole number, or a missing neighbor name, is an error
+func splitTravelCost(definition string) (string, int, error) {
+	index := strings.LastIndex(definition, ":")
+	if index < 0 {
+		return definition, defaultTravelCost, nil
+	}
+
+	cost, err := strconv.Atoi(definition[index+1:])
+	if err != nil || cost <= 0 || index == 0 {
+		return "", 0, fmt.Errorf("%w: invalid travel cost in %q", ErrInvalidCityLine, definition)
+	}
+
+	return definition[:index], cost, nil
 }
 
 // getCity fetches a city from the city map.
@@ -153,6 +322,92 @@ func (m *EarthMap) removeCity(name string) {
 	}
 }
 
+// RemoveCity removes the city with the given name from the earth map,
+// along with all the roads leading to it.
+// If cascade is set, any city left without neighbors as a result of the removal
+// is removed as well, repeating until the map is stable. Cities that had
+// no neighbors before the removal are never cascaded.
+// Returns the names of all removed cities, starting with the given city
+func (m *EarthMap) RemoveCity(name string, cascade bool) ([]string, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	removedCity := m.getCity(name)
+	if removedCity == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCityNotFound, name)
+	}
+
+	var (
+		removed = []string{name}
+		pending = []*city{removedCity}
+		queued  = map[string]struct{}{name: {}}
+	)
+
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		// Grab the neighbors in a stable order before the removal
+		neighbors := make([]*city, 0, len(current.neighbors))
+
+		for _, direction := range directions {
+			if neighbor, ok := current.neighbors[direction]; ok {
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+
+		m.removeCity(current.name)
+
+		if !cascade {
+			continue
+		}
+
+		// Queue up the neighbors left without any roads
+		for _, neighbor := range neighbors {
+			if _, alreadyQueued := queued[neighbor.name]; alreadyQueued {
+				continue
+			}
+
+			if len(neighbor.neighbors) == 0 {
+				queued[neighbor.name] = struct{}{}
+				removed = append(removed, neighbor.name)
+				pending = append(pending, neighbor)
+			}
+		}
+	}
+
+	return removed, nil
+}
+
+// addRoad links the city with the neighbor in the specified direction,
+// and links the neighbor back to the city in the opposite direction
+func (m *EarthMap) addRoad(city *city, direction direction, neighbor *city) {
+	// Redefining a road replaces the previous one,
+	// so the stale links back to both cities need to be removed
+	if previous, ok := city.neighbors[direction]; ok && previous != neighbor {
+		previous.removeNeighbor(direction.getOpposite())
+	}
+
+	if previous, ok := neighbor.neighbors[direction.getOpposite()]; ok && previous != city {
+		previous.removeNeighbor(direction)
+	}
+
+	// Add the current city as a new neighbor
+	neighbor.addNeighbor(direction.getOpposite(), city)
+
+	// Add the new neighbor to the current city
+	city.addNeighbor(direction, neighbor)
+
+	m.log.Debug(
+		fmt.Sprintf(
+			"Added %s as a %s neighbor of %s",
+			neighbor.name,
+			direction.getName(),
+			city.name,
+		),
+	)
+}
+
 // getOrAddCity attempts to fetch a city from the city map.
 // If the city is not present, it is created, appended to the city map
 // and returned
@@ -161,7 +416,7 @@ func (m *EarthMap) getOrAddCity(name string) *city {
 
 	if city == nil {
 		// City not created yet, add it
-		city = newCity(name, withLogger(m.log.Named(name)))
+		city = newCity(name, m.cityOptions(m.log.Named(name))...)
 
 		m.addCity(city)
 	}
@@ -170,23 +425,52 @@ func (m *EarthMap) getOrAddCity(name string) *city {
 }
 
 // WriteOutput writes the current map layout to the specified
-// output stream. It assumes that the output order is not important
+// output stream. It assumes that the output order is not important.
+// The output stream is flushed, but not closed, so closing it (which
+// completes the output of writers like the atomic file writer) is up to the caller
 func (m *EarthMap) WriteOutput(writer stream.OutputWriter) error {
+	return m.WriteOutputCtx(context.Background(), writer)
+}
+
+// WriteOutputCtx writes the current map layout to the specified output stream,
+// like WriteOutput. The context is checked between cities, and if it's cancelled,
+// the cities written so far are flushed and the context error is returned
+func (m *EarthMap) WriteOutputCtx(ctx context.Context, writer stream.OutputWriter) error {
 	// Check if there are any cities left to output
 	if len(m.cityMap) == 0 {
 		m.log.Info("All cities were destroyed by mad aliens")
 	}
 
+	// Keep track of the successfully written cities,
+	// in case the output stream fails midway
+	written := 0
+
 	// Each city has an output format:
 	// CityName direction=CityName...
-	for _, city := range m.cityMap {
+	for _, city := range m.sortedCities() {
+		// Stop writing if the output is no longer needed
+		if err := ctx.Err(); err != nil {
+			m.flushPartialOutput(writer)
+
+			return fmt.Errorf(
+				"output cancelled after %d cities were written, %w",
+				written,
+				err,
+			)
+		}
+
 		var sb strings.Builder
 
 		// Write the city name
 		sb.WriteString(city.name)
 
 		// For each direction, write the neighbor with the direction
-		for direction, neighbor := range city.neighbors {
+		for _, direction := range city.outputDirections() {
+			neighbor, ok := city.neighbors[direction]
+			if !ok {
+				continue
+			}
+
 			sb.WriteString(
 				fmt.Sprintf(
 					" %s=%s",
@@ -194,48 +478,134 @@ func (m *EarthMap) WriteOutput(writer stream.OutputWriter) error {
 					neighbor.name,
 				),
 			)
+
+			// Write the travel cost, if it differs from the default
+			if cost := city.getCost(direction); cost != defaultTravelCost {
+				sb.WriteString(fmt.Sprintf(":%d", cost))
+			}
 		}
 
 		if err := writer.Write(fmt.Sprintf("%s\n", sb.String())); err != nil {
-			return fmt.Errorf("unable to write to output stream, %w", err)
+			m.flushPartialOutput(writer)
+
+			return fmt.Errorf(
+				"unable to write to output stream after %d cities were written, %w",
+				written,
+				err,
+			)
 		}
+
+		written++
 	}
 
 	return writer.Flush()
 }
 
+// flushPartialOutput attempts to flush the cities written so far,
+// so the output stream is not left in an indeterminate state
+func (m *EarthMap) flushPartialOutput(writer stream.OutputWriter) {
+	if err := writer.Flush(); err != nil {
+		m.log.Error(
+			fmt.Sprintf("Unable to flush the partial output, %v", err),
+		)
+	}
+}
+
+// sortedCities returns the cities on the map,
+// ordered using the configured output sort mode
+func (m *EarthMap) sortedCities() []*city {
+	cities := make([]*city, 0, len(m.cityMap))
+	for _, city := range m.cityMap {
+		cities = append(cities, city)
+	}
+
+	sort.Slice(cities, func(i, j int) bool {
+		if m.config.outputSort == SortByDegreeDesc {
+			degreeI, degreeJ := len(cities[i].neighbors), len(cities[j].neighbors)
+			if degreeI != degreeJ {
+				return degreeI > degreeJ
+			}
+		}
+
+		// Ties are always broken by the city name
+		return cities[i].name < cities[j].name
+	})
+
+	return cities
+}
+
 // SimulateInvasion starts the invasion simulation using the provided number of aliens.
 // The invasion consists of a few steps:
 // 1. Randomly assign starting positions for aliens
 // 2. Set the aliens loose on the Earth map
 // 3. Wait until the program terminates (either):
 //    - all aliens are dead
-//    - all aliens moved at least 10k times (solves the "trapped" scenarios)
+//    - all aliens moved the max number of times (solves the "trapped" scenarios)
 //    - the user terminated the program with an exit signal (CTRL-C)
 // 4. Prune out destroyed cities from the map
-func (m *EarthMap) SimulateInvasion(ctx context.Context, numAliens int) {
+//
+// Returns the outcome of the simulation, or an error if the number
+// of aliens is not positive, or exceeds the configured max alien count
+func (m *EarthMap) SimulateInvasion(ctx context.Context, numAliens int) (SimulationResult, error) {
+	result := SimulationResult{}
+
+	err := m.simulateInvasion(ctx, numAliens, &result)
+
+	return result, err
+}
+
+// simulateInvasion runs the invasion simulation, and fills in its outcome
+func (m *EarthMap) simulateInvasion(ctx context.Context, numAliens int, result *SimulationResult) error {
+	// Make sure the number of aliens is within bounds,
+	// before any allocation takes place
+	if numAliens <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidAliens, numAliens)
+	}
+
+	if m.config.maxAliens > 0 && numAliens > m.config.maxAliens {
+		return fmt.Errorf("%w: %d aliens, at most %d allowed", ErrTooManyAliens, numAliens, m.config.maxAliens)
+	}
+
 	// Check if there are cities on the map for the invasion
 	if len(m.cityMap) == 0 {
 		// There are no cities on the earth map for aliens
 		// to destroy, so the simulation terminates
 		m.log.Error("There are no cities for the mad aliens to invade")
 
-		return
+		return nil
 	}
 
 	// Randomly assign starting positions for aliens
 	randomCities := m.getRandomCities(numAliens)
 
+	m.progress.reset(numAliens)
+
 	// Set the aliens loose on the Earth map
 	var (
-		aliensLeft  = numAliens
-		alienDoneCh = make(chan struct{})
+		aliensLeft = numAliens
+
+		// The channel is buffered for every alien, so finished
+		// aliens don't serialize through the main loop receive
+		alienDoneCh = make(chan struct{}, numAliens)
+
+		// The total travel cost of all the aliens
+		totalCost int64
+
+		// The number of aliens that ran out of energy
+		starvedAliens int64
 
 		wg sync.WaitGroup
 	)
 
 	workerContext, cancelFn := context.WithCancel(ctx)
 
+	// The aliens stop the simulation once they use up the total move budget, if set
+	var budget *moveBudget
+
+	if m.config.maxTotalMoves > 0 {
+		budget = newMoveBudget(m.config.maxTotalMoves, cancelFn)
+	}
+
 	// Cleanup
 	defer func() {
 		// Close off the alien routines, and wait
@@ -245,31 +615,35 @@ func (m *EarthMap) SimulateInvasion(ctx context.Context, numAliens int) {
 
 		close(alienDoneCh)
 
-		// Prune out the destroyed cities
-		m.log.Info(
-			fmt.Sprintf(
-				"A total of %d cities were destroyed",
-				m.pruneDestroyedCities(),
-			),
-		)
+		result.TotalCost = int(totalCost)
+		result.StarvedAliens = int(starvedAliens)
+		result.SurvivingAliens = m.countSurvivingAliens()
+		result.CitiesDestroyed = m.concludeInvasion()
 	}()
 
+	// The aliens wait on the start barrier until every alien is placed,
+	// so the aliens launched first don't get a head start
+	startCh := make(chan struct{})
+
 	// For each random city, attempt to add an invader,
 	// and kick off the invasion process for that alien
 	for id, randomCity := range randomCities {
 		// Attempt to add the alien as an invader
-		if !randomCity.laySiege(id) {
+		if invaded, _ := randomCity.tryInvade(id, m.rng); !invaded {
 			// The alien could not be added, because the city
 			// is not accessible. The assumption is that aliens that cannot
 			// be added to their initially assigned cities are not accounted for.
 			// An alternative approach would be to grab a new random city for each alien
 			// in this situation (reassign them to a new random city)
 			aliensLeft--
+			m.progress.alienFinished()
 
 			continue
 		}
 
-		randomCity.addInvader(id)
+		if m.config.recorder != nil {
+			m.config.recorder.recordPlacement(id, randomCity.name)
+		}
 
 		wg.Add(1)
 
@@ -279,39 +653,204 @@ func (m *EarthMap) SimulateInvasion(ctx context.Context, numAliens int) {
 				wg.Done()
 			}()
 
-			newAlien(id).runAlien(
+			select {
+			case <-ctx.Done():
+				return
+			case <-startCh:
+			}
+
+			opts := m.alienOptions(id)
+			if budget != nil {
+				opts = append(opts, withMoveBudget(budget))
+			}
+
+			a := newAlien(id, opts...)
+			a.runAlien(
 				workerContext,
 				startingCity,
 				alienDoneCh,
 			)
+
+			atomic.AddInt64(&totalCost, int64(a.traveled))
+
+			if a.starved {
+				atomic.AddInt64(&starvedAliens, 1)
+			}
 		}(workerContext, id, randomCity)
 	}
 
+	// Release all the placed aliens at once
+	close(startCh)
+
+	result.Aliens = aliensLeft
+
+	// Every alien could have been dropped during placement, in which
+	// case no alien will ever report back, and the simulation is already over
+	if aliensLeft <= 0 {
+		m.log.Info("No aliens were placed on the map")
+
+		return nil
+	}
+
+	// Periodically check if any further destruction is possible,
+	// if early termination is enabled
+	var stalemateCheckCh <-chan time.Time
+
+	if m.config.earlyTermination {
+		ticker := time.NewTicker(stalemateCheckInterval)
+		defer ticker.Stop()
+
+		stalemateCheckCh = ticker.C
+	}
+
 	// Wait until the program terminates
 	for {
 		select {
-		case <-ctx.Done():
+		case <-workerContext.Done():
+			// The aliens stopped the simulation on the total move budget
+			if ctx.Err() == nil {
+				m.log.Info(fmt.Sprintf("The total move budget of %d moves was used up", m.config.maxTotalMoves))
+
+				result.MoveBudgetExhausted = true
+
+				return nil
+			}
+
 			// User stopped the program
 			m.log.Info("Shutdown signal caught...")
 
-			return
+			result.Interrupted = true
+
+			return nil
+		case <-stalemateCheckCh:
+			if m.isStalemate() {
+				// All remaining cities are cut off from each other,
+				// so the surviving aliens are trapped for good
+				m.log.Info("No further destruction is possible, terminating early")
+
+				return nil
+			}
 		case <-alienDoneCh:
 			aliensLeft--
+			m.progress.alienFinished()
 
 			if aliensLeft == 0 {
 				m.log.Info("The final alien has finished")
 
-				return
+				return nil
 			}
 		}
 	}
 }
 
+// concludeInvasion prunes out the destroyed cities (unless they should be kept),
+// reports the destruction, and verifies the map state if debug checks are enabled.
+// Returns the number of destroyed cities
+func (m *EarthMap) concludeInvasion() int {
+	// Prune out the destroyed cities, unless they should be kept
+	destroyedCount := len(m.DestroyedCities())
+	if !m.config.keepDestroyed {
+		destroyedCount = m.pruneDestroyedCities()
+	}
+
+	m.log.Info(
+		fmt.Sprintf(
+			"A total of %d cities were destroyed",
+			destroyedCount,
+		),
+	)
+
+	// Verify the map state is consistent after the invasion
+	if m.config.debugChecks {
+		if err := m.CheckInvariants(); err != nil {
+			m.log.Error(
+				fmt.Sprintf("Map invariants violated after the invasion, %v", err),
+			)
+		}
+	}
+
+	return destroyedCount
+}
+
+// cityOptions returns the city options based on the map configuration
+func (m *EarthMap) cityOptions(log hclog.Logger) []func(*city) {
+	return []func(*city){
+		withLogger(log),
+		withDestructionListener(m.notifyDestruction),
+		withCollisionStrategy(m.config.collision),
+	}
+}
+
+// notifyDestruction counts the city destruction, and
+// notifies the destruction listener, if the listener is set
+func (m *EarthMap) notifyDestruction(destruction Destruction) {
+	m.progress.cityDestroyed()
+
+	if m.config.destructionListener != nil {
+		m.config.destructionListener(destruction)
+	}
+}
+
+// countSurvivingAliens counts the aliens left in the cities that were not destroyed
+func (m *EarthMap) countSurvivingAliens() int {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	survivors := 0
+
+	for _, city := range m.cityMap {
+		city.RLock()
+
+		if !city.destroyed {
+			survivors += city.numInvaders()
+		}
+
+		city.RUnlock()
+	}
+
+	return survivors
+}
+
+// alienOptions returns the options of the alien with the given ID,
+// based on the map configuration
+func (m *EarthMap) alienOptions(id int) []func(*alien) {
+	rng := m.rng
+	if m.config.perAlienRand {
+		rng = newAlienRand(m.config.seed, id)
+	}
+
+	opts := []func(*alien){
+		withRand(rng),
+		withMaxMoves(m.config.maxMoves),
+	}
+
+	if m.config.alienBehavior != nil {
+		opts = append(opts, withBehavior(m.config.alienBehavior))
+	}
+
+	if m.config.strategy != nil {
+		opts = append(opts, withBehavior(strategyMovement(m.config.strategy(id))))
+	}
+
+	if m.config.alienEnergy != nil {
+		if energy := m.config.alienEnergy(id); energy > 0 {
+			opts = append(opts, withAlienEnergy(energy))
+		}
+	}
+
+	if m.config.recorder != nil {
+		opts = append(opts, withRecorder(m.config.recorder))
+	}
+
+	if m.config.travelCosts {
+		opts = append(opts, withTravelCosts())
+	}
+
+	return opts
+}
+
 // getRandomCities fetches random cities from the earth map
 func (m *EarthMap) getRandomCities(numCities int) []*city {
-	// Seed the random number generator
-	rand.Seed(time.Now().UnixNano())
-
 	// Gather the cities (keys)
 	var (
 		totalCities = len(m.cityMap)
@@ -324,19 +863,40 @@ func (m *EarthMap) getRandomCities(numCities int) []*city {
 		index++
 	}
 
+	// The map iteration order is random regardless of the seed,
+	// so the cities are sorted for the seed to fully determine the picks
+	sort.Strings(cities)
+
 	// Randomly distribute the cities
 	randomCities := make([]*city, numCities)
 	for i := 0; i < numCities; i++ {
-		//nolint:gosec
-		randomCities[i] = m.cityMap[cities[rand.Intn(totalCities)]]
+		randomCities[i] = m.cityMap[cities[m.rng.Intn(totalCities)]]
 	}
 
 	return randomCities
 }
 
+// isStalemate checks if no remaining city has an accessible neighbor,
+// meaning no alien can move and no further destruction is possible
+func (m *EarthMap) isStalemate() bool {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	for _, city := range m.cityMap {
+		if !city.isDestroyed() && city.hasAccessibleNeighbors() {
+			return false
+		}
+	}
+
+	return true
+}
+
 // pruneDestroyedCities removes destroyed cities from the earth map.
 // Returns the number of pruned destroyed cities
 func (m *EarthMap) pruneDestroyedCities() int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
 	destroyed := 0
 	for _, city := range m.cityMap {
 		// Prune out any destroyed cities
@@ -346,5 +906,98 @@ func (m *EarthMap) pruneDestroyedCities() int {
 		}
 	}
 
+	m.prunedCount += destroyed
+
 	return destroyed
 }
+
+// DestroyedCities returns the sorted names of the destroyed cities
+// that are still present on the earth map. Destroyed cities are only
+// kept on the map after the simulation if auto-pruning is disabled.
+// It is safe to call concurrently with a running simulation
+func (m *EarthMap) DestroyedCities() []string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	destroyed := make([]string, 0)
+
+	for _, city := range m.cityMap {
+		if city.isDestroyed() {
+			destroyed = append(destroyed, city.name)
+		}
+	}
+
+	sort.Strings(destroyed)
+
+	return destroyed
+}
+
+// DestroyedCount returns the total number of cities destroyed so far,
+// including the destroyed cities already pruned out of the map.
+// It is safe to call concurrently with a running simulation
+func (m *EarthMap) DestroyedCount() int {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	destroyed := m.prunedCount
+
+	for _, city := range m.cityMap {
+		if city.isDestroyed() {
+			destroyed++
+		}
+	}
+
+	return destroyed
+}
+
+// Cities returns the sorted names of the cities present on the map.
+// Destroyed cities are present until they are pruned out at the end of the simulation.
+// It is safe to call concurrently with a running simulation
+func (m *EarthMap) Cities() []string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	cities := make([]string, 0, len(m.cityMap))
+
+	for name := range m.cityMap {
+		cities = append(cities, name)
+	}
+
+	sort.Strings(cities)
+
+	return cities
+}
+
+// Neighbors returns the roads leading out of the city with the given name,
+// in the order they are output. Destroyed neighbors are included until they are pruned out.
+// It is safe to call concurrently with a running simulation
+func (m *EarthMap) Neighbors(name string) ([]Road, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	c := m.getCity(name)
+	if c == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCityNotFound, name)
+	}
+
+	roads := make([]Road, 0, len(c.neighbors))
+
+	for _, direction := range c.outputDirections() {
+		neighbor := c.neighbors[direction]
+		if neighbor == nil {
+			continue
+		}
+
+		roads = append(roads, Road{
+			Direction: direction.getName(),
+			City:      neighbor.name,
+		})
+	}
+
+	return roads, nil
+}
+
+// Seed returns the seed used by the map's random number generator
+func (m *EarthMap) Seed() int64 {
+	return m.config.seed
+}
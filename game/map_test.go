@@ -1,9 +1,19 @@
 package game
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -11,55 +21,32 @@ import (
 	"github.com/zivkovicmilos/alien-invasion/stream"
 )
 
-// arrayReader is a simple city array input reader used for testing
-type arrayReader struct {
-	cityArray []string
-	index     int
-}
-
-func newArrayReader(cityArray []string) stream.InputReader {
-	return &arrayReader{
-		cityArray: cityArray,
-		index:     0,
-	}
-}
-
-func (ar *arrayReader) HasMoreCities() bool {
-	return ar.index < len(ar.cityArray)
-}
-
-func (ar *arrayReader) ReadCity() string {
-	line := ar.cityArray[ar.index]
-	ar.index++
-
-	return line
-}
-
-func (ar *arrayReader) Close() error {
-	return nil
-}
+// failingWriter is an output writer that fails on the Nth write
+type failingWriter struct {
+	*stream.SliceWriter
 
-type arrayWriter struct {
-	outputArray []string
+	failAt  int
+	flushed bool
 }
 
-func newArrayWriter() *arrayWriter {
-	return &arrayWriter{
-		outputArray: make([]string, 0),
+func newFailingWriter(failAt int) *failingWriter {
+	return &failingWriter{
+		SliceWriter: stream.NewSliceWriter(),
+		failAt:      failAt,
 	}
 }
 
-func (aw *arrayWriter) Write(s string) error {
-	aw.outputArray = append(aw.outputArray, s)
+func (fw *failingWriter) Write(s string) error {
+	if len(fw.Lines())+1 == fw.failAt {
+		return errors.New("no space left on device")
+	}
 
-	return nil
+	return fw.SliceWriter.Write(s)
 }
 
-func (aw *arrayWriter) Flush() error {
-	return nil
-}
+func (fw *failingWriter) Flush() error {
+	fw.flushed = true
 
-func (aw *arrayWriter) Close() error {
 	return nil
 }
 
@@ -116,7 +103,7 @@ func TestMap_InitMap(t *testing.T) {
 	)
 
 	// Create a mock input reader
-	reader := newArrayReader(cityInputs)
+	reader := stream.NewSliceReader(cityInputs)
 
 	// Create an instance of the earth map
 	earthMap := NewEarthMap(hclog.NewNullLogger())
@@ -167,7 +154,7 @@ func TestMap_RemoveCity(t *testing.T) {
 	)
 
 	// Create a mock input reader
-	reader := newArrayReader(cityInputs)
+	reader := stream.NewSliceReader(cityInputs)
 
 	// Create an instance of the earth map
 	earthMap := NewEarthMap(hclog.NewNullLogger())
@@ -206,7 +193,7 @@ func TestMap_WriteOutput(t *testing.T) {
 	}
 
 	// Create a mock input reader
-	reader := newArrayReader(cityInputs)
+	reader := stream.NewSliceReader(cityInputs)
 
 	// Create an instance of the earth map
 	earthMap := NewEarthMap(hclog.NewNullLogger())
@@ -218,16 +205,16 @@ func TestMap_WriteOutput(t *testing.T) {
 	assert.Len(t, earthMap.cityMap, 2)
 
 	// Create a mock output writer
-	writer := newArrayWriter()
+	writer := stream.NewSliceWriter()
 
 	// Write the output
 	assert.NoError(t, earthMap.WriteOutput(writer))
 
 	// Make sure the output is the same as the input
 	// in this test case
-	assert.Len(t, writer.outputArray, len(cityInputs))
+	assert.Len(t, writer.Lines(), len(cityInputs))
 
-	for _, outputLine := range writer.outputArray {
+	for _, outputLine := range writer.Lines() {
 		// Make sure the output exactly matches one of the inputs
 		// as nothing is unchanged in the map
 		matchFound := false
@@ -247,6 +234,204 @@ func TestMap_WriteOutput(t *testing.T) {
 	}
 }
 
+// TestMap_WriteOutput_PartialFailure checks that a failing output
+// stream is flushed, and that the error reports the written city count
+func TestMap_WriteOutput_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	cityInputs := []string{
+		"Foo north=Bar",
+		"Bar south=Foo east=Baz",
+		"Baz west=Bar",
+	}
+
+	// Create an instance of the earth map
+	earthMap := NewEarthMap(hclog.NewNullLogger())
+
+	// Initialize the earth map using the reader
+	earthMap.InitMap(stream.NewSliceReader(cityInputs))
+
+	// Create a mock output writer that fails on the third write
+	writer := newFailingWriter(3)
+
+	// Write the output
+	err := earthMap.WriteOutput(writer)
+	if err == nil {
+		t.Fatal("write output should fail")
+	}
+
+	// Make sure the error contains the number of written cities
+	assert.Contains(t, err.Error(), "after 2 cities were written")
+
+	// Make sure the partial output was flushed
+	assert.True(t, writer.flushed)
+	assert.Len(t, writer.Lines(), 2)
+}
+
+// cancellingWriter is an output writer that cancels
+// the output context after the Nth write
+type cancellingWriter struct {
+	failingWriter
+
+	cancelAt int
+	cancel   context.CancelFunc
+}
+
+func (cw *cancellingWriter) Write(s string) error {
+	if err := cw.failingWriter.Write(s); err != nil {
+		return err
+	}
+
+	if len(cw.Lines()) == cw.cancelAt {
+		cw.cancel()
+	}
+
+	return nil
+}
+
+// TestMap_WriteOutputCtx_Cancelled checks that a cancelled output
+// stops between cities, and that the partial output is flushed
+func TestMap_WriteOutputCtx_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	cityInputs := []string{
+		"Bar south=Foo",
+		"Baz north=Foo",
+		"Foo north=Bar south=Baz east=Qux west=Quux",
+		"Quux east=Foo",
+		"Qux west=Foo",
+	}
+
+	// Create an instance of the earth map
+	earthMap := NewEarthMap(hclog.NewNullLogger())
+
+	// Initialize the earth map using the reader
+	earthMap.InitMap(stream.NewSliceReader(cityInputs))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Create a mock output writer that cancels the output after the second write
+	writer := &cancellingWriter{
+		failingWriter: *newFailingWriter(0),
+		cancelAt:      2,
+		cancel:        cancel,
+	}
+
+	// Write the output
+	err := earthMap.WriteOutputCtx(ctx, writer)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), "after 2 cities were written")
+
+	// Make sure only the cities before the cancellation were written and flushed
+	assert.True(t, writer.flushed)
+	assert.Equal(
+		t,
+		[]string{
+			"Bar south=Foo\n",
+			"Baz north=Foo\n",
+		},
+		writer.Lines(),
+	)
+}
+
+// TestMap_WriteOutput_Sorted makes sure the output
+// follows the configured sort mode
+func TestMap_WriteOutput_Sorted(t *testing.T) {
+	t.Parallel()
+
+	cityInputs := []string{
+		"Alpha east=Hub",
+		"Hub north=Mid south=Zeta",
+		"Mid east=Yonder",
+		"Lone",
+	}
+
+	testTable := []struct {
+		name string
+		mode SortMode
+
+		expectedOutput []string
+	}{
+		{
+			"sort by name",
+			SortByName,
+			[]string{
+				"Alpha east=Hub\n",
+				"Hub north=Mid south=Zeta west=Alpha\n",
+				"Lone\n",
+				"Mid south=Hub east=Yonder\n",
+				"Yonder west=Mid\n",
+				"Zeta north=Hub\n",
+			},
+		},
+		{
+			"sort by degree descending",
+			SortByDegreeDesc,
+			[]string{
+				"Hub north=Mid south=Zeta west=Alpha\n",
+				"Mid south=Hub east=Yonder\n",
+				"Alpha east=Hub\n",
+				"Yonder west=Mid\n",
+				"Zeta north=Hub\n",
+				"Lone\n",
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			earthMap := NewEarthMap(hclog.NewNullLogger(), WithOutputSort(testCase.mode))
+			earthMap.InitMap(stream.NewSliceReader(cityInputs))
+
+			writer := stream.NewSliceWriter()
+
+			assert.NoError(t, earthMap.WriteOutput(writer))
+			assert.Equal(t, testCase.expectedOutput, writer.Lines())
+		})
+	}
+}
+
+// TestMap_WriteOutput_PreservedOrder makes sure a sorted input map
+// round-trips byte-identically when the declaration order is preserved
+func TestMap_WriteOutput_PreservedOrder(t *testing.T) {
+	t.Parallel()
+
+	cityInputs := []string{
+		"Bar west=Bee south=Foo",
+		"Baz east=Foo",
+		"Bee east=Bar",
+		"Foo west=Baz south=Qu-ux north=Bar",
+		"Qu-ux north=Foo",
+	}
+
+	earthMap := NewEarthMap(hclog.NewNullLogger(), WithPreservedOrder())
+	earthMap.InitMap(stream.NewSliceReader(cityInputs))
+
+	writer := stream.NewSliceWriter()
+
+	assert.NoError(t, earthMap.WriteOutput(writer))
+	assert.Equal(
+		t,
+		strings.Join(cityInputs, "\n")+"\n",
+		strings.Join(writer.Lines(), ""),
+	)
+
+	// Without the option, the fixed direction order is used
+	earthMap = NewEarthMap(hclog.NewNullLogger())
+	earthMap.InitMap(stream.NewSliceReader(cityInputs))
+
+	writer = stream.NewSliceWriter()
+
+	assert.NoError(t, earthMap.WriteOutput(writer))
+	assert.Equal(t, "Foo north=Bar south=Qu-ux west=Baz\n", writer.Lines()[3])
+}
+
 // TestMap_GetRandomCities makes sure random cities are properly sampled
 // from the earth map
 func TestMap_GetRandomCities(t *testing.T) {
@@ -259,7 +444,7 @@ func TestMap_GetRandomCities(t *testing.T) {
 	}
 
 	// Create a mock input reader
-	reader := newArrayReader(cityInputs)
+	reader := stream.NewSliceReader(cityInputs)
 
 	// Create an instance of the earth map
 	earthMap := NewEarthMap(hclog.NewNullLogger())
@@ -279,6 +464,38 @@ func TestMap_GetRandomCities(t *testing.T) {
 	}
 }
 
+// TestMap_GetRandomCities_Seed makes sure the seed alone determines the sampled cities,
+// regardless of the random iteration order of the city map
+func TestMap_GetRandomCities_Seed(t *testing.T) {
+	t.Parallel()
+
+	cityInputs := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		cityInputs = append(cityInputs, fmt.Sprintf("City%d", i))
+	}
+
+	// sampleCities returns the names of the starting cities
+	// of the aliens, sampled from a freshly initialized map
+	sampleCities := func() []string {
+		earthMap := NewEarthMap(hclog.NewNullLogger(), WithSeed(42))
+
+		assert.NoError(t, earthMap.InitMap(stream.NewSliceReader(cityInputs)))
+
+		names := make([]string, 0, 20)
+		for _, randomCity := range earthMap.getRandomCities(20) {
+			names = append(names, randomCity.name)
+		}
+
+		return names
+	}
+
+	expectedCities := sampleCities()
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, expectedCities, sampleCities())
+	}
+}
+
 // TestMap_PruneDestroyedCities verifies the city pruning
 // functionality from the earth map
 func TestMap_PruneDestroyedCities(t *testing.T) {
@@ -505,3 +722,1317 @@ func TestMap_SimulateInvasion_EmptyMap(t *testing.T) {
 	// Make sure the city map is unchanged
 	assert.Len(t, m.cityMap, 0)
 }
+
+// TestMap_SimulateInvasion_ImmediateDeath makes sure the simulation
+// returns promptly when no placed alien is able to move
+func TestMap_SimulateInvasion_ImmediateDeath(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name      string
+		numAliens int
+	}{
+		{
+			"all aliens trapped",
+			1,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewEarthMap(hclog.NewNullLogger())
+			m.InitMap(stream.NewSliceReader([]string{
+				"Foo",
+			}))
+
+			doneCh := make(chan struct{})
+
+			go func() {
+				defer close(doneCh)
+
+				_, err := m.SimulateInvasion(context.Background(), testCase.numAliens)
+				assert.NoError(t, err)
+			}()
+
+			select {
+			case <-doneCh:
+			case <-time.After(5 * time.Second):
+				t.Fatal("simulation did not terminate")
+			}
+
+			// Make sure no city was destroyed
+			assert.Equal(t, 0, m.DestroyedCount())
+			assert.Equal(t, []string{"Foo"}, m.Cities())
+		})
+	}
+}
+
+// TestMap_SimulateInvasion_Result makes sure the simulation
+// outcome is properly reported
+func TestMap_SimulateInvasion_Result(t *testing.T) {
+	t.Parallel()
+
+	t.Run("completed simulation", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewEarthMap(hclog.NewNullLogger())
+		m.InitMap(stream.NewSliceReader([]string{
+			"Foo",
+		}))
+
+		// Both aliens land in the only city, and destroy it
+		result, err := m.SimulateInvasion(context.Background(), 2)
+		if err != nil {
+			t.Fatalf("unable to simulate the invasion, %v", err)
+		}
+
+		assert.Equal(
+			t,
+			SimulationResult{
+				Aliens:          2,
+				CitiesDestroyed: 1,
+				Interrupted:     false,
+			},
+			result,
+		)
+	})
+
+	t.Run("interrupted simulation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancelFn := context.WithCancel(context.Background())
+		cancelFn()
+
+		result, err := GenerateGridMap(10, 10).SimulateInvasion(ctx, 1)
+		if err != nil {
+			t.Fatalf("unable to simulate the invasion, %v", err)
+		}
+
+		assert.True(t, result.Interrupted)
+	})
+}
+
+// TestMap_DestroyedCities makes sure the destroyed cities
+// are properly listed in sorted order
+func TestMap_DestroyedCities(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger(), WithoutAutoPrune())
+
+	m.InitMap(stream.NewSliceReader([]string{
+		"Foo north=Bar",
+		"Bar east=Baz",
+		"Qu-ux",
+	}))
+
+	// No cities are destroyed initially
+	assert.Len(t, m.DestroyedCities(), 0)
+
+	// Mark some cities as destroyed
+	m.getCity("Foo").destroyed = true
+	m.getCity("Baz").destroyed = true
+
+	assert.Equal(t, []string{"Baz", "Foo"}, m.DestroyedCities())
+}
+
+// TestMap_SimulateInvasion_WithoutAutoPrune makes sure destroyed
+// cities are kept on the map when auto-pruning is disabled
+func TestMap_SimulateInvasion_WithoutAutoPrune(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m     = NewEarthMap(hclog.NewNullLogger(), WithoutAutoPrune())
+		cityA = newCity("city A")
+		cityB = newCity("city B")
+	)
+
+	cityA.neighbors = neighbors{
+		north: cityB,
+	}
+
+	cityB.neighbors = neighbors{
+		south: cityA,
+	}
+
+	m.addCity(cityA)
+	m.addCity(cityB)
+
+	// Start the simulation with many aliens
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	m.SimulateInvasion(ctx, 30)
+
+	// Make sure all cities were destroyed, but kept on the map
+	assert.Len(t, m.cityMap, 2)
+	assert.Equal(t, []string{"city A", "city B"}, m.DestroyedCities())
+}
+
+// TestMap_ConcurrentReads makes sure the map can be safely queried
+// while the invasion simulation is running
+func TestMap_ConcurrentReads(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m = GenerateGridMap(10, 10)
+
+		wg     sync.WaitGroup
+		doneCh = make(chan struct{})
+	)
+
+	// Start the readers hammering the map
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-doneCh:
+					return
+				default:
+					assert.LessOrEqual(t, m.DestroyedCount(), 100)
+					assert.LessOrEqual(t, len(m.Cities()), 100)
+					assert.LessOrEqual(t, len(m.DestroyedCities()), 100)
+				}
+			}
+		}()
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelFn()
+
+	m.SimulateInvasion(ctx, 10000)
+
+	close(doneCh)
+	wg.Wait()
+
+	// Make sure the destroyed count accounts for the pruned cities
+	assert.Equal(t, 100-len(m.Cities()), m.DestroyedCount())
+	assert.Greater(t, m.DestroyedCount(), 0)
+}
+
+// TestMap_RemoveCity_Cascade makes sure cities left without
+// neighbors are cascade-removed when requested
+func TestMap_RemoveCity_Cascade(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chainInputs = []string{
+			"A east=B",
+			"B east=C",
+			"Lonely",
+		}
+
+		// Hub H has 4 spokes, and spoke N leads further north to X
+		starInputs = []string{
+			"H north=N south=S east=E west=W",
+			"N north=X",
+			"Lonely",
+		}
+	)
+
+	testTable := []struct {
+		name    string
+		inputs  []string
+		city    string
+		cascade bool
+
+		expectedRemoved []string
+		expectedCities  []string
+	}{
+		{
+			"chain without cascade",
+			chainInputs,
+			"B",
+			false,
+			[]string{"B"},
+			[]string{"A", "C", "Lonely"},
+		},
+		{
+			"chain with cascade",
+			chainInputs,
+			"B",
+			true,
+			[]string{"B", "C", "A"},
+			[]string{"Lonely"},
+		},
+		{
+			"star leaf with cascade",
+			starInputs,
+			"E",
+			true,
+			[]string{"E"},
+			[]string{"H", "Lonely", "N", "S", "W", "X"},
+		},
+		{
+			"star hub with cascade",
+			starInputs,
+			"H",
+			true,
+			[]string{"H", "S", "E", "W"},
+			[]string{"Lonely", "N", "X"},
+		},
+		{
+			"star spoke chain with cascade",
+			starInputs,
+			"N",
+			true,
+			[]string{"N", "X"},
+			[]string{"E", "H", "Lonely", "S", "W"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewEarthMap(hclog.NewNullLogger())
+			m.InitMap(stream.NewSliceReader(testCase.inputs))
+
+			removed, err := m.RemoveCity(testCase.city, testCase.cascade)
+			if err != nil {
+				t.Fatalf("unable to remove city, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedRemoved, removed)
+			assert.Equal(t, testCase.expectedCities, m.Cities())
+			assert.NoError(t, m.CheckInvariants())
+		})
+	}
+}
+
+// TestMap_RemoveCity_Missing makes sure removing
+// a non-existing city returns an error
+func TestMap_RemoveCity_Missing(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+	m.InitMap(stream.NewSliceReader([]string{"Foo north=Bar"}))
+
+	removed, err := m.RemoveCity("Baz", true)
+
+	assert.ErrorIs(t, err, ErrCityNotFound)
+	assert.Nil(t, removed)
+	assert.Len(t, m.cityMap, 2)
+}
+
+// TestMap_IsStalemate makes sure a map where no city
+// has an accessible neighbor is detected
+func TestMap_IsStalemate(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name      string
+		destroyed []string
+
+		expectedStalemate bool
+	}{
+		{
+			"intact map",
+			[]string{},
+			false,
+		},
+		{
+			"partially collapsed map",
+			[]string{"B"},
+			false,
+		},
+		{
+			"map collapsed into isolated cities",
+			[]string{"B", "D"},
+			true,
+		},
+		{
+			"fully destroyed map",
+			[]string{"A", "B", "C", "D", "E"},
+			true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create a line map A-B-C-D-E
+			m := NewEarthMap(hclog.NewNullLogger())
+			m.InitMap(stream.NewSliceReader([]string{
+				"A east=B",
+				"B east=C",
+				"C east=D",
+				"D east=E",
+			}))
+
+			for _, name := range testCase.destroyed {
+				m.getCity(name).destroyed = true
+			}
+
+			assert.Equal(t, testCase.expectedStalemate, m.isStalemate())
+		})
+	}
+}
+
+// TestMap_SimulateInvasion_EarlyTermination makes sure the simulation
+// terminates once the map collapses into isolated cities
+func TestMap_SimulateInvasion_EarlyTermination(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger(), WithEarlyTermination(), WithoutAutoPrune())
+	m.InitMap(stream.NewSliceReader([]string{
+		"A east=B",
+		"B east=C",
+		"C east=D",
+		"D east=E",
+	}))
+
+	// The middle cities are already destroyed,
+	// so the map is collapsed from the start
+	m.getCity("B").destroyed = true
+	m.getCity("D").destroyed = true
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	m.SimulateInvasion(ctx, 1)
+
+	// Make sure the simulation terminated before the timeout
+	assert.NoError(t, ctx.Err())
+	assert.True(t, m.isStalemate())
+}
+
+// endlessReader is a synthetic input reader
+// that never runs out of city lines
+type endlessReader struct {
+	index int
+}
+
+func (er *endlessReader) HasMoreCities() bool {
+	return true
+}
+
+func (er *endlessReader) ReadCity() (string, error) {
+	er.index++
+
+	return fmt.Sprintf("City_%d east=City_%d", er.index, er.index+1), nil
+}
+
+func (er *endlessReader) Err() error {
+	return nil
+}
+
+func (er *endlessReader) Close() error {
+	return nil
+}
+
+// TestMap_InitMap_Comments makes sure the comment lines,
+// like the metadata header, are not parsed as cities
+func TestMap_InitMap_Comments(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"# aliens: 10", "# seed: 42", "Foo north=Bar", "  # Bar is implied"}
+
+	for _, opts := range [][]Option{nil, {WithStrictParsing()}} {
+		m := NewEarthMap(hclog.NewNullLogger(), opts...)
+
+		assert.NoError(t, m.InitMap(stream.NewSliceReader(lines)))
+		assert.Equal(t, []string{"Bar", "Foo"}, m.Cities())
+	}
+}
+
+// TestMap_InitMap_MaxCities makes sure the map size
+// is capped by the max city count
+func TestMap_InitMap_MaxCities(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger(), WithMaxCities(100))
+
+	assert.ErrorIs(t, m.InitMap(&endlessReader{}), ErrMapTooLarge)
+	assert.LessOrEqual(t, len(m.cityMap), 102)
+
+	// Make sure unlimited maps accept any size
+	m = NewEarthMap(hclog.NewNullLogger(), WithMaxCities(0))
+
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo north=Bar", "Baz"})))
+}
+
+// TestMap_InitMap_LongLine makes sure city lines longer than bufio.MaxScanTokenSize
+// parse by default, and lines longer than the reader's max line size are reported
+func TestMap_InitMap_LongLine(t *testing.T) {
+	t.Parallel()
+
+	var (
+		longName = strings.Repeat("B", 2*bufio.MaxScanTokenSize)
+		mapPath  = filepath.Join(t.TempDir(), "map.txt")
+	)
+
+	if err := os.WriteFile(mapPath, []byte("Foo north="+longName), 0o600); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	// readMap initializes a new map from the map file,
+	// using the given max line size (0 for the default)
+	readMap := func(maxLineSize int) (*EarthMap, error) {
+		reader, err := stream.NewFileReaderConcrete(mapPath)
+		if err != nil {
+			t.Fatalf("unable to create file reader, %v", err)
+		}
+
+		defer reader.Close()
+
+		if maxLineSize > 0 {
+			reader.SetMaxLineSize(maxLineSize)
+		}
+
+		m := NewEarthMap(hclog.NewNullLogger())
+
+		return m, m.InitMap(reader)
+	}
+
+	m, err := readMap(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{longName, "Foo"}, m.Cities())
+
+	_, err = readMap(bufio.MaxScanTokenSize)
+	assert.ErrorIs(t, err, bufio.ErrTooLong)
+}
+
+// TestMap_InitMap_ReadError makes sure a read error that cuts the
+// map short fails the initialization, instead of passing for a smaller map
+func TestMap_InitMap_ReadError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errRead = errors.New("connection reset")
+		reader  = stream.NewScannerReader(io.MultiReader(
+			strings.NewReader("Foo north=Bar\n"),
+			iotest.ErrReader(errRead),
+		))
+		m = NewEarthMap(hclog.NewNullLogger())
+	)
+
+	err := m.InitMap(reader)
+
+	assert.ErrorIs(t, err, errRead)
+	assert.ErrorContains(t, err, "unable to read the map")
+}
+
+// failingReader is a synthetic input reader that fails to read
+// the city line at the given index, or stops the reading there
+type failingReader struct {
+	stream.InputReader
+
+	read    int   // the number of city lines read so far
+	failAt  int   // the index of the failing city line
+	lineErr error // the error of reading the failing line, if any
+	err     error // the error that stops the reading at the failing line, if any
+}
+
+func (fr *failingReader) HasMoreCities() bool {
+	if fr.read == fr.failAt && fr.err != nil {
+		return false
+	}
+
+	return fr.InputReader.HasMoreCities()
+}
+
+func (fr *failingReader) ReadCity() (string, error) {
+	if fr.read == fr.failAt && fr.lineErr != nil {
+		return "", fr.lineErr
+	}
+
+	fr.read++
+
+	return fr.InputReader.ReadCity()
+}
+
+func (fr *failingReader) Err() error {
+	if fr.read == fr.failAt {
+		return fr.err
+	}
+
+	return nil
+}
+
+// TestMap_InitMap_FailingReader makes sure the errors reported by the reader,
+// either for a single line or after the reading stops, fail the initialization
+func TestMap_InitMap_FailingReader(t *testing.T) {
+	t.Parallel()
+
+	errRead := errors.New("input/output error")
+
+	testTable := []struct {
+		name          string
+		reader        *failingReader
+		expectedCause string
+	}{
+		{
+			"line read error",
+			&failingReader{failAt: 1, lineErr: errRead},
+			"unable to read line 2",
+		},
+		{
+			"reading stopped",
+			&failingReader{failAt: 1, err: errRead},
+			"unable to read the map",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCase.reader.InputReader = stream.NewSliceReader([]string{"Foo north=Bar", "Bar east=Baz", "Baz"})
+
+			m := NewEarthMap(hclog.NewNullLogger())
+			err := m.InitMap(testCase.reader)
+
+			assert.ErrorIs(t, err, errRead)
+			assert.ErrorContains(t, err, testCase.expectedCause)
+		})
+	}
+}
+
+// TestMap_SimulateInvasion_AlienEnergy makes sure the aliens
+// with limited energy starve, and are reported in the result
+func TestMap_SimulateInvasion_AlienEnergy(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(
+		hclog.NewNullLogger(),
+		WithMaxMoves(20),
+		WithAlienEnergy(func(int) int {
+			return 5
+		}),
+	)
+
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo north=Bar"})))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	result, err := m.SimulateInvasion(ctx, 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, result.StarvedAliens)
+	assert.Equal(t, 5, result.TotalCost)
+}
+
+// TestMap_SimulateInvasion_MaxAliens makes sure the alien count
+// is capped by the max alien count
+func TestMap_SimulateInvasion_MaxAliens(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger(), WithMaxAliens(10))
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo north=Bar"})))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	_, err := m.SimulateInvasion(ctx, 11)
+	assert.ErrorIs(t, err, ErrTooManyAliens)
+
+	// Make sure the map is untouched
+	assert.Len(t, m.cityMap, 2)
+
+	// Make sure unlimited simulations accept any alien count
+	WithMaxAliens(0)(m)
+
+	_, err = m.SimulateInvasion(ctx, 11)
+	assert.NoError(t, err)
+}
+
+// TestMap_SimulateInvasion_InvalidAliens makes sure non-positive alien counts
+// are rejected with a zeroed result, before any alien is spawned or the map is touched
+func TestMap_SimulateInvasion_InvalidAliens(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name      string
+		numAliens int
+	}{
+		{
+			"zero aliens",
+			0,
+		},
+		{
+			"negative aliens",
+			-5,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewEarthMap(hclog.NewNullLogger())
+			assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo north=Bar"})))
+
+			result, err := m.SimulateInvasion(context.Background(), testCase.numAliens)
+			assert.ErrorIs(t, err, ErrInvalidAliens)
+
+			// Make sure nothing was simulated, and the map is untouched
+			assert.Equal(t, SimulationResult{}, result)
+			assert.Equal(t, Progress{}, m.Progress())
+			assert.Len(t, m.cityMap, 2)
+		})
+	}
+}
+
+// FuzzInitMap makes sure arbitrary input lines never crash the map parser,
+// and that the resulting map always satisfies the structural invariants
+func FuzzInitMap(f *testing.F) {
+	seedCorpus := []string{
+		"Foo north=Bar west=Baz south=Qu-ux\nBar south=Foo west=Bee\n",
+		"Foo north=Bar\nBar south=Foo east=Baz\nBaz west=Bar\n",
+		"A east=B\nB south=C\nX north=Y\n",
+		"Foo\n\n north=Baz\n",
+		"Foo north=Bar north=Baz\n",
+		"Foo north=Foo\n",
+		"Foo north=Bar\nFoo north=Baz\n",
+		"Foo up=Bar\n",
+	}
+
+	for _, seed := range seedCorpus {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		m := NewEarthMap(hclog.NewNullLogger())
+
+		if err := m.InitMap(stream.NewSliceReader(strings.Split(input, "\n"))); err != nil {
+			return
+		}
+
+		if err := m.CheckInvariants(); err != nil {
+			t.Fatalf("map invariants violated for input %q, %v", input, err)
+		}
+	})
+}
+
+// TestMap_SimulateInvasion_StartBarrier makes sure no alien starts
+// moving before every alien has been placed on the map
+func TestMap_SimulateInvasion_StartBarrier(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m = GenerateGridMap(100, 100)
+
+		mux            sync.Mutex
+		observedPlaced = make([]int, 0)
+	)
+
+	// countInvaders counts the aliens present in the map cities
+	countInvaders := func() int {
+		count := 0
+
+		for _, c := range m.cityMap {
+			c.RLock()
+			count += c.numInvaders()
+			c.RUnlock()
+		}
+
+		return count
+	}
+
+	// Every alien records how many aliens were placed when it
+	// started, and dies in place, so the count never decreases
+	withAlienBehavior(func(a *alien, current *city) *city {
+		placed := countInvaders()
+
+		mux.Lock()
+		observedPlaced = append(observedPlaced, placed)
+		mux.Unlock()
+
+		return nil
+	})(m)
+
+	WithoutAutoPrune()(m)
+
+	_, err := m.SimulateInvasion(context.Background(), 500)
+	assert.NoError(t, err)
+
+	totalPlaced := countInvaders()
+
+	assert.NotEmpty(t, observedPlaced)
+
+	for _, placed := range observedPlaced {
+		assert.Equal(t, totalPlaced, placed)
+	}
+}
+
+// TestMap_TravelCosts makes sure road travel costs are parsed,
+// written back out, and ignored when travel costs are disabled
+func TestMap_TravelCosts(t *testing.T) {
+	t.Parallel()
+
+	cityInputs := []string{
+		"A east=B:3 west=C",
+		"B west=A:3",
+		"C east=A",
+	}
+
+	earthMap := NewEarthMap(hclog.NewNullLogger(), WithTravelCosts())
+	assert.NoError(t, earthMap.InitMap(stream.NewSliceReader(cityInputs)))
+
+	a := earthMap.getCity("A")
+	if !assert.NotNil(t, a) {
+		return
+	}
+
+	assert.Equal(t, 3, a.costTo(earthMap.getCity("B")))
+	assert.Equal(t, 1, a.costTo(earthMap.getCity("C")))
+	assert.Equal(t, 3, earthMap.getCity("B").costTo(a))
+
+	writer := stream.NewSliceWriter()
+
+	assert.NoError(t, earthMap.WriteOutput(writer))
+	assert.Equal(
+		t,
+		strings.Join(cityInputs, "\n")+"\n",
+		strings.Join(writer.Lines(), ""),
+	)
+
+	// Without travel costs, the suffix is part of the city name
+	earthMap = NewEarthMap(hclog.NewNullLogger())
+	assert.NoError(t, earthMap.InitMap(stream.NewSliceReader([]string{"A east=B:3"})))
+
+	assert.NotNil(t, earthMap.getCity("B:3"))
+	assert.Nil(t, earthMap.getCity("B"))
+}
+
+// TestMap_TravelCosts_Invalid makes sure the lines with an invalid travel cost
+// are rejected in strict parsing, and skipped as a whole otherwise
+func TestMap_TravelCosts_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, definition := range []string{"Bar:0", "Bar:-2", "Bar:far", "Bar:", ":3"} {
+		definition := definition
+
+		t.Run(definition, func(t *testing.T) {
+			t.Parallel()
+
+			cityLine := fmt.Sprintf("Foo west=Baz north=%s", definition)
+
+			// Strict parsing fails on the line
+			earthMap := NewEarthMap(hclog.NewNullLogger(), WithTravelCosts(), WithStrictParsing())
+
+			err := earthMap.InitMap(stream.NewSliceReader([]string{cityLine}))
+			assert.ErrorIs(t, err, ErrInvalidCityLine)
+
+			// Lenient parsing skips the line, without adding any of its cities
+			earthMap = NewEarthMap(hclog.NewNullLogger(), WithTravelCosts())
+
+			assert.NoError(t, earthMap.InitMap(stream.NewSliceReader([]string{cityLine, "Bee east=Baz"})))
+			assert.Nil(t, earthMap.getCity("Foo"))
+			assert.Nil(t, earthMap.getCity("Bar"))
+			assert.Len(t, earthMap.cityMap, 2)
+		})
+	}
+}
+
+// TestMap_SimulateInvasion_TravelCosts makes sure the aliens spend
+// their max moves as a travel budget, and the total cost is reported
+func TestMap_SimulateInvasion_TravelCosts(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name         string
+		cityInputs   []string
+		opts         []Option
+		expectedCost int
+	}{
+		{
+			"costly road exhausts the budget",
+			[]string{"A east=B:5"},
+			[]Option{WithTravelCosts(), WithMaxMoves(10)},
+			10,
+		},
+		{
+			"costly road over the rest of the budget is not taken",
+			[]string{"A east=B:4 west=C:4"},
+			[]Option{WithTravelCosts(), WithMaxMoves(6)},
+			4,
+		},
+		{
+			"default costs count moves",
+			[]string{"A east=B west=C"},
+			[]Option{WithTravelCosts(), WithMaxMoves(7)},
+			7,
+		},
+		{
+			"costs disabled",
+			[]string{"A east=B:5"},
+			[]Option{WithMaxMoves(10)},
+			10,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			earthMap := NewEarthMap(hclog.NewNullLogger(), testCase.opts...)
+			assert.NoError(t, earthMap.InitMap(stream.NewSliceReader(testCase.cityInputs)))
+
+			result, err := earthMap.SimulateInvasion(context.Background(), 1)
+			assert.NoError(t, err)
+
+			assert.Equal(t, testCase.expectedCost, result.TotalCost)
+		})
+	}
+}
+
+// TestMap_SimulateInvasion_DestructionListener makes sure the destruction
+// listener is notified of each destroyed city
+func TestMap_SimulateInvasion_DestructionListener(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux          sync.Mutex
+		destructions = make([]Destruction, 0)
+	)
+
+	m := NewEarthMap(hclog.NewNullLogger(), WithDestructionListener(func(destruction Destruction) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		destructions = append(destructions, destruction)
+	}))
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo"})))
+
+	// Both aliens land in the only city, and destroy it
+	_, err := m.SimulateInvasion(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unable to simulate the invasion, %v", err)
+	}
+
+	assert.Equal(
+		t,
+		[]Destruction{
+			{
+				City:   "Foo",
+				Aliens: []int{0, 1},
+			},
+		},
+		destructions,
+	)
+}
+
+// TestMap_SimulateInvasion_DestructionListener_QueriesMap makes sure the destruction
+// listener can query the map without blocking the simulation
+func TestMap_SimulateInvasion_DestructionListener_QueriesMap(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name       string
+		cityInputs []string
+		numAliens  int
+	}{
+		{
+			"destroyed on placement",
+			[]string{
+				"Foo",
+			},
+			2,
+		},
+		{
+			"destroyed on moves",
+			[]string{
+				"Foo north=Bar",
+				"Bar south=Foo",
+			},
+			10,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				m *EarthMap
+
+				mux             sync.Mutex
+				destroyedCounts = make([]int, 0)
+			)
+
+			m = NewEarthMap(hclog.NewNullLogger(), WithSeed(42), WithDestructionListener(func(Destruction) {
+				destroyedCount := m.DestroyedCount()
+
+				mux.Lock()
+				defer mux.Unlock()
+
+				destroyedCounts = append(destroyedCounts, destroyedCount)
+			}))
+			assert.NoError(t, m.InitMap(stream.NewSliceReader(testCase.cityInputs)))
+
+			doneCh := make(chan struct{})
+
+			go func() {
+				defer close(doneCh)
+
+				_, err := m.SimulateInvasion(context.Background(), testCase.numAliens)
+				assert.NoError(t, err)
+			}()
+
+			select {
+			case <-doneCh:
+			case <-time.After(5 * time.Second):
+				t.Fatal("simulation did not terminate")
+			}
+
+			mux.Lock()
+			defer mux.Unlock()
+
+			// The listener sees each destroyed city as already destroyed
+			assert.NotEmpty(t, destroyedCounts)
+
+			for _, destroyedCount := range destroyedCounts {
+				assert.GreaterOrEqual(t, destroyedCount, 1)
+			}
+		})
+	}
+}
+
+// offsetReader is a synthetic positioned input reader, for a map
+// that starts at the given line of a larger document
+type offsetReader struct {
+	stream.InputReader
+
+	read   int // the number of city lines read so far
+	offset int
+}
+
+func (or *offsetReader) ReadCity() (string, error) {
+	or.read++
+
+	return or.InputReader.ReadCity()
+}
+
+func (or *offsetReader) LineNumber() int {
+	return or.offset + or.read
+}
+
+// TestMap_InitMap_LineNumbers makes sure the invalid lines are reported
+// with their line numbers in the map source, counting the skipped lines
+func TestMap_InitMap_LineNumbers(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"# aliens: 2",
+		"Foo north=Bar",
+		"  # Bar is implied",
+		" north=Baz",
+		"Bar east=Baz",
+		"# Qux is missing",
+		" south=Qux",
+	}
+
+	testTable := []struct {
+		name            string
+		reader          stream.InputReader
+		expectedNumbers []int
+	}{
+		{
+			"scanner reader",
+			stream.NewReader(strings.NewReader(strings.Join(lines, "\n"))),
+			[]int{4, 7},
+		},
+		{
+			"positioned reader",
+			&offsetReader{InputReader: stream.NewSliceReader(lines), offset: 100},
+			[]int{104, 107},
+		},
+		{
+			"reader without positions",
+			stream.NewSliceReader(lines),
+			[]int{4, 7},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logs bytes.Buffer
+
+			m := NewEarthMap(hclog.New(&hclog.LoggerOptions{
+				Output: &logs,
+				Level:  hclog.Warn,
+			}))
+
+			assert.NoError(t, m.InitMap(testCase.reader))
+
+			assert.Contains(t, logs.String(), fmt.Sprintf("Invalid city input line %d:  north=Baz", testCase.expectedNumbers[0]))
+			assert.Contains(t, logs.String(), fmt.Sprintf("Invalid city input line %d:  south=Qux", testCase.expectedNumbers[1]))
+		})
+	}
+}
+
+// TestMap_InitMap_StrictLineNumber makes sure the first invalid line is reported
+// with its line number in the map source, counting the blank and comment lines
+func TestMap_InitMap_StrictLineNumber(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger(), WithStrictParsing())
+
+	err := m.InitMap(stream.NewReader(strings.NewReader("# seed: 42\nFoo north=Bar\n\n# Baz\nBar north=Baz up=Qux\n")))
+
+	assert.ErrorIs(t, err, ErrInvalidCityLine)
+	assert.ErrorContains(t, err, "unable to parse line 5")
+}
+
+// TestMap_InitMap_RepeatedDirection makes sure a direction repeated
+// on a single line is reported, and the first declaration wins
+func TestMap_InitMap_RepeatedDirection(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+
+	earthMap := NewEarthMap(hclog.New(&hclog.LoggerOptions{
+		Output: &logs,
+		Level:  hclog.Warn,
+	}))
+
+	assert.NoError(t, earthMap.InitMap(stream.NewSliceReader([]string{
+		"Foo north=Bar north=Baz west=Bee",
+	})))
+
+	assert.Contains(t, logs.String(), "City Foo declares the north direction 2 times, using the first neighbor Bar")
+
+	// The first declaration wins, and the repeated neighbor is dropped
+	foo := earthMap.getCity("Foo")
+	if !assert.NotNil(t, foo) {
+		return
+	}
+
+	assert.Equal(t, "Bar", foo.neighbors[north].name)
+	assert.Equal(t, "Bee", foo.neighbors[west].name)
+	assert.Nil(t, earthMap.getCity("Baz"))
+}
+
+// TestMap_InitMap_IsolatedCities makes sure the isolated cities
+// are reported when the map is loaded
+func TestMap_InitMap_IsolatedCities(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+
+	earthMap := NewEarthMap(hclog.New(&hclog.LoggerOptions{
+		Output: &logs,
+		Level:  hclog.Warn,
+	}))
+
+	assert.NoError(t, earthMap.InitMap(stream.NewSliceReader([]string{
+		"Foo north=Bar",
+		"Qux",
+		"Baz",
+	})))
+
+	assert.Equal(t, []string{"Baz", "Qux"}, earthMap.IsolatedCities())
+	assert.Contains(t, logs.String(), "The map has 2 isolated cities, which can't be destroyed: Baz, Qux")
+}
+
+// TestMap_InitMap_NoIsolatedCities makes sure nothing
+// is reported when every city has a road
+func TestMap_InitMap_NoIsolatedCities(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+
+	earthMap := NewEarthMap(hclog.New(&hclog.LoggerOptions{
+		Output: &logs,
+		Level:  hclog.Warn,
+	}))
+
+	assert.NoError(t, earthMap.InitMap(stream.NewSliceReader([]string{"Foo north=Bar"})))
+
+	assert.Empty(t, earthMap.IsolatedCities())
+	assert.NotContains(t, logs.String(), "isolated cities")
+}
+
+// TestMap_InitMap_Gzip makes sure the gzip compressed map file
+// initializes the same map as the plain map file
+func TestMap_InitMap_Gzip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mapText = "Foo north=Bar west=Baz\nBar south=Foo\nBaz east=Foo\nQux\n"
+
+		plainPath      = filepath.Join(t.TempDir(), "map.txt")
+		compressedPath = filepath.Join(t.TempDir(), "map.txt.gz")
+
+		compressed bytes.Buffer
+	)
+
+	gzipWriter := gzip.NewWriter(&compressed)
+
+	if _, err := gzipWriter.Write([]byte(mapText)); err != nil {
+		t.Fatalf("unable to compress the map, %v", err)
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("unable to compress the map, %v", err)
+	}
+
+	if err := os.WriteFile(plainPath, []byte(mapText), 0o600); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	if err := os.WriteFile(compressedPath, compressed.Bytes(), 0o600); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	initFromFile := func(path string) *EarthMap {
+		reader, err := stream.NewFileReader(path)
+		if err != nil {
+			t.Fatalf("unable to create file reader, %v", err)
+		}
+
+		defer reader.Close()
+
+		m := NewEarthMap(hclog.NewNullLogger())
+
+		if err := m.InitMap(reader); err != nil {
+			t.Fatalf("unable to initialize the map, %v", err)
+		}
+
+		return m
+	}
+
+	plainMap := initFromFile(plainPath)
+	compressedMap := initFromFile(compressedPath)
+
+	assert.Len(t, compressedMap.cityMap, 4)
+	assert.Equal(t, plainMap.Canonical(), compressedMap.Canonical())
+	assert.True(t, MapsEquivalent(plainMap, compressedMap))
+}
+
+// TestMap_InitMap_WindowsLineEndings makes sure the map saved with a byte order mark,
+// Windows line endings and trailing whitespace is the same as the plain map
+func TestMap_InitMap_WindowsLineEndings(t *testing.T) {
+	t.Parallel()
+
+	// loadMap initializes the map from the reader, and writes it out
+	loadMap := func(reader stream.InputReader) (*EarthMap, []string) {
+		t.Helper()
+
+		defer func() {
+			_ = reader.Close()
+		}()
+
+		m := NewEarthMap(hclog.NewNullLogger(), WithStrictParsing())
+		assert.NoError(t, m.InitMap(reader))
+
+		writer := stream.NewSliceWriter()
+		assert.NoError(t, m.WriteOutput(writer))
+
+		return m, writer.Lines()
+	}
+
+	openMap := func(name string) stream.InputReader {
+		t.Helper()
+
+		reader, err := stream.NewFileReader(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatalf("unable to open the %s map, %v", name, err)
+		}
+
+		return reader
+	}
+
+	unixMap, unixOutput := loadMap(openMap("unix.txt"))
+
+	assert.Equal(t, []string{"Bar", "Baz", "Foo", "Qu-ux"}, unixMap.Cities())
+
+	// The readers that don't drop the carriage returns
+	// (like the in-memory ones) are covered by the parsing
+	for _, reader := range []stream.InputReader{
+		openMap("windows.txt"),
+		stream.NewSliceReader([]string{
+			"Foo north=Bar west=Baz\r",
+			"Bar south=Foo east=Qu-ux \r",
+			"Baz east=Foo\t",
+			"Qu-ux west=Bar",
+		}),
+	} {
+		m, output := loadMap(reader)
+
+		assert.Equal(t, unixMap.Cities(), m.Cities())
+		assert.Equal(t, unixOutput, output)
+	}
+}
+
+// TestMap_Neighbors makes sure the roads of the city are returned
+// in the output order, and unknown cities are reported
+func TestMap_Neighbors(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"Foo west=Baz north=Bar",
+		"Bar south=Foo",
+		"Baz east=Foo",
+		"Qux",
+	}
+
+	m := NewEarthMap(hclog.NewNullLogger())
+
+	assert.NoError(t, m.InitMap(stream.NewSliceReader(lines)))
+
+	roads, err := m.Neighbors("Foo")
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]Road{
+			{Direction: "north", City: "Bar"},
+			{Direction: "west", City: "Baz"},
+		},
+		roads,
+	)
+
+	roads, err = m.Neighbors("Qux")
+
+	assert.NoError(t, err)
+	assert.Empty(t, roads)
+
+	_, err = m.Neighbors("Quux")
+
+	assert.ErrorIs(t, err, ErrCityNotFound)
+
+	// The declared order is kept, if preserved
+	m = NewEarthMap(hclog.NewNullLogger(), WithPreservedOrder())
+
+	assert.NoError(t, m.InitMap(stream.NewSliceReader(lines)))
+
+	roads, err = m.Neighbors("Foo")
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]Road{
+			{Direction: "west", City: "Baz"},
+			{Direction: "north", City: "Bar"},
+		},
+		roads,
+	)
+}
@@ -0,0 +1,41 @@
+package game
+
+import (
+	"sort"
+)
+
+// AdjacencyMatrix returns the adjacency matrix of the map, along with the city names
+// indexing its rows and columns. The names are sorted, so the matrix is the same
+// for any two logically identical maps. The entry at [i][j] is true if there is
+// a road from the i-th city to the j-th city. The map is left untouched
+func (m *EarthMap) AdjacencyMatrix() ([][]bool, []string) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	names := make([]string, 0, len(m.cityMap))
+	for name := range m.cityMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[name] = i
+	}
+
+	matrix := make([][]bool, len(names))
+
+	for i, name := range names {
+		matrix[i] = make([]bool, len(names))
+
+		for _, neighbor := range m.cityMap[name].neighbors {
+			// The roads to the cities no longer on the map are left out
+			if j, ok := index[neighbor.name]; ok {
+				matrix[i][j] = true
+			}
+		}
+	}
+
+	return matrix, names
+}
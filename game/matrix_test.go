@@ -0,0 +1,55 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestMap_AdjacencyMatrix makes sure the matrix entries
+// match the neighbor links, with the cities sorted by name
+func TestMap_AdjacencyMatrix(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{
+		"Foo north=Bar west=Baz",
+		"Bar south=Foo",
+		"Baz east=Foo",
+		"Qux",
+	})))
+
+	matrix, names := m.AdjacencyMatrix()
+
+	assert.Equal(t, []string{"Bar", "Baz", "Foo", "Qux"}, names)
+	assert.Equal(
+		t,
+		[][]bool{
+			{false, false, true, false},
+			{false, false, true, false},
+			{true, true, false, false},
+			{false, false, false, false},
+		},
+		matrix,
+	)
+
+	// The matrix is the same for repeated calls
+	repeatedMatrix, repeatedNames := m.AdjacencyMatrix()
+
+	assert.Equal(t, matrix, repeatedMatrix)
+	assert.Equal(t, names, repeatedNames)
+}
+
+// TestMap_AdjacencyMatrix_Empty makes sure
+// the empty map has an empty matrix
+func TestMap_AdjacencyMatrix_Empty(t *testing.T) {
+	t.Parallel()
+
+	matrix, names := NewEarthMap(hclog.NewNullLogger()).AdjacencyMatrix()
+
+	assert.Empty(t, matrix)
+	assert.Empty(t, names)
+}
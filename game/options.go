@@ -0,0 +1,231 @@
+package game
+
+// Defines the default limits of the earth map,
+// that protect against unbounded allocation
+const (
+	DefaultMaxCities = 10_000_000
+	DefaultMaxAliens = 10_000_000
+)
+
+// DefaultMaxMoves is the default max move count for each alien on the map
+const DefaultMaxMoves = 10000
+
+// SortMode defines the order in which cities are written to the output
+type SortMode int
+
+const (
+	// SortByName orders the cities alphabetically by name
+	SortByName SortMode = iota
+
+	// SortByDegreeDesc orders the cities from the most connected
+	// to the least connected, with ties ordered by name
+	SortByDegreeDesc
+)
+
+// config holds the earth map configuration
+type config struct {
+	maxCities int // the max number of cities on the map, 0 for unlimited
+	maxAliens int // the max number of aliens in a simulation, 0 for unlimited
+	maxMoves  int // the max number of moves each alien makes
+
+	maxTotalMoves int // the max number of moves across all aliens, 0 for unlimited
+
+	seed int64 // the seed of the random number generator
+
+	outputSort SortMode // the order in which cities are written to the output
+
+	debugChecks   bool // flag indicating if the map invariants are checked after the simulation
+	keepDestroyed bool // flag indicating if destroyed cities are kept on the map after the simulation
+
+	earlyTermination bool // flag indicating if the simulation stops once no further destruction is possible
+	strictParsing    bool // flag indicating if invalid map lines fail the map initialization
+	preserveOrder    bool // flag indicating if the neighbor declaration order is kept for the output
+	travelCosts      bool // flag indicating if the roads have travel costs that count against the max moves
+	perAlienRand     bool // flag indicating if each alien has its own random number generator
+
+	collision CollisionStrategy // the outcome of two aliens meeting in a city
+
+	alienBehavior movementBehavior // custom alien movement behavior, if any
+	strategy      StrategyFactory  // the factory of the alien movement strategies, if any
+	alienEnergy   func(int) int    // the energy budget of each alien, if any
+	recorder      *Recorder        // the recorder of the alien decisions, if any
+
+	destructionListener func(Destruction) // the listener notified of each city destruction, if any
+}
+
+// defaultConfig returns the earth map configuration used if no options are set
+func defaultConfig() config {
+	return config{
+		maxCities: DefaultMaxCities,
+		maxAliens: DefaultMaxAliens,
+		maxMoves:  DefaultMaxMoves,
+		seed:      generateSeed(),
+		collision: DefaultCollisionStrategy,
+	}
+}
+
+// Option defines a configuration option for the earth map
+type Option func(*EarthMap)
+
+// WithDebugChecks enables verifying the map invariants
+// at the end of each invasion simulation
+func WithDebugChecks() Option {
+	return func(m *EarthMap) {
+		m.config.debugChecks = true
+	}
+}
+
+// WithoutAutoPrune keeps the destroyed cities on the map
+// after the invasion simulation, instead of pruning them out
+func WithoutAutoPrune() Option {
+	return func(m *EarthMap) {
+		m.config.keepDestroyed = true
+	}
+}
+
+// WithEarlyTermination stops the invasion simulation as soon as
+// every remaining city is cut off from its neighbors, since the surviving
+// aliens are trapped and no further destruction is possible
+func WithEarlyTermination() Option {
+	return func(m *EarthMap) {
+		m.config.earlyTermination = true
+	}
+}
+
+// withAlienBehavior sets a custom movement behavior used by every alien
+// in place of the default random neighbor selection
+func withAlienBehavior(behavior movementBehavior) Option {
+	return func(m *EarthMap) {
+		m.config.alienBehavior = behavior
+	}
+}
+
+// WithStrategy sets the movement strategy of the aliens, created
+// for each alien using the factory. By default, the aliens move randomly
+func WithStrategy(factory StrategyFactory) Option {
+	return func(m *EarthMap) {
+		m.config.strategy = factory
+	}
+}
+
+// WithAlienEnergy gives each alien an energy budget, returned by the given function
+// for the alien ID. The energy decreases with each move, and the alien starves once it runs out,
+// regardless of the max moves. An energy of 0 or less leaves the alien's energy unlimited
+func WithAlienEnergy(energy func(alienID int) int) Option {
+	return func(m *EarthMap) {
+		m.config.alienEnergy = energy
+	}
+}
+
+// WithMaxCities sets the max number of cities the map can be initialized with.
+// A limit of 0 means the map size is unlimited
+func WithMaxCities(maxCities int) Option {
+	return func(m *EarthMap) {
+		m.config.maxCities = maxCities
+	}
+}
+
+// WithMaxAliens sets the max number of aliens an invasion simulation can use.
+// A limit of 0 means the alien count is unlimited
+func WithMaxAliens(maxAliens int) Option {
+	return func(m *EarthMap) {
+		m.config.maxAliens = maxAliens
+	}
+}
+
+// WithSeed sets the seed of the map's random number generator,
+// which drives the alien placement and movement
+func WithSeed(seed int64) Option {
+	return func(m *EarthMap) {
+		m.config.seed = seed
+		m.rng = newRand(seed)
+	}
+}
+
+// WithOutputSort sets the order in which the cities are written to the output.
+// By default, the cities are sorted by name
+func WithOutputSort(mode SortMode) Option {
+	return func(m *EarthMap) {
+		m.config.outputSort = mode
+	}
+}
+
+// WithMaxMoves sets the max number of moves each alien makes
+// before it stops wandering the map
+func WithMaxMoves(maxMoves int) Option {
+	return func(m *EarthMap) {
+		m.config.maxMoves = maxMoves
+	}
+}
+
+// WithMaxTotalMoves sets the max number of moves across all aliens of the
+// invasion simulation, after which the simulation stops regardless of the
+// per-alien max moves. A few moves already under way might still complete.
+// This bounds the simulation run time on pathological maps
+func WithMaxTotalMoves(maxTotalMoves int) Option {
+	return func(m *EarthMap) {
+		m.config.maxTotalMoves = maxTotalMoves
+	}
+}
+
+// WithStrictParsing fails the map initialization on the first invalid
+// input line, instead of skipping it. Empty lines are still ignored
+func WithStrictParsing() Option {
+	return func(m *EarthMap) {
+		m.config.strictParsing = true
+	}
+}
+
+// WithPreservedOrder keeps the order in which the neighbors were declared
+// in the input map, so the output lists them in the same order.
+// Implicitly added neighbors follow the declared ones
+func WithPreservedOrder() Option {
+	return func(m *EarthMap) {
+		m.config.preserveOrder = true
+	}
+}
+
+// WithRecorder records the alien placements and moves of the invasion
+// simulations, so they can be replayed later on
+func WithRecorder(recorder *Recorder) Option {
+	return func(m *EarthMap) {
+		m.config.recorder = recorder
+	}
+}
+
+// WithTravelCosts enables per-road travel costs, declared in the input
+// map as a suffix on the neighbor (ex. north=Bar:3). Roads without a cost
+// take a single move. The max moves become the travel cost budget of each alien
+func WithTravelCosts() Option {
+	return func(m *EarthMap) {
+		m.config.travelCosts = true
+	}
+}
+
+// WithCollisionStrategy sets the outcome of two aliens meeting in a city,
+// which is the destruction of the city along with both aliens by default.
+// The recorded invasions are replayed with the default collision strategy
+func WithCollisionStrategy(strategy CollisionStrategy) Option {
+	return func(m *EarthMap) {
+		m.config.collision = strategy
+	}
+}
+
+// WithDestructionListener sets the listener that is notified of each city
+// destruction as it happens. The listener is called from the alien routines
+// concurrently, so it needs to be thread safe
+func WithDestructionListener(listener func(Destruction)) Option {
+	return func(m *EarthMap) {
+		m.config.destructionListener = listener
+	}
+}
+
+// WithPerAlienRand gives each alien its own random number generator, seeded
+// from the map seed and the alien ID, instead of sharing the map generator.
+// The aliens no longer contend for the shared generator lock, and each alien's
+// movement choices no longer depend on how the alien routines are scheduled
+func WithPerAlienRand() Option {
+	return func(m *EarthMap) {
+		m.config.perAlienRand = true
+	}
+}
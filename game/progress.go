@@ -0,0 +1,44 @@
+package game
+
+import (
+	"sync/atomic"
+)
+
+// Progress is a snapshot of the running invasion simulation
+type Progress struct {
+	AliensRemaining int // the number of aliens still wandering the map
+	CitiesDestroyed int // the number of cities destroyed so far
+}
+
+// progressCounters are the counters of the running invasion simulation.
+// They are updated and read atomically, so the progress can be
+// polled while the simulation is running
+type progressCounters struct {
+	aliensRemaining int64
+	citiesDestroyed int64
+}
+
+// reset starts the counters for a new invasion simulation
+func (p *progressCounters) reset(aliensRemaining int) {
+	atomic.StoreInt64(&p.aliensRemaining, int64(aliensRemaining))
+	atomic.StoreInt64(&p.citiesDestroyed, 0)
+}
+
+// alienFinished marks a single alien as no longer wandering
+func (p *progressCounters) alienFinished() {
+	atomic.AddInt64(&p.aliensRemaining, -1)
+}
+
+// cityDestroyed marks a single city as destroyed
+func (p *progressCounters) cityDestroyed() {
+	atomic.AddInt64(&p.citiesDestroyed, 1)
+}
+
+// Progress returns the progress of the current (or last) invasion simulation.
+// It is safe to call while the simulation is running [Thread safe]
+func (m *EarthMap) Progress() Progress {
+	return Progress{
+		AliensRemaining: int(atomic.LoadInt64(&m.progress.aliensRemaining)),
+		CitiesDestroyed: int(atomic.LoadInt64(&m.progress.citiesDestroyed)),
+	}
+}
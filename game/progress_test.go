@@ -0,0 +1,67 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestMap_Progress makes sure the progress counters
+// track the running simulation, and can be read concurrently
+func TestMap_Progress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m *EarthMap
+
+		progressCh = make(chan Progress, 1)
+	)
+
+	// Capture the progress at the moment of the destruction
+	m = NewEarthMap(hclog.NewNullLogger(), WithDestructionListener(func(Destruction) {
+		progressCh <- m.Progress()
+	}))
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo"})))
+
+	// Poll the progress while the simulation is running
+	pollDone := make(chan struct{})
+	stopPolling := make(chan struct{})
+
+	go func() {
+		defer close(pollDone)
+
+		for {
+			select {
+			case <-stopPolling:
+				return
+			default:
+				_ = m.Progress()
+			}
+		}
+	}()
+
+	// Both aliens land in the only city, and destroy it
+	_, err := m.SimulateInvasion(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unable to simulate the invasion, %v", err)
+	}
+
+	close(stopPolling)
+	<-pollDone
+
+	// The destruction is counted before the listener is notified
+	assert.Equal(t, 1, (<-progressCh).CitiesDestroyed)
+
+	// Every alien is accounted for, once the simulation is over
+	assert.Equal(
+		t,
+		Progress{
+			AliensRemaining: 0,
+			CitiesDestroyed: 1,
+		},
+		m.Progress(),
+	)
+}
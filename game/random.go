@@ -0,0 +1,61 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedSource is a random number source that is
+// safe for concurrent use by multiple aliens
+type lockedSource struct {
+	mux sync.Mutex
+	src rand.Source64
+}
+
+func (ls *lockedSource) Int63() int64 {
+	ls.mux.Lock()
+	defer ls.mux.Unlock()
+
+	return ls.src.Int63()
+}
+
+func (ls *lockedSource) Uint64() uint64 {
+	ls.mux.Lock()
+	defer ls.mux.Unlock()
+
+	return ls.src.Uint64()
+}
+
+func (ls *lockedSource) Seed(seed int64) {
+	ls.mux.Lock()
+	defer ls.mux.Unlock()
+
+	ls.src.Seed(seed)
+}
+
+// newRand creates a new thread safe random number generator
+// using the given seed
+func newRand(seed int64) *rand.Rand {
+	//nolint:gosec
+	return rand.New(&lockedSource{
+		src: rand.NewSource(seed).(rand.Source64),
+	})
+}
+
+// newAlienRand creates the random number generator owned by a single alien,
+// seeded from the map seed and the alien ID. The generator is never
+// shared, so it doesn't need locking
+func newAlienRand(seed int64, id int) *rand.Rand {
+	// Spread the seeds of consecutive alien IDs with the golden ratio
+	// increment, so neighboring aliens don't get related sequences
+	alienSeed := int64(uint64(seed) + uint64(id+1)*0x9E3779B97F4A7C15)
+
+	//nolint:gosec
+	return rand.New(rand.NewSource(alienSeed))
+}
+
+// generateSeed generates a new random seed
+func generateSeed() int64 {
+	return time.Now().UnixNano()
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// drawDirections draws a sequence of random directions from the generator
+func drawDirections(draw func(int) int, count int) []int {
+	sequence := make([]int, count)
+	for i := range sequence {
+		sequence[i] = draw(numDirections)
+	}
+
+	return sequence
+}
+
+// TestNewAlienRand makes sure the per-alien generators are
+// reproducible, and differ between the aliens
+func TestNewAlienRand(t *testing.T) {
+	t.Parallel()
+
+	first := drawDirections(newAlienRand(42, 1).Intn, 32)
+
+	assert.Equal(t, first, drawDirections(newAlienRand(42, 1).Intn, 32))
+	assert.NotEqual(t, first, drawDirections(newAlienRand(42, 2).Intn, 32))
+	assert.NotEqual(t, first, drawDirections(newAlienRand(43, 1).Intn, 32))
+}
+
+// TestMap_AlienOptions_PerAlienRand makes sure the aliens share the map
+// generator by default, and own their generator with the per-alien option
+func TestMap_AlienOptions_PerAlienRand(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger(), WithSeed(42))
+
+	assert.Same(t, m.rng, newAlien(1, m.alienOptions(1)...).rng)
+
+	WithPerAlienRand()(m)
+
+	a := newAlien(1, m.alienOptions(1)...)
+
+	assert.NotSame(t, m.rng, a.rng)
+	assert.Equal(
+		t,
+		drawDirections(newAlienRand(42, 1).Intn, 32),
+		drawDirections(a.rng.Intn, 32),
+	)
+}
+
+// BenchmarkRand_Contention compares the random draws from the shared
+// generator with the draws from per-alien generators, across parallel routines
+func BenchmarkRand_Contention(b *testing.B) {
+	b.Run("shared", func(b *testing.B) {
+		rng := newRand(42)
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				rng.Intn(numDirections)
+			}
+		})
+	})
+
+	b.Run("per-alien", func(b *testing.B) {
+		var nextID int64
+
+		b.RunParallel(func(pb *testing.PB) {
+			rng := newAlienRand(42, int(atomic.AddInt64(&nextID, 1)))
+
+			for pb.Next() {
+				rng.Intn(numDirections)
+			}
+		})
+	})
+}
+
+// BenchmarkMap_SimulateInvasion_RandMode runs the invasion simulation with
+// 10k aliens on a 100x100 grid map, using the shared and per-alien generators
+func BenchmarkMap_SimulateInvasion_RandMode(b *testing.B) {
+	testTable := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			"shared",
+			nil,
+		},
+		{
+			"per-alien",
+			[]Option{WithPerAlienRand()},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		b.Run(testCase.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+
+				m := GenerateGridMap(100, 100)
+
+				for _, opt := range testCase.opts {
+					opt(m)
+				}
+
+				b.StartTimer()
+
+				_, _ = m.SimulateInvasion(context.Background(), 10_000)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// traverse walks the map breadth-first from the start city, treating destroyed
+// cities as blocked. The visit callback is invoked for each reached city,
+// and the traversal stops early if it returns false [NOT Thread safe]
+func (m *EarthMap) traverse(start *city, visit func(*city) bool) {
+	if start.isDestroyed() {
+		return
+	}
+
+	var (
+		visited = map[*city]struct{}{start: {}}
+		queue   = []*city{start}
+	)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if !visit(current) {
+			return
+		}
+
+		for _, neighbor := range current.neighbors {
+			if _, seen := visited[neighbor]; seen || neighbor.isDestroyed() {
+				continue
+			}
+
+			visited[neighbor] = struct{}{}
+			queue = append(queue, neighbor)
+		}
+	}
+}
+
+// CanReach checks if the destination city can be reached from the source city,
+// without passing through destroyed cities
+func (m *EarthMap) CanReach(from, to string) (bool, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	fromCity := m.getCity(from)
+	if fromCity == nil {
+		return false, fmt.Errorf("%w: %s", ErrCityNotFound, from)
+	}
+
+	toCity := m.getCity(to)
+	if toCity == nil {
+		return false, fmt.Errorf("%w: %s", ErrCityNotFound, to)
+	}
+
+	reachable := false
+
+	m.traverse(fromCity, func(c *city) bool {
+		reachable = c == toCity
+
+		// Stop as soon as the destination is reached
+		return !reachable
+	})
+
+	return reachable, nil
+}
+
+// ReachableFrom returns the sorted names of all cities reachable from the given city,
+// including the city itself, without passing through destroyed cities.
+// If the city is not present on the map, or is destroyed, nothing is reachable
+func (m *EarthMap) ReachableFrom(name string) []string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	reachable := make([]string, 0)
+
+	start := m.getCity(name)
+	if start == nil {
+		return reachable
+	}
+
+	m.traverse(start, func(c *city) bool {
+		reachable = append(reachable, c.name)
+
+		return true
+	})
+
+	sort.Strings(reachable)
+
+	return reachable
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestReachability_TwoComponents makes sure cities in separate
+// components of the map can't reach each other
+func TestReachability_TwoComponents(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+	m.InitMap(stream.NewSliceReader([]string{
+		"A east=B",
+		"B south=C",
+		"X north=Y",
+	}))
+
+	testTable := []struct {
+		name string
+		from string
+		to   string
+
+		expectedReachable bool
+	}{
+		{
+			"same city",
+			"A",
+			"A",
+			true,
+		},
+		{
+			"same component",
+			"A",
+			"C",
+			true,
+		},
+		{
+			"same component reversed",
+			"C",
+			"A",
+			true,
+		},
+		{
+			"different components",
+			"A",
+			"Y",
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			reachable, err := m.CanReach(testCase.from, testCase.to)
+			if err != nil {
+				t.Fatalf("unable to check reachability, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedReachable, reachable)
+		})
+	}
+
+	assert.Equal(t, []string{"A", "B", "C"}, m.ReachableFrom("B"))
+	assert.Equal(t, []string{"X", "Y"}, m.ReachableFrom("X"))
+}
+
+// TestReachability_DestroyedConnector makes sure destroyed
+// cities block the paths going through them
+func TestReachability_DestroyedConnector(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+	m.InitMap(stream.NewSliceReader([]string{
+		"A east=B",
+		"B east=C",
+	}))
+
+	// Destroy the only connecting city
+	m.getCity("B").destroyed = true
+
+	reachable, err := m.CanReach("A", "C")
+	if err != nil {
+		t.Fatalf("unable to check reachability, %v", err)
+	}
+
+	assert.False(t, reachable)
+
+	// Destroyed cities can't be reached, nor reach others
+	reachable, err = m.CanReach("A", "B")
+	if err != nil {
+		t.Fatalf("unable to check reachability, %v", err)
+	}
+
+	assert.False(t, reachable)
+
+	assert.Equal(t, []string{"A"}, m.ReachableFrom("A"))
+	assert.Empty(t, m.ReachableFrom("B"))
+}
+
+// TestReachability_UnknownCity makes sure unknown
+// city names are rejected
+func TestReachability_UnknownCity(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+	m.InitMap(stream.NewSliceReader([]string{
+		"A east=B",
+	}))
+
+	_, err := m.CanReach("A", "Z")
+	assert.ErrorIs(t, err, ErrCityNotFound)
+
+	_, err = m.CanReach("Z", "A")
+	assert.ErrorIs(t, err, ErrCityNotFound)
+
+	assert.Empty(t, m.ReachableFrom("Z"))
+}
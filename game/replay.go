@@ -0,0 +1,132 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrReplayMismatch = errors.New("replay does not match the map")
+
+// Decision is a single recorded alien decision,
+// to be placed in or to move to the city
+type Decision struct {
+	Alien int    `json:"alien"`
+	City  string `json:"city"`
+}
+
+// Recording contains the alien decisions of an invasion simulation,
+// in the order in which they happened
+type Recording struct {
+	Placements []Decision `json:"placements"`
+	Moves      []Decision `json:"moves"`
+}
+
+// Recorder records the alien decisions during the invasion simulation
+type Recorder struct {
+	mux       sync.Mutex // serializes the recorded moves
+	recording Recording  // the recorded decisions
+}
+
+// NewRecorder creates a new instance of the recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		recording: Recording{
+			Placements: make([]Decision, 0),
+			Moves:      make([]Decision, 0),
+		},
+	}
+}
+
+// recordPlacement records the alien placement on the map [Thread safe]
+func (r *Recorder) recordPlacement(alienID int, cityName string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.recording.Placements = append(r.recording.Placements, Decision{alienID, cityName})
+}
+
+// addMove records the alien move [NOT Thread safe]
+func (r *Recorder) addMove(alienID int, cityName string) {
+	r.recording.Moves = append(r.recording.Moves, Decision{alienID, cityName})
+}
+
+// Recording returns a copy of the recorded decisions [Thread safe]
+func (r *Recorder) Recording() *Recording {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return &Recording{
+		Placements: append(make([]Decision, 0, len(r.recording.Placements)), r.recording.Placements...),
+		Moves:      append(make([]Decision, 0, len(r.recording.Moves)), r.recording.Moves...),
+	}
+}
+
+// Replay runs the recorded invasion on the earth map sequentially, so the outcome
+// is identical to the recorded simulation. Once the replay completes, the destroyed
+// cities are handled the same way as after the invasion simulation.
+// Returns an error if a recorded decision is not possible on the map,
+// in which case the map is left in the partially replayed state
+func (m *EarthMap) Replay(recording *Recording) error {
+	positions := make(map[int]*city)
+
+	for index, placement := range recording.Placements {
+		placementCity := m.getCity(placement.City)
+		if placementCity == nil {
+			return fmt.Errorf("%w: placement %d, unknown city %s", ErrReplayMismatch, index, placement.City)
+		}
+
+		if _, placed := positions[placement.Alien]; placed {
+			return fmt.Errorf("%w: placement %d, alien %d is already placed", ErrReplayMismatch, index, placement.Alien)
+		}
+
+		if !placementCity.laySiege(placement.Alien) {
+			return fmt.Errorf(
+				"%w: placement %d, alien %d can't be placed in %s",
+				ErrReplayMismatch,
+				index,
+				placement.Alien,
+				placement.City,
+			)
+		}
+
+		placementCity.addInvader(placement.Alien)
+		positions[placement.Alien] = placementCity
+	}
+
+	for index, move := range recording.Moves {
+		current, placed := positions[move.Alien]
+		if !placed {
+			return fmt.Errorf("%w: move %d, alien %d was never placed", ErrReplayMismatch, index, move.Alien)
+		}
+
+		next := m.getCity(move.City)
+		if next == nil {
+			return fmt.Errorf("%w: move %d, unknown city %s", ErrReplayMismatch, index, move.City)
+		}
+
+		if !current.isNeighbor(next) || next.isDestroyed() || !next.laySiege(move.Alien) {
+			return fmt.Errorf(
+				"%w: move %d, alien %d can't move from %s to %s",
+				ErrReplayMismatch,
+				index,
+				move.Alien,
+				current.name,
+				move.City,
+			)
+		}
+
+		if !current.removeInvader(move.Alien) {
+			next.liftSiege(move.Alien)
+
+			return fmt.Errorf("%w: move %d, alien %d is dead", ErrReplayMismatch, index, move.Alien)
+		}
+
+		next.addInvader(move.Alien)
+		positions[move.Alien] = next
+	}
+
+	_ = m.concludeInvasion()
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestReplay_RecordedSimulation makes sure replaying a recorded
+// simulation produces the identical resulting map
+func TestReplay_RecordedSimulation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		recorder = NewRecorder()
+		original = GenerateGridMap(20, 20)
+		replayed = original.Clone()
+	)
+
+	WithRecorder(recorder)(original)
+
+	_, err := original.SimulateInvasion(context.Background(), 100)
+	assert.NoError(t, err)
+
+	recording := recorder.Recording()
+
+	assert.NotEmpty(t, recording.Placements)
+	assert.NotEmpty(t, recording.Moves)
+
+	assert.NoError(t, replayed.Replay(recording))
+
+	assert.Equal(t, original.DestroyedCount(), replayed.DestroyedCount())
+	assert.True(t, MapsEquivalent(original, replayed))
+}
+
+// TestReplay_Mismatch makes sure recordings that
+// don't match the map are rejected
+func TestReplay_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name      string
+		recording *Recording
+	}{
+		{
+			"unknown placement city",
+			&Recording{
+				Placements: []Decision{{0, "Zed"}},
+			},
+		},
+		{
+			"duplicate placement",
+			&Recording{
+				Placements: []Decision{{0, "Foo"}, {0, "Bar"}},
+			},
+		},
+		{
+			"move of unplaced alien",
+			&Recording{
+				Placements: []Decision{{0, "Foo"}},
+				Moves:      []Decision{{1, "Bar"}},
+			},
+		},
+		{
+			"move to non-neighbor",
+			&Recording{
+				Placements: []Decision{{0, "Foo"}},
+				Moves:      []Decision{{0, "Baz"}},
+			},
+		},
+		{
+			"move of dead alien",
+			&Recording{
+				Placements: []Decision{{0, "Foo"}, {1, "Foo"}},
+				Moves:      []Decision{{0, "Bar"}},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewEarthMap(hclog.NewNullLogger())
+			m.InitMap(stream.NewSliceReader([]string{
+				"Foo north=Bar",
+				"Bar north=Baz",
+			}))
+
+			assert.ErrorIs(t, m.Replay(testCase.recording), ErrReplayMismatch)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package game
+
+// SimulationResult contains the outcome of the invasion simulation
+type SimulationResult struct {
+	Aliens          int  `json:"aliens"`          // the number of aliens placed on the map
+	CitiesDestroyed int  `json:"citiesDestroyed"` // the number of cities destroyed during the simulation
+	Interrupted     bool `json:"interrupted"`     // flag indicating if the simulation was cut short
+	TotalCost       int  `json:"totalCost"`       // the total travel cost of the roads the aliens have taken
+	SurvivingAliens int  `json:"survivingAliens"` // the number of aliens left in the cities that were not destroyed
+
+	MoveBudgetExhausted bool `json:"moveBudgetExhausted"` // flag indicating if the simulation stopped on the total move budget
+	StarvedAliens       int  `json:"starvedAliens"`       // the number of aliens that ran out of energy
+}
+
+// Destruction describes a city destroyed during the invasion simulation
+type Destruction struct {
+	City   string `json:"city"`   // the name of the destroyed city
+	Aliens []int  `json:"aliens"` // the sorted IDs of the aliens that destroyed the city
+}
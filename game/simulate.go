@@ -0,0 +1,36 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// SimulateToString parses the map from the text, simulates the invasion with the given
+// number of aliens, and returns the map left after the invasion as text, along with the result.
+// It is the shortest way to run an invasion, for tests and quick experiments.
+// The simulation logs are discarded
+func SimulateToString(mapText string, numAliens int, opts ...Option) (string, SimulationResult, error) {
+	m := NewEarthMap(hclog.NewNullLogger(), opts...)
+
+	if err := m.InitMap(stream.NewReader(strings.NewReader(mapText))); err != nil {
+		return "", SimulationResult{}, fmt.Errorf("unable to initialize the map, %w", err)
+	}
+
+	result, err := m.SimulateInvasion(context.Background(), numAliens)
+	if err != nil {
+		return "", result, fmt.Errorf("unable to simulate the invasion, %w", err)
+	}
+
+	var output bytes.Buffer
+
+	if err := m.WriteOutput(stream.NewWriter(&output)); err != nil {
+		return "", result, fmt.Errorf("unable to write the map, %w", err)
+	}
+
+	return output.String(), result, nil
+}
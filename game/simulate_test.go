@@ -0,0 +1,75 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSimulateToString makes sure the map left after
+// the invasion is returned as text, along with the result
+func TestSimulateToString(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name      string
+		mapText   string
+		numAliens int
+
+		expectedOutput string
+		expectedResult SimulationResult
+	}{
+		{
+			"single alien",
+			"Foo north=Bar\nBar south=Foo",
+			1,
+			"Bar south=Foo\nFoo north=Bar\n",
+			SimulationResult{
+				Aliens:          1,
+				TotalCost:       10,
+				SurvivingAliens: 1,
+			},
+		},
+		{
+			"every city destroyed",
+			"Foo",
+			2,
+			"",
+			SimulationResult{
+				Aliens:          2,
+				CitiesDestroyed: 1,
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			output, result, err := SimulateToString(
+				testCase.mapText,
+				testCase.numAliens,
+				WithSeed(42),
+				WithMaxMoves(10),
+			)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOutput, output)
+			assert.Equal(t, testCase.expectedResult, result)
+		})
+	}
+}
+
+// TestSimulateToString_Invalid makes sure invalid maps and alien
+// counts are reported, with the options applied to the map parsing
+func TestSimulateToString_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := SimulateToString(" north=Bar", 1, WithStrictParsing())
+	assert.ErrorIs(t, err, ErrInvalidCityLine)
+
+	_, _, err = SimulateToString("Foo north=Bar", 0)
+	assert.ErrorIs(t, err, ErrInvalidAliens)
+}
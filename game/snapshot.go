@@ -0,0 +1,60 @@
+package game
+
+import (
+	"sort"
+)
+
+// MapSnapshot is a point-in-time view of the earth map
+type MapSnapshot struct {
+	Cities          int `json:"cities"`          // the number of cities on the map, including destroyed cities not yet pruned
+	DestroyedCities int `json:"destroyedCities"` // the number of cities destroyed so far, including the pruned ones
+	Invaders        int `json:"invaders"`        // the number of aliens invading the cities that still stand
+	Sieges          int `json:"sieges"`          // the number of sieges laid on the cities that still stand
+}
+
+// Snapshot returns a consistent point-in-time view of the earth map.
+// Every city is locked while the snapshot is taken, so no alien can move midway.
+// It is safe to call concurrently with a running simulation, including from
+// the destruction listener, which is notified once the city lock is released
+func (m *EarthMap) Snapshot() MapSnapshot {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Lock the cities in a stable order
+	cities := make([]*city, 0, len(m.cityMap))
+	for _, city := range m.cityMap {
+		cities = append(cities, city)
+	}
+
+	sort.Slice(cities, func(i, j int) bool {
+		return cities[i].name < cities[j].name
+	})
+
+	for _, city := range cities {
+		city.RLock()
+	}
+
+	defer func() {
+		for _, city := range cities {
+			city.RUnlock()
+		}
+	}()
+
+	snapshot := MapSnapshot{
+		Cities:          len(cities),
+		DestroyedCities: m.prunedCount,
+	}
+
+	for _, city := range cities {
+		if city.destroyed {
+			snapshot.DestroyedCities++
+
+			continue
+		}
+
+		snapshot.Invaders += city.numInvaders()
+		snapshot.Sieges += city.numSieges()
+	}
+
+	return snapshot
+}
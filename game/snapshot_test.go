@@ -0,0 +1,164 @@
+package game
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestMap_Snapshot makes sure the snapshot counts
+// the cities, destructions, invaders and sieges
+func TestMap_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{
+		"Foo north=Bar",
+		"Bar south=Foo east=Baz",
+		"Baz west=Bar",
+	})))
+
+	// Destroy Foo, and leave a single alien in Bar, which besieges Baz
+	for _, id := range []int{0, 1} {
+		assert.True(t, m.cityMap["Foo"].laySiege(id))
+		m.cityMap["Foo"].addInvader(id)
+	}
+
+	assert.True(t, m.cityMap["Bar"].laySiege(2))
+	m.cityMap["Bar"].addInvader(2)
+	assert.True(t, m.cityMap["Baz"].laySiege(2))
+
+	assert.Equal(
+		t,
+		MapSnapshot{
+			Cities:          3,
+			DestroyedCities: 1,
+			Invaders:        1,
+			Sieges:          2,
+		},
+		m.Snapshot(),
+	)
+
+	// Make sure the pruned cities are still counted as destroyed
+	m.pruneDestroyedCities()
+
+	assert.Equal(
+		t,
+		MapSnapshot{
+			Cities:          2,
+			DestroyedCities: 1,
+			Invaders:        1,
+			Sieges:          2,
+		},
+		m.Snapshot(),
+	)
+}
+
+// TestMap_Snapshot_Running makes sure snapshots can be
+// taken while the aliens are moving, and are always consistent
+func TestMap_Snapshot_Running(t *testing.T) {
+	t.Parallel()
+
+	const numAliens = 20
+
+	m := GenerateGridMap(5, 5)
+
+	var (
+		snapshots = make([]MapSnapshot, 0)
+
+		stopCh = make(chan struct{})
+		doneCh = make(chan struct{})
+	)
+
+	// Poll the snapshots while the simulation is running
+	go func() {
+		defer close(doneCh)
+
+		for {
+			select {
+			case <-stopCh:
+				return
+			default:
+				snapshots = append(snapshots, m.Snapshot())
+			}
+		}
+	}()
+
+	_, err := m.SimulateInvasion(context.Background(), numAliens)
+	if err != nil {
+		t.Fatalf("unable to simulate the invasion, %v", err)
+	}
+
+	close(stopCh)
+	<-doneCh
+
+	for _, snapshot := range snapshots {
+		// Every invader holds a siege on its city,
+		// and can besiege at most one more city when moving
+		assert.LessOrEqual(t, snapshot.Invaders, snapshot.Sieges)
+		assert.LessOrEqual(t, snapshot.Invaders, numAliens)
+		assert.LessOrEqual(t, snapshot.Sieges, 2*numAliens)
+
+		// The destroyed cities are only pruned once the simulation is over
+		assert.True(t, snapshot.Cities == 25 || snapshot.Cities+snapshot.DestroyedCities == 25)
+	}
+
+	// Make sure the final snapshot matches the outcome
+	final := m.Snapshot()
+
+	assert.Equal(t, m.DestroyedCount(), final.DestroyedCities)
+	assert.Equal(t, 25-final.DestroyedCities, final.Cities)
+}
+
+// TestMap_Snapshot_DestructionListener makes sure snapshots
+// can be taken from the destruction listener
+func TestMap_Snapshot_DestructionListener(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m *EarthMap
+
+		mux       sync.Mutex
+		snapshots = make([]MapSnapshot, 0)
+	)
+
+	m = NewEarthMap(hclog.NewNullLogger(), WithSeed(42), WithDestructionListener(func(Destruction) {
+		snapshot := m.Snapshot()
+
+		mux.Lock()
+		defer mux.Unlock()
+
+		snapshots = append(snapshots, snapshot)
+	}))
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo north=Bar", "Bar south=Foo"})))
+
+	doneCh := make(chan struct{})
+
+	go func() {
+		defer close(doneCh)
+
+		_, err := m.SimulateInvasion(context.Background(), 10)
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("simulation did not terminate")
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	// The listener sees each destroyed city as already destroyed
+	assert.NotEmpty(t, snapshots)
+
+	for _, snapshot := range snapshots {
+		assert.GreaterOrEqual(t, snapshot.DestroyedCities, 1)
+	}
+}
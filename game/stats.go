@@ -0,0 +1,91 @@
+package game
+
+import (
+	"sort"
+)
+
+// MapStats contains the summary metrics of the earth map
+type MapStats struct {
+	Cities          int      `json:"cities"`          // the number of cities on the map
+	Roads           int      `json:"roads"`           // the number of roads between the cities
+	DegreeHistogram []int    `json:"degreeHistogram"` // the number of cities per road count, indexed by the road count
+	Components      int      `json:"components"`      // the number of disconnected map regions
+	IsolatedCities  []string `json:"isolatedCities"`  // the sorted names of the cities without roads
+}
+
+// Stats calculates the summary metrics of the earth map
+func (m *EarthMap) Stats() *MapStats {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	stats := &MapStats{
+		Cities:          len(m.cityMap),
+		DegreeHistogram: make([]int, len(directions)+1),
+		IsolatedCities:  m.isolatedCities(),
+	}
+
+	names := make([]string, 0, len(m.cityMap))
+	for name := range m.cityMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	// Gather the road counts
+	roadEnds := 0
+
+	for _, name := range names {
+		numNeighbors := len(m.cityMap[name].neighbors)
+
+		stats.DegreeHistogram[numNeighbors]++
+		roadEnds += numNeighbors
+	}
+
+	// Every road is linked from both of its cities
+	stats.Roads = roadEnds / 2
+
+	// Count the disconnected map regions
+	visited := make(map[*city]struct{})
+
+	for _, name := range names {
+		start := m.cityMap[name]
+		if _, seen := visited[start]; seen || start.isDestroyed() {
+			continue
+		}
+
+		stats.Components++
+
+		m.traverse(start, func(c *city) bool {
+			visited[c] = struct{}{}
+
+			return true
+		})
+	}
+
+	return stats
+}
+
+// IsolatedCities returns the sorted names of the cities without roads.
+// The aliens placed in these cities can't move, so the cities can't be destroyed
+func (m *EarthMap) IsolatedCities() []string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	return m.isolatedCities()
+}
+
+// isolatedCities returns the sorted names of the cities without roads.
+// The map lock needs to be held
+func (m *EarthMap) isolatedCities() []string {
+	isolated := make([]string, 0)
+
+	for name, city := range m.cityMap {
+		if len(city.neighbors) == 0 {
+			isolated = append(isolated, name)
+		}
+	}
+
+	sort.Strings(isolated)
+
+	return isolated
+}
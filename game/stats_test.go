@@ -0,0 +1,35 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestStats_Metrics makes sure the map summary
+// metrics are properly calculated
+func TestStats_Metrics(t *testing.T) {
+	t.Parallel()
+
+	m := NewEarthMap(hclog.NewNullLogger())
+	m.InitMap(stream.NewSliceReader([]string{
+		"Foo north=Bar west=Baz south=Qu-ux",
+		"Bar west=Bee",
+		"Lone",
+		"X east=Y",
+	}))
+
+	assert.Equal(
+		t,
+		&MapStats{
+			Cities:          8,
+			Roads:           5,
+			DegreeHistogram: []int{1, 5, 1, 1, 0},
+			Components:      3,
+			IsolatedCities:  []string{"Lone"},
+		},
+		m.Stats(),
+	)
+}
@@ -0,0 +1,278 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// Stepper runs the invasion simulation one tick at a time, in the calling routine.
+// In each tick, every alien that is still wandering makes a single move,
+// in the order of the alien IDs, so the simulation is not subject to scheduling.
+// The stepper is not safe for concurrent use
+type Stepper struct {
+	m *EarthMap
+
+	aliens []*steppingAlien // the placed aliens, ordered by ID
+	tick   int              // the number of ticks taken so far
+
+	concluded bool             // flag indicating if the simulation was concluded
+	result    SimulationResult // the outcome of the concluded simulation
+}
+
+// steppingAlien is an alien placed on the map by the stepper
+type steppingAlien struct {
+	*alien
+
+	current   *city // the city the alien is in
+	moveCount int   // the number of moves the alien made
+	finished  bool  // flag indicating if the alien stopped wandering
+}
+
+// NewStepper places the given number of aliens on random cities of the map,
+// and returns the stepper that advances the invasion simulation.
+// Returns an error if the number of aliens is not positive,
+// or exceeds the configured max alien count
+func (m *EarthMap) NewStepper(numAliens int) (*Stepper, error) {
+	if numAliens <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidAliens, numAliens)
+	}
+
+	if m.config.maxAliens > 0 && numAliens > m.config.maxAliens {
+		return nil, fmt.Errorf("%w: %d aliens, at most %d allowed", ErrTooManyAliens, numAliens, m.config.maxAliens)
+	}
+
+	s := &Stepper{
+		m:      m,
+		aliens: make([]*steppingAlien, 0, numAliens),
+	}
+
+	// There are no cities on the earth map for aliens to invade
+	if len(m.cityMap) == 0 {
+		m.log.Error("There are no cities for the mad aliens to invade")
+
+		return s, nil
+	}
+
+	m.progress.reset(numAliens)
+
+	// Randomly assign starting positions for aliens
+	for id, randomCity := range m.getRandomCities(numAliens) {
+		// Aliens that can't be placed in their random
+		// city are not accounted for, like in the simulation
+		if invaded, _ := randomCity.tryInvade(id, m.rng); !invaded {
+			m.progress.alienFinished()
+
+			continue
+		}
+
+		if m.config.recorder != nil {
+			m.config.recorder.recordPlacement(id, randomCity.name)
+		}
+
+		s.aliens = append(s.aliens, &steppingAlien{
+			alien:   newAlien(id, m.alienOptions(id)...),
+			current: randomCity,
+		})
+	}
+
+	s.result.Aliens = len(s.aliens)
+
+	// Aliens placed together in a city destroyed it on landing
+	s.finishDefeated()
+
+	return s, nil
+}
+
+// Tick returns the number of ticks taken so far
+func (s *Stepper) Tick() int {
+	return s.tick
+}
+
+// Done checks if the simulation is over, because no alien is wandering anymore
+func (s *Stepper) Done() bool {
+	for _, a := range s.aliens {
+		if !a.finished {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Step advances the simulation by a single tick, in which every
+// wandering alien makes a single move.
+// Returns false if the simulation is over (or was concluded)
+func (s *Stepper) Step() bool {
+	if s.concluded || s.Done() {
+		return false
+	}
+
+	s.tick++
+
+	for _, a := range s.aliens {
+		if !a.finished {
+			s.moveAlien(a)
+		}
+	}
+
+	s.finishDefeated()
+
+	return !s.Done()
+}
+
+// finishDefeated stops the aliens that died with the city they're in,
+// including the aliens that stayed in a city destroyed by a later arrival,
+// and the aliens killed in a fight by a later arrival
+func (s *Stepper) finishDefeated() {
+	for _, a := range s.aliens {
+		if !a.finished && (a.current.isDestroyed() || a.current.isDefeated(a.id)) {
+			s.finishAlien(a)
+		}
+	}
+}
+
+// moveAlien makes a single move of the wandering alien,
+// mirroring the alien run loop of the simulation
+func (s *Stepper) moveAlien(a *steppingAlien) {
+	// The alien died with the city it's in, destroyed earlier in the tick,
+	// or was killed in a fight earlier in the tick
+	if a.current.isDestroyed() || a.current.isDefeated(a.id) {
+		s.finishAlien(a)
+
+		return
+	}
+
+	next := a.selectNextCity(a.current)
+	if next == nil {
+		// No neighbor can be sieged, the alien dies
+		s.finishAlien(a)
+
+		return
+	}
+
+	// The road over the rest of the travel budget is not taken, the alien stays
+	if !a.canAfford(a.current, next) {
+		next.liftSiege(a.id)
+		s.finishAlien(a)
+
+		return
+	}
+
+	if moved, _ := a.moveTo(a.current, next); !moved {
+		next.liftSiege(a.id)
+		s.finishAlien(a)
+
+		return
+	}
+
+	a.current = next
+	a.moveCount++
+
+	// Check if the alien has starved
+	if !a.useEnergy() {
+		s.finishAlien(a)
+
+		return
+	}
+
+	// Check if max moves (or the travel budget) have been reached.
+	// The alien that died destroying the city is stopped after the tick
+	if a.spent(a.moveCount) >= a.maxMoves {
+		s.finishAlien(a)
+	}
+}
+
+// finishAlien marks the alien as no longer wandering
+func (s *Stepper) finishAlien(a *steppingAlien) {
+	a.finished = true
+
+	s.m.progress.alienFinished()
+}
+
+// Run advances the simulation until it is over
+func (s *Stepper) Run() {
+	for s.Step() {
+	}
+}
+
+// Conclude ends the simulation at the current tick, and prunes out the destroyed cities
+// like at the end of the invasion simulation. The simulation is reported as
+// interrupted if any alien is still wandering.
+// Returns the outcome of the simulation, which is the same for repeated calls
+func (s *Stepper) Conclude() SimulationResult {
+	if s.concluded {
+		return s.result
+	}
+
+	s.concluded = true
+
+	for _, a := range s.aliens {
+		s.result.TotalCost += a.traveled
+
+		if a.starved {
+			s.result.StarvedAliens++
+		}
+	}
+
+	s.result.Interrupted = !s.Done()
+	s.result.SurvivingAliens = s.m.countSurvivingAliens()
+	s.result.CitiesDestroyed = s.m.concludeInvasion()
+
+	return s.result
+}
+
+// WriteMap writes the map of the cities that are still standing to the output stream,
+// without the roads leading to the destroyed cities
+func (s *Stepper) WriteMap(writer stream.OutputWriter) error {
+	standing := s.m.copyCities(func(c *city) bool {
+		return !c.destroyed
+	})
+
+	return standing.WriteOutput(writer)
+}
+
+// CityView is a read-only view of a single city on the map
+type CityView struct {
+	Name      string   `json:"name"`      // the name of the city
+	Roads     []string `json:"roads"`     // the roads to the neighbors (direction=name), in the output order
+	Invaders  []int    `json:"invaders"`  // the sorted IDs of the aliens in the city
+	Destroyed bool     `json:"destroyed"` // flag indicating if the city has been destroyed
+}
+
+// City returns the view of the city with the given name.
+// Returns an error if the city is not on the map
+func (m *EarthMap) City(name string) (CityView, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	c := m.getCity(name)
+	if c == nil {
+		return CityView{}, fmt.Errorf("%w: %s", ErrCityNotFound, name)
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+
+	view := CityView{
+		Name:      c.name,
+		Roads:     make([]string, 0, len(c.neighbors)),
+		Invaders:  make([]int, 0, len(c.invaders)),
+		Destroyed: c.destroyed,
+	}
+
+	for _, direction := range c.outputDirections() {
+		if neighbor, ok := c.neighbors[direction]; ok {
+			view.Roads = append(view.Roads, fmt.Sprintf("%s=%s", direction.getName(), neighbor.name))
+		}
+	}
+
+	for id := range c.invaders {
+		view.Invaders = append(view.Invaders, id)
+	}
+
+	sort.Ints(view.Invaders)
+
+	return view, nil
+}
@@ -0,0 +1,271 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// newStepperMap creates a map of the given city lines, with the given options
+func newStepperMap(t *testing.T, lines []string, opts ...Option) *EarthMap {
+	t.Helper()
+
+	m := NewEarthMap(hclog.NewNullLogger(), opts...)
+	assert.NoError(t, m.InitMap(stream.NewSliceReader(lines)))
+
+	return m
+}
+
+// TestStepper_Step makes sure a single alien moves once
+// per tick, until it reaches the max moves
+func TestStepper_Step(t *testing.T) {
+	t.Parallel()
+
+	m := newStepperMap(t, []string{"Foo north=Bar", "Bar south=Foo"}, WithMaxMoves(3))
+
+	s, err := m.NewStepper(1)
+	if err != nil {
+		t.Fatalf("unable to create the stepper, %v", err)
+	}
+
+	assert.Equal(t, 0, s.Tick())
+	assert.Equal(t, Progress{AliensRemaining: 1}, m.Progress())
+
+	// The alien makes its final move in the third tick
+	assert.True(t, s.Step())
+	assert.True(t, s.Step())
+	assert.False(t, s.Step())
+
+	assert.Equal(t, 3, s.Tick())
+	assert.True(t, s.Done())
+	assert.Equal(t, Progress{}, m.Progress())
+
+	// Make sure the finished simulation doesn't advance
+	assert.False(t, s.Step())
+	assert.Equal(t, 3, s.Tick())
+
+	assert.Equal(
+		t,
+		SimulationResult{
+			Aliens:          1,
+			SurvivingAliens: 1,
+			TotalCost:       3,
+		},
+		s.Conclude(),
+	)
+}
+
+// TestStepper_Starved makes sure the aliens that
+// run out of energy stop wandering, and are reported
+func TestStepper_Starved(t *testing.T) {
+	t.Parallel()
+
+	m := newStepperMap(
+		t,
+		[]string{"Foo north=Bar", "Bar south=Foo"},
+		WithMaxMoves(5),
+		WithAlienEnergy(func(int) int {
+			return 2
+		}),
+	)
+
+	s, err := m.NewStepper(1)
+	if err != nil {
+		t.Fatalf("unable to create the stepper, %v", err)
+	}
+
+	s.Run()
+
+	assert.Equal(t, 2, s.Tick())
+	assert.Equal(
+		t,
+		SimulationResult{
+			Aliens:          1,
+			SurvivingAliens: 1,
+			TotalCost:       2,
+			StarvedAliens:   1,
+		},
+		s.Conclude(),
+	)
+}
+
+// TestStepper_TravelCosts makes sure the aliens don't take
+// a road that costs more than the rest of their travel budget
+func TestStepper_TravelCosts(t *testing.T) {
+	t.Parallel()
+
+	m := newStepperMap(
+		t,
+		[]string{"Foo north=Bar:4", "Bar south=Foo:4"},
+		WithTravelCosts(),
+		WithMaxMoves(6),
+	)
+
+	s, err := m.NewStepper(1)
+	if err != nil {
+		t.Fatalf("unable to create the stepper, %v", err)
+	}
+
+	s.Run()
+
+	assert.Equal(
+		t,
+		SimulationResult{
+			Aliens:          1,
+			SurvivingAliens: 1,
+			TotalCost:       4,
+		},
+		s.Conclude(),
+	)
+}
+
+// TestStepper_Destroyed makes sure the aliens that destroyed
+// their city on landing are not wandering
+func TestStepper_Destroyed(t *testing.T) {
+	t.Parallel()
+
+	// Both aliens land in the only city, and destroy it
+	m := newStepperMap(t, []string{"Foo"})
+
+	s, err := m.NewStepper(2)
+	if err != nil {
+		t.Fatalf("unable to create the stepper, %v", err)
+	}
+
+	view, err := m.City("Foo")
+	assert.NoError(t, err)
+	assert.True(t, view.Destroyed)
+
+	assert.True(t, s.Done())
+	assert.False(t, s.Step())
+	assert.Equal(t, 0, s.Tick())
+	assert.Equal(
+		t,
+		SimulationResult{
+			Aliens:          2,
+			CitiesDestroyed: 1,
+		},
+		s.Conclude(),
+	)
+	assert.Empty(t, m.Cities())
+}
+
+// TestStepper_Destruction makes sure two aliens on a pair of cities
+// destroy one of the cities, on landing or in the first tick
+func TestStepper_Destruction(t *testing.T) {
+	t.Parallel()
+
+	m := newStepperMap(t, []string{"Foo north=Bar", "Bar south=Foo"})
+
+	s, err := m.NewStepper(2)
+	if err != nil {
+		t.Fatalf("unable to create the stepper, %v", err)
+	}
+
+	s.Run()
+
+	assert.LessOrEqual(t, s.Tick(), 1)
+	assert.Equal(
+		t,
+		SimulationResult{
+			Aliens:          2,
+			CitiesDestroyed: 1,
+			TotalCost:       s.Tick(),
+		},
+		s.Conclude(),
+	)
+	assert.Len(t, m.Cities(), 1)
+}
+
+// TestStepper_Conclude makes sure concluding a running
+// simulation reports it as interrupted, and stops it
+func TestStepper_Conclude(t *testing.T) {
+	t.Parallel()
+
+	m := newStepperMap(t, []string{"Foo north=Bar", "Bar south=Foo"})
+
+	s, err := m.NewStepper(1)
+	if err != nil {
+		t.Fatalf("unable to create the stepper, %v", err)
+	}
+
+	assert.True(t, s.Step())
+
+	result := s.Conclude()
+
+	assert.True(t, result.Interrupted)
+	assert.Equal(t, 1, result.SurvivingAliens)
+
+	// Make sure the concluded simulation stays concluded
+	assert.False(t, s.Step())
+	assert.Equal(t, 1, s.Tick())
+	assert.Equal(t, result, s.Conclude())
+}
+
+// TestStepper_InvalidAliens makes sure the alien
+// count is validated before the aliens are placed
+func TestStepper_InvalidAliens(t *testing.T) {
+	t.Parallel()
+
+	m := newStepperMap(t, []string{"Foo north=Bar"}, WithMaxAliens(2))
+
+	_, err := m.NewStepper(0)
+	assert.ErrorIs(t, err, ErrInvalidAliens)
+
+	_, err = m.NewStepper(3)
+	assert.ErrorIs(t, err, ErrTooManyAliens)
+}
+
+// TestStepper_WriteMap makes sure only the standing cities are written,
+// without the roads leading to the destroyed cities
+func TestStepper_WriteMap(t *testing.T) {
+	t.Parallel()
+
+	m := newStepperMap(t, []string{"Foo north=Bar", "Bar south=Foo east=Baz", "Baz west=Bar"})
+
+	// Destroy Foo
+	for _, id := range []int{0, 1} {
+		assert.True(t, m.cityMap["Foo"].laySiege(id))
+		m.cityMap["Foo"].addInvader(id)
+	}
+
+	writer := stream.NewSliceWriter()
+
+	assert.NoError(t, (&Stepper{m: m}).WriteMap(writer))
+	assert.Equal(t, []string{"Bar east=Baz\n", "Baz west=Bar\n"}, writer.Lines())
+
+	// Make sure the map itself is not pruned
+	assert.Len(t, m.cityMap, 3)
+}
+
+// TestMap_City makes sure the city view lists the
+// roads in the output order, and the sorted invaders
+func TestMap_City(t *testing.T) {
+	t.Parallel()
+
+	m := newStepperMap(t, []string{"Foo north=Bar west=Baz", "Bar south=Foo", "Baz east=Foo"})
+
+	for _, id := range []int{4, 2} {
+		assert.True(t, m.cityMap["Foo"].laySiege(id))
+		m.cityMap["Foo"].addInvader(id)
+	}
+
+	view, err := m.City("Foo")
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		CityView{
+			Name:      "Foo",
+			Roads:     []string{"north=Bar", "west=Baz"},
+			Invaders:  []int{2, 4},
+			Destroyed: true,
+		},
+		view,
+	)
+
+	_, err = m.City("Qux")
+	assert.ErrorIs(t, err, ErrCityNotFound)
+}
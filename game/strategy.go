@@ -0,0 +1,258 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Define the built-in movement strategy names
+const (
+	StrategyRandom      = "random"       // the alien moves to a random neighbor
+	StrategyAvoidRecent = "avoid-recent" // the alien avoids the cities it has recently been to
+	StrategyExplore     = "explore"      // the alien prefers the cities it has been to the least
+
+	// DefaultStrategy is the movement strategy used if none is chosen
+	DefaultStrategy = StrategyRandom
+)
+
+// DefaultRecentCityCount is the number of recently visited cities the avoid-recent
+// strategy keeps track of by default, including the city the alien is in
+const DefaultRecentCityCount = 3
+
+var (
+	ErrUnknownStrategy = errors.New("unknown movement strategy")
+)
+
+// Road is a road leading out of the city an alien is in
+type Road struct {
+	Direction string // the direction of the road (north, south, east or west)
+	City      string // the name of the city the road leads to
+}
+
+// MovementStrategy decides where an alien moves next.
+// A strategy instance is created for each alien, so it can
+// keep track of the alien's own history
+type MovementStrategy interface {
+	// Choose returns the index of the road the alien takes out of the current city.
+	// The roads lead to the cities that are accessible, in the canonical direction order,
+	// and there is at least one. The alien's random number generator is used for any randomness,
+	// so the seeded invasions are reproducible. An index out of range traps the alien.
+	// Choose is called again for the same move if the chosen city can't be sieged
+	Choose(current string, roads []Road, rng *rand.Rand) int
+}
+
+// StrategyFactory creates the movement strategy of the alien with the given ID
+type StrategyFactory func(alienID int) MovementStrategy
+
+var (
+	strategiesLock sync.RWMutex
+	strategies     = map[string]StrategyFactory{
+		StrategyRandom: func(int) MovementStrategy {
+			return randomStrategy{}
+		},
+		StrategyAvoidRecent: AvoidRecentStrategy(DefaultRecentCityCount),
+		StrategyExplore: func(int) MovementStrategy {
+			return &exploreStrategy{
+				visits: make(map[string]int),
+			}
+		},
+	}
+)
+
+// RegisterStrategy makes the movement strategy available under the given name,
+// so it can be chosen without changes to the program commands.
+// It panics if the name is empty or already registered, or the factory is nil
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategiesLock.Lock()
+	defer strategiesLock.Unlock()
+
+	if name == "" || factory == nil {
+		panic("game: invalid movement strategy registration")
+	}
+
+	if _, exists := strategies[name]; exists {
+		panic(fmt.Sprintf("game: movement strategy %s registered twice", name))
+	}
+
+	strategies[name] = factory
+}
+
+// LookupStrategy returns the factory of the movement strategy registered under the given name
+func LookupStrategy(name string) (StrategyFactory, error) {
+	strategiesLock.RLock()
+	defer strategiesLock.RUnlock()
+
+	factory, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (available: %s)", ErrUnknownStrategy, name, strings.Join(strategyNames(), ", "))
+	}
+
+	return factory, nil
+}
+
+// Strategies returns the sorted names of the registered movement strategies
+func Strategies() []string {
+	strategiesLock.RLock()
+	defer strategiesLock.RUnlock()
+
+	return strategyNames()
+}
+
+// strategyNames returns the sorted names of the registered
+// movement strategies. The registry lock needs to be held
+func strategyNames() []string {
+	names := make([]string, 0, len(strategies))
+
+	for name := range strategies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// strategyMovement adapts the movement strategy into the alien movement behavior
+func strategyMovement(strategy MovementStrategy) movementBehavior {
+	// The built-in random strategy keeps the original neighbor selection,
+	// so the seeded invasions are the same as without a chosen strategy
+	if _, ok := strategy.(randomStrategy); ok {
+		return randomMovement
+	}
+
+	return func(a *alien, current *city) *city {
+		for {
+			candidates := make([]*city, 0, numDirections)
+			roads := make([]Road, 0, numDirections)
+
+			for _, direction := range directions {
+				neighbor := current.neighbors[direction]
+				if neighbor == nil || !neighbor.isAccessible() {
+					continue
+				}
+
+				candidates = append(candidates, neighbor)
+				roads = append(roads, Road{
+					Direction: direction.getName(),
+					City:      neighbor.name,
+				})
+			}
+
+			if len(roads) == 0 {
+				// There are no neighbors the alien can move to,
+				// so the alien dies
+				return nil
+			}
+
+			index := strategy.Choose(current.name, roads, a.rng)
+			if index < 0 || index >= len(roads) {
+				return nil
+			}
+
+			// The siege can be taken by other aliens in the meantime,
+			// in which case the strategy chooses again
+			if candidates[index].laySiege(a.id) {
+				return candidates[index]
+			}
+		}
+	}
+}
+
+// randomStrategy is the movement strategy where the alien moves to a random neighbor
+type randomStrategy struct{}
+
+func (randomStrategy) Choose(_ string, roads []Road, rng *rand.Rand) int {
+	return rng.Intn(len(roads))
+}
+
+// AvoidRecentStrategy creates the factory of the avoid-recent movement strategy, where each alien
+// keeps track of the given number of recently visited cities (including the city it's in),
+// and moves to a neighbor it hasn't recently been to, if there is one. A longer memory cuts down
+// on the aliens moving back and forth between the same cities. The count needs to be positive,
+// and is DefaultRecentCityCount for the registered avoid-recent strategy
+func AvoidRecentStrategy(count int) StrategyFactory {
+	if count <= 0 {
+		panic(fmt.Sprintf("game: invalid recent city count %d", count))
+	}
+
+	return func(int) MovementStrategy {
+		return &avoidRecentStrategy{
+			count: count,
+		}
+	}
+}
+
+// avoidRecentStrategy is the movement strategy where the alien moves to a random neighbor
+// it hasn't recently been to, unless every neighbor has been visited recently
+type avoidRecentStrategy struct {
+	count  int      // the number of recently visited cities kept track of
+	recent []string // the recently visited cities, the oldest first
+}
+
+func (s *avoidRecentStrategy) Choose(current string, roads []Road, rng *rand.Rand) int {
+	// Repeated choices for the same move don't count as visits
+	if len(s.recent) == 0 || s.recent[len(s.recent)-1] != current {
+		s.recent = append(s.recent, current)
+	}
+
+	if len(s.recent) > s.count {
+		s.recent = s.recent[1:]
+	}
+
+	fresh := make([]int, 0, len(roads))
+
+	for index, road := range roads {
+		if !s.isRecent(road.City) {
+			fresh = append(fresh, index)
+		}
+	}
+
+	if len(fresh) == 0 {
+		return rng.Intn(len(roads))
+	}
+
+	return fresh[rng.Intn(len(fresh))]
+}
+
+// isRecent checks if the alien has recently been to the city
+func (s *avoidRecentStrategy) isRecent(city string) bool {
+	for _, recent := range s.recent {
+		if recent == city {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exploreStrategy is the movement strategy where the alien moves to
+// a random neighbor among the ones it has been to the least
+type exploreStrategy struct {
+	visits  map[string]int // the number of times the alien has been to each city
+	current string         // the city the alien is in
+}
+
+func (s *exploreStrategy) Choose(current string, roads []Road, rng *rand.Rand) int {
+	// Repeated choices for the same move don't count as visits
+	if current != s.current {
+		s.visits[current]++
+		s.current = current
+	}
+
+	least := make([]int, 0, len(roads))
+
+	for index, road := range roads {
+		switch {
+		case len(least) == 0 || s.visits[road.City] < s.visits[roads[least[0]].City]:
+			least = append(least[:0], index)
+		case s.visits[road.City] == s.visits[roads[least[0]].City]:
+			least = append(least, index)
+		}
+	}
+
+	return least[rng.Intn(len(least))]
+}
@@ -0,0 +1,275 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// firstRoadStrategy is a deterministic movement strategy
+// that always takes the first road
+type firstRoadStrategy struct{}
+
+func (firstRoadStrategy) Choose(_ string, _ []Road, _ *rand.Rand) int {
+	return 0
+}
+
+// TestLookupStrategy makes sure the built-in strategies are
+// registered, and unknown strategies are reported
+func TestLookupStrategy(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{StrategyRandom, StrategyAvoidRecent, StrategyExplore} {
+		factory, err := LookupStrategy(name)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, factory(0))
+	}
+
+	_, err := LookupStrategy("teleport")
+
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+	assert.ErrorContains(t, err, "avoid-recent, explore, random")
+}
+
+// TestRegisterStrategy makes sure registered strategies can be looked up,
+// and duplicate or invalid registrations are rejected
+func TestRegisterStrategy(t *testing.T) {
+	t.Parallel()
+
+	// The registry is global, so the name is unique for repeated test runs
+	name := fmt.Sprintf("test-register-%d", time.Now().UnixNano())
+
+	RegisterStrategy(name, func(int) MovementStrategy {
+		return firstRoadStrategy{}
+	})
+
+	factory, err := LookupStrategy(name)
+
+	assert.NoError(t, err)
+	assert.Equal(t, firstRoadStrategy{}, factory(0))
+	assert.Contains(t, Strategies(), name)
+
+	assert.Panics(t, func() {
+		RegisterStrategy(name, func(int) MovementStrategy {
+			return firstRoadStrategy{}
+		})
+	})
+
+	assert.Panics(t, func() {
+		RegisterStrategy("", func(int) MovementStrategy {
+			return firstRoadStrategy{}
+		})
+	})
+
+	assert.Panics(t, func() {
+		RegisterStrategy("test-nil", nil)
+	})
+}
+
+// TestMap_SimulateInvasion_Strategy makes sure the strategy
+// is created for each alien, and drives the alien movement
+func TestMap_SimulateInvasion_Strategy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux      sync.Mutex
+		alienIDs = make([]int, 0)
+	)
+
+	m := NewEarthMap(
+		hclog.NewNullLogger(),
+		WithMaxMoves(3),
+		WithStrategy(func(alienID int) MovementStrategy {
+			mux.Lock()
+			defer mux.Unlock()
+
+			alienIDs = append(alienIDs, alienID)
+
+			return firstRoadStrategy{}
+		}),
+	)
+
+	// Foo and Bar are only connected to each other, so the aliens can't
+	// run into each other, and the first road always leads to the other city
+	assert.NoError(t, m.InitMap(stream.NewSliceReader([]string{"Foo north=Bar", "Bar south=Foo", "Baz"})))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	_, err := m.SimulateInvasion(ctx, 2)
+	assert.NoError(t, err)
+
+	sort.Ints(alienIDs)
+	assert.Equal(t, []int{0, 1}, alienIDs)
+}
+
+// TestStrategyMovement_Random makes sure the built-in random strategy
+// keeps the default movement, so the seeded invasions are unchanged
+func TestStrategyMovement_Random(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(
+		t,
+		reflect.ValueOf(randomMovement).Pointer(),
+		reflect.ValueOf(strategyMovement(randomStrategy{})).Pointer(),
+	)
+}
+
+// TestStrategyMovement_InvalidChoice makes sure a choice
+// of a road that doesn't exist traps the alien
+func TestStrategyMovement_InvalidChoice(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cityA = newCity("A")
+		cityB = newCity("B")
+	)
+
+	cityA.addNeighbor(east, cityB)
+
+	behavior := strategyMovement(&chooseIndexStrategy{index: 1})
+
+	assert.Nil(t, behavior(newAlien(0), cityA))
+	assert.False(t, cityB.hasSiege(0))
+}
+
+// chooseIndexStrategy is a movement strategy that always chooses the same index
+type chooseIndexStrategy struct {
+	index int
+}
+
+func (s *chooseIndexStrategy) Choose(_ string, _ []Road, _ *rand.Rand) int {
+	return s.index
+}
+
+// TestAvoidRecentStrategy makes sure the recently
+// visited cities are avoided, if possible
+func TestAvoidRecentStrategy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		strategy = &avoidRecentStrategy{count: DefaultRecentCityCount}
+		rng      = newRand(42)
+		roads    = []Road{
+			{Direction: "north", City: "Bar"},
+			{Direction: "south", City: "Baz"},
+		}
+	)
+
+	// Bar is the city the alien has just been to
+	strategy.recent = []string{"Bar"}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, 1, strategy.Choose("Foo", roads, rng))
+	}
+
+	// Every neighbor is recent, so any of them can be chosen
+	strategy.recent = []string{"Bar", "Baz"}
+
+	index := strategy.Choose("Foo", roads, rng)
+	assert.True(t, index == 0 || index == 1)
+
+	// Repeated choices for the same move are not counted as visits
+	assert.Equal(t, []string{"Bar", "Baz", "Foo"}, strategy.recent)
+
+	// Only the most recent cities are kept
+	strategy.Choose("Qux", roads, rng)
+	assert.Equal(t, []string{"Baz", "Foo", "Qux"}, strategy.recent)
+}
+
+// TestAvoidRecentStrategy_Count makes sure the alien keeps
+// track of the given number of recently visited cities
+func TestAvoidRecentStrategy_Count(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rng   = newRand(42)
+		roads = []Road{
+			{Direction: "north", City: "Bar"},
+			{Direction: "south", City: "Baz"},
+		}
+	)
+
+	strategy, ok := AvoidRecentStrategy(5)(0).(*avoidRecentStrategy)
+	if !ok {
+		t.Fatalf("unexpected avoid-recent strategy type")
+	}
+
+	for _, city := range []string{"Bar", "Qux", "Quux", "Foo"} {
+		strategy.Choose(city, roads, rng)
+	}
+
+	// Bar is still remembered 3 moves later, unlike with the default count
+	assert.Equal(t, []string{"Bar", "Qux", "Quux", "Foo"}, strategy.recent)
+	assert.Equal(t, 1, strategy.Choose("Foo", roads, rng))
+
+	assert.Panics(t, func() {
+		AvoidRecentStrategy(0)
+	})
+}
+
+// TestStrategyMovement_AvoidRecent makes sure the alien moves on to the
+// neighbor it hasn't been to, instead of going back, when both are accessible
+func TestStrategyMovement_AvoidRecent(t *testing.T) {
+	t.Parallel()
+
+	for seed := int64(0); seed < 10; seed++ {
+		var (
+			cityA = newCity("A")
+			cityB = newCity("B")
+			cityC = newCity("C")
+		)
+
+		cityA.addNeighbor(east, cityB)
+		cityB.addNeighbor(west, cityA)
+		cityB.addNeighbor(east, cityC)
+		cityC.addNeighbor(west, cityB)
+
+		var (
+			a        = newAlien(0, withRand(newRand(seed)))
+			behavior = strategyMovement(AvoidRecentStrategy(2)(a.id))
+		)
+
+		assert.Equal(t, cityB, behavior(a, cityA))
+		assert.Equal(t, cityC, behavior(a, cityB))
+	}
+}
+
+// TestExploreStrategy makes sure the least
+// visited neighbors are preferred
+func TestExploreStrategy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		strategy = &exploreStrategy{
+			visits: map[string]int{
+				"Bar": 2,
+				"Baz": 1,
+				"Qux": 1,
+			},
+		}
+		rng   = newRand(42)
+		roads = []Road{
+			{Direction: "north", City: "Bar"},
+			{Direction: "south", City: "Baz"},
+			{Direction: "east", City: "Qux"},
+		}
+	)
+
+	for i := 0; i < 10; i++ {
+		assert.NotEqual(t, 0, strategy.Choose("Foo", roads, rng))
+	}
+
+	// Repeated choices for the same move are not counted as visits
+	assert.Equal(t, 1, strategy.visits["Foo"])
+}
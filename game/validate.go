@@ -0,0 +1,137 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity defines how serious a map validation issue is
+type Severity string
+
+const (
+	// SeverityWarning marks issues that don't prevent the simulation,
+	// but are likely unintended (ex. isolated cities)
+	SeverityWarning Severity = "warning"
+
+	// SeverityError marks issues that make the map structurally invalid
+	SeverityError Severity = "error"
+)
+
+// Issue is a single problem found during the map validation
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// ValidationReport contains the outcome of the map validation
+type ValidationReport struct {
+	Cities     int     `json:"cities"`     // the number of cities on the map
+	Roads      int     `json:"roads"`      // the number of roads between the cities
+	Components int     `json:"components"` // the number of disconnected map regions
+	Issues     []Issue `json:"issues"`     // the issues found on the map
+}
+
+// addIssue appends a new issue to the report
+func (r *ValidationReport) addIssue(severity Severity, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// HasErrors checks if any of the found issues is an error
+func (r *ValidationReport) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate runs the structural checks and connectivity analysis
+// of the earth map, and reports the found issues
+func (m *EarthMap) Validate() *ValidationReport {
+	report := &ValidationReport{
+		Issues: make([]Issue, 0),
+	}
+
+	// Structural violations are errors
+	if err := m.CheckInvariants(); err != nil {
+		report.addIssue(SeverityError, "%v", err)
+	}
+
+	stats := m.Stats()
+
+	report.Cities = stats.Cities
+	report.Roads = stats.Roads
+	report.Components = stats.Components
+
+	for _, name := range stats.IsolatedCities {
+		report.addIssue(SeverityWarning, "city %s has no roads", name)
+	}
+
+	if report.Components > 1 {
+		report.addIssue(
+			SeverityWarning,
+			"map is split into %d disconnected regions",
+			report.Components,
+		)
+	}
+
+	return report
+}
+
+// addStrictCityLine validates that the city input line is well formed,
+// before adding it to the earth map. Each token following the city name
+// needs to be a single valid direction=neighbor pair. Empty lines are ignored
+func (m *EarthMap) addStrictCityLine(cityLine string) error {
+	if strings.TrimSpace(cityLine) == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(cityLine, " ") {
+		return fmt.Errorf("%w: missing city name in %q", ErrInvalidCityLine, cityLine)
+	}
+
+	var (
+		tokens   = strings.Split(cityLine, " ")
+		cityName = tokens[0]
+		seen     = make(map[direction]struct{})
+	)
+
+	for _, token := range tokens[1:] {
+		if token == "" {
+			// Repeated separators are allowed
+			continue
+		}
+
+		directionName, neighbor, found := strings.Cut(token, "=")
+
+		direction, ok := directionFromName(directionName)
+		if !found || !ok || neighbor == "" {
+			return fmt.Errorf("%w: unknown token %q", ErrInvalidCityLine, token)
+		}
+
+		if m.config.travelCosts {
+			var err error
+
+			if neighbor, _, err = splitTravelCost(neighbor); err != nil {
+				return err
+			}
+		}
+
+		if _, duplicate := seen[direction]; duplicate {
+			return fmt.Errorf("%w: duplicate direction %s", ErrInvalidCityLine, directionName)
+		}
+
+		if neighbor == cityName {
+			return fmt.Errorf("%w: city %s has a road to itself", ErrInvalidCityLine, cityName)
+		}
+
+		seen[direction] = struct{}{}
+	}
+
+	return m.addCityLine(cityLine)
+}
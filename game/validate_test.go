@@ -0,0 +1,133 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// TestValidate_Report makes sure the validation report
+// contains the map stats and connectivity warnings
+func TestValidate_Report(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid map", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewEarthMap(hclog.NewNullLogger())
+		m.InitMap(stream.NewSliceReader([]string{
+			"Foo north=Bar west=Baz",
+			"Bar west=Bee",
+		}))
+
+		report := m.Validate()
+
+		assert.Equal(t, 4, report.Cities)
+		assert.Equal(t, 3, report.Roads)
+		assert.Equal(t, 1, report.Components)
+		assert.Empty(t, report.Issues)
+		assert.False(t, report.HasErrors())
+	})
+
+	t.Run("disconnected map", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewEarthMap(hclog.NewNullLogger())
+		m.InitMap(stream.NewSliceReader([]string{
+			"Foo north=Bar",
+			"Lone",
+		}))
+
+		report := m.Validate()
+
+		assert.Equal(t, 2, report.Components)
+		assert.Equal(
+			t,
+			[]Issue{
+				{SeverityWarning, "city Lone has no roads"},
+				{SeverityWarning, "map is split into 2 disconnected regions"},
+			},
+			report.Issues,
+		)
+		assert.False(t, report.HasErrors())
+	})
+
+	t.Run("broken map", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewEarthMap(hclog.NewNullLogger())
+		m.InitMap(stream.NewSliceReader([]string{
+			"Foo north=Bar",
+		}))
+
+		// Break the road symmetry
+		m.getCity("Bar").removeNeighbor(south)
+
+		assert.True(t, m.Validate().HasErrors())
+	})
+}
+
+// TestValidate_StrictParsing makes sure malformed lines
+// fail the map initialization in strict mode
+func TestValidate_StrictParsing(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		lines []string
+
+		expectedErr error
+	}{
+		{
+			"valid lines",
+			[]string{"Foo north=Bar  west=Baz", "", "Bar"},
+			nil,
+		},
+		{
+			"missing city name",
+			[]string{" north=Bar"},
+			ErrInvalidCityLine,
+		},
+		{
+			"unknown direction",
+			[]string{"Foo up=Bar"},
+			ErrInvalidCityLine,
+		},
+		{
+			"trailing junk",
+			[]string{"Foo north=Bar junk"},
+			ErrInvalidCityLine,
+		},
+		{
+			"duplicate direction",
+			[]string{"Foo north=Bar north=Baz"},
+			ErrInvalidCityLine,
+		},
+		{
+			"road to itself",
+			[]string{"Foo north=Foo"},
+			ErrInvalidCityLine,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewEarthMap(hclog.NewNullLogger(), WithStrictParsing())
+
+			err := m.InitMap(stream.NewSliceReader(testCase.lines))
+			if testCase.expectedErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package scenario
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidScenario   = errors.New("invalid scenario")
+	ErrExpectationNotMet = errors.New("scenario expectation not met")
+)
+
+// Scenario bundles everything about an invasion experiment:
+// the map, the simulation parameters and the expected outcome
+type Scenario struct {
+	Name         string       `yaml:"name"`           // the name of the scenario
+	Description  string       `yaml:"description"`    // the description of the scenario, if any
+	Map          Map          `yaml:"map"`            // the map of the scenario
+	Aliens       int          `yaml:"aliens"`         // the number of aliens
+	Seed         *int64       `yaml:"seed"`           // the seed of the random number generator, if set
+	MaxMoves     int          `yaml:"max-moves"`      // the max number of moves of each alien, if set
+	Strategy     string       `yaml:"strategy"`       // the movement strategy of the aliens, if set
+	Collision    string       `yaml:"collision"`      // the collision strategy of the aliens, if set
+	TravelCosts  bool         `yaml:"travel-costs"`   // flag indicating if the roads have travel costs
+	PerAlienRand bool         `yaml:"per-alien-rand"` // flag indicating if each alien has its own random number generator
+	Expect       Expectations `yaml:"expect"`         // the expected outcome of the invasion, if any
+
+	Hash string `yaml:"-"` // the SHA-256 hash of the scenario file, along with the map file it references
+
+	dir string // the directory of the scenario file, which the map path is relative to
+}
+
+// Map is the map of the scenario, either inline or by reference
+type Map struct {
+	Path   string        `yaml:"path"`   // the path to the map file, relative to the scenario file
+	Inline string        `yaml:"inline"` // the map itself
+	Format stream.Format `yaml:"format"` // the format of the map, detected from the path extension if omitted
+}
+
+// Expectations is the expected outcome of the invasion
+type Expectations struct {
+	CitiesDestroyed *int     `yaml:"cities-destroyed"` // the number of destroyed cities, if set
+	SurvivingCities []string `yaml:"surviving-cities"` // the names of the surviving cities, if set
+}
+
+// Load reads and validates the scenario file
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the scenario file, %w", err)
+	}
+
+	// Unknown fields are rejected, so typos don't go unnoticed
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	s := &Scenario{}
+
+	if err := decoder.Decode(s); err != nil {
+		return nil, fmt.Errorf("unable to decode the scenario file, %w", err)
+	}
+
+	s.dir = filepath.Dir(path)
+
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hash(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Hash = hash
+
+	return s, nil
+}
+
+// hash returns the SHA-256 hash of the scenario file contents, followed by
+// the contents of the map file, if the map is referenced by path.
+// The map is part of the experiment, so a changed map changes the hash as well
+func (s *Scenario) hash(data []byte) (string, error) {
+	hasher := sha256.New()
+	hasher.Write(data)
+
+	if s.Map.Path != "" {
+		mapData, err := os.ReadFile(s.MapPath())
+		if err != nil {
+			return "", fmt.Errorf("unable to read the scenario map file, %w", err)
+		}
+
+		hasher.Write(mapData)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// validate makes sure the scenario fields are valid,
+// naming the offending field otherwise
+func (s *Scenario) validate() error {
+	invalidField := func(field, reason string) error {
+		return fmt.Errorf("%w: %s, %s", ErrInvalidScenario, field, reason)
+	}
+
+	if strings.TrimSpace(s.Name) == "" {
+		return invalidField("name", "expected a name")
+	}
+
+	switch {
+	case s.Map.Path == "" && s.Map.Inline == "":
+		return invalidField("map", "expected either the path or the inline map")
+	case s.Map.Path != "" && s.Map.Inline != "":
+		return invalidField("map", "expected either the path or the inline map, not both")
+	}
+
+	if s.Map.Format != "" && !isInputFormat(s.Map.Format) {
+		return invalidField("map.format", fmt.Sprintf("%s is not an input format", s.Map.Format))
+	}
+
+	if s.Aliens <= 0 {
+		return invalidField("aliens", fmt.Sprintf("%d, expected a positive number", s.Aliens))
+	}
+
+	if s.MaxMoves < 0 {
+		return invalidField("max-moves", fmt.Sprintf("%d, expected a non-negative number", s.MaxMoves))
+	}
+
+	if s.Strategy != "" {
+		if _, err := game.LookupStrategy(s.Strategy); err != nil {
+			return invalidField("strategy", err.Error())
+		}
+	}
+
+	if s.Collision != "" {
+		if _, err := game.ParseCollisionStrategy(s.Collision); err != nil {
+			return invalidField("collision", err.Error())
+		}
+	}
+
+	if s.Expect.CitiesDestroyed != nil && *s.Expect.CitiesDestroyed < 0 {
+		return invalidField(
+			"expect.cities-destroyed",
+			fmt.Sprintf("%d, expected a non-negative number", *s.Expect.CitiesDestroyed),
+		)
+	}
+
+	return nil
+}
+
+// isInputFormat checks if the format can be read
+func isInputFormat(format stream.Format) bool {
+	for _, inputFormat := range stream.InputFormats() {
+		if format == inputFormat {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MapPath returns the path to the map file, resolved against
+// the scenario file directory, or an empty path for inline maps
+func (s *Scenario) MapPath() string {
+	if s.Map.Path == "" || filepath.IsAbs(s.Map.Path) {
+		return s.Map.Path
+	}
+
+	return filepath.Join(s.dir, s.Map.Path)
+}
+
+// MapFormat returns the format of the map, which is detected from
+// the map path extension if omitted. Inline maps are in the text format by default
+func (s *Scenario) MapFormat() stream.Format {
+	switch {
+	case s.Map.Format != "":
+		return s.Map.Format
+	case s.Map.Path != "":
+		return stream.DetectInputFormat(s.Map.Path)
+	default:
+		return stream.FormatText
+	}
+}
+
+// MapReader returns the reader of the scenario map
+func (s *Scenario) MapReader() (stream.InputReader, error) {
+	if s.Map.Inline != "" {
+		return stream.NewFormatReader(strings.NewReader(s.Map.Inline), s.MapFormat())
+	}
+
+	mapFile, err := stream.OpenMapFile(s.MapPath())
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the scenario map file, %w", err)
+	}
+
+	reader, err := stream.NewFormatReader(mapFile, s.MapFormat())
+	if err != nil {
+		_ = mapFile.Close()
+
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// Options returns the earth map options of the scenario parameters
+func (s *Scenario) Options() ([]game.Option, error) {
+	opts := make([]game.Option, 0)
+
+	if s.Seed != nil {
+		opts = append(opts, game.WithSeed(*s.Seed))
+	}
+
+	if s.MaxMoves > 0 {
+		opts = append(opts, game.WithMaxMoves(s.MaxMoves))
+	}
+
+	if s.Strategy != "" {
+		factory, err := game.LookupStrategy(s.Strategy)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, game.WithStrategy(factory))
+	}
+
+	if s.Collision != "" {
+		collision, err := game.ParseCollisionStrategy(s.Collision)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, game.WithCollisionStrategy(collision))
+	}
+
+	if s.TravelCosts {
+		opts = append(opts, game.WithTravelCosts())
+	}
+
+	if s.PerAlienRand {
+		opts = append(opts, game.WithPerAlienRand())
+	}
+
+	return opts, nil
+}
+
+// NewEarthMap creates the earth map of the scenario, initialized with the scenario map.
+// The given options are applied after the scenario options
+func (s *Scenario) NewEarthMap(log hclog.Logger, opts ...game.Option) (*game.EarthMap, error) {
+	scenarioOpts, err := s.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := s.MapReader()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	earthMap := game.NewEarthMap(log, append(scenarioOpts, opts...)...)
+
+	if err := earthMap.InitMap(reader); err != nil {
+		return nil, fmt.Errorf("unable to initialize the scenario map, %w", err)
+	}
+
+	return earthMap, nil
+}
+
+// Verify checks the invasion outcome against the scenario expectations.
+// The surviving cities are the cities left on the map after the invasion
+func (s *Scenario) Verify(result game.SimulationResult, survivingCities []string) error {
+	if expected := s.Expect.CitiesDestroyed; expected != nil && *expected != result.CitiesDestroyed {
+		return fmt.Errorf(
+			"%w: %d cities destroyed, expected %d",
+			ErrExpectationNotMet,
+			result.CitiesDestroyed,
+			*expected,
+		)
+	}
+
+	if s.Expect.SurvivingCities == nil {
+		return nil
+	}
+
+	expected := append([]string(nil), s.Expect.SurvivingCities...)
+	actual := append([]string(nil), survivingCities...)
+
+	sort.Strings(expected)
+	sort.Strings(actual)
+
+	if strings.Join(expected, ",") != strings.Join(actual, ",") {
+		return fmt.Errorf(
+			"%w: surviving cities %s, expected %s",
+			ErrExpectationNotMet,
+			strings.Join(actual, ", "),
+			strings.Join(expected, ", "),
+		)
+	}
+
+	return nil
+}
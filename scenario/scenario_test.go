@@ -0,0 +1,240 @@
+package scenario
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// writeTempScenario writes the scenario file contents to a temporary file
+func writeTempScenario(t *testing.T, contents string) string {
+	t.Helper()
+
+	scenarioPath := filepath.Join(t.TempDir(), "scenario.yaml")
+
+	if err := os.WriteFile(scenarioPath, []byte(contents), 0o600); err != nil {
+		t.Fatalf("unable to write scenario file, %v", err)
+	}
+
+	return scenarioPath
+}
+
+// TestLoad makes sure the scenario parameters
+// are resolved from the scenario file
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	s, err := Load(filepath.Join("testdata", "siege.yaml"))
+	if err != nil {
+		t.Fatalf("unable to load the scenario, %v", err)
+	}
+
+	assert.Equal(t, "siege-of-foo", s.Name)
+	assert.Equal(t, 2, s.Aliens)
+	assert.Equal(t, int64(42), *s.Seed)
+	assert.Equal(t, 100, s.MaxMoves)
+	assert.Equal(t, game.StrategyExplore, s.Strategy)
+	assert.Equal(t, filepath.Join("testdata", "siege.txt"), s.MapPath())
+	assert.Equal(t, stream.FormatText, s.MapFormat())
+	assert.Equal(t, 1, *s.Expect.CitiesDestroyed)
+	assert.Equal(t, []string{}, s.Expect.SurvivingCities)
+	assert.Len(t, s.Hash, 64)
+}
+
+// TestLoad_Hash makes sure the scenario hash covers
+// both the scenario file and the map file it references
+func TestLoad_Hash(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir          = t.TempDir()
+		scenarioPath = filepath.Join(dir, "scenario.yaml")
+		mapPath      = filepath.Join(dir, "map.txt")
+	)
+
+	writeFile := func(path, contents string) {
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatalf("unable to write file, %v", err)
+		}
+	}
+
+	load := func() string {
+		s, err := Load(scenarioPath)
+		if err != nil {
+			t.Fatalf("unable to load the scenario, %v", err)
+		}
+
+		return s.Hash
+	}
+
+	writeFile(scenarioPath, "name: siege\naliens: 2\nmap:\n  path: map.txt\n")
+	writeFile(mapPath, "Foo north=Bar\n")
+
+	original := load()
+
+	// The same files hash the same
+	assert.Equal(t, original, load())
+
+	// A changed map changes the hash, even though the scenario file is the same
+	writeFile(mapPath, "Foo north=Bee\n")
+	assert.NotEqual(t, original, load())
+
+	// A missing map file is reported
+	assert.NoError(t, os.Remove(mapPath))
+
+	_, err := Load(scenarioPath)
+	assert.ErrorContains(t, err, "unable to read the scenario map file")
+}
+
+// TestScenario_Simulate makes sure the scenario map is
+// simulated, with the expected outcome
+func TestScenario_Simulate(t *testing.T) {
+	t.Parallel()
+
+	s, err := Load(filepath.Join("testdata", "siege.yaml"))
+	if err != nil {
+		t.Fatalf("unable to load the scenario, %v", err)
+	}
+
+	earthMap, err := s.NewEarthMap(hclog.NewNullLogger())
+	if err != nil {
+		t.Fatalf("unable to create the scenario map, %v", err)
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	result, err := earthMap.SimulateInvasion(ctx, s.Aliens)
+	if err != nil {
+		t.Fatalf("unable to simulate the invasion, %v", err)
+	}
+
+	assert.Equal(t, 1, result.CitiesDestroyed)
+	assert.NoError(t, s.Verify(result, earthMap.Cities()))
+}
+
+// TestScenario_InlineMap makes sure inline maps are read in the given format
+func TestScenario_InlineMap(t *testing.T) {
+	t.Parallel()
+
+	s, err := Load(writeTempScenario(
+		t,
+		"name: inline\naliens: 1\nmap:\n  format: csv\n  inline: |\n    city,north,south,east,west\n    Foo,Bar,,,\n",
+	))
+	if err != nil {
+		t.Fatalf("unable to load the scenario, %v", err)
+	}
+
+	earthMap, err := s.NewEarthMap(hclog.NewNullLogger())
+	if err != nil {
+		t.Fatalf("unable to create the scenario map, %v", err)
+	}
+
+	assert.Equal(t, []string{"Bar", "Foo"}, earthMap.Cities())
+}
+
+// TestLoad_Invalid makes sure invalid scenario
+// files are rejected, naming the offending field
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name          string
+		contents      string
+		expectedCause string
+	}{
+		{
+			"missing name",
+			"aliens: 2\nmap:\n  inline: Foo\n",
+			"name, expected a name",
+		},
+		{
+			"missing map",
+			"name: siege\naliens: 2\n",
+			"map, expected either the path or the inline map",
+		},
+		{
+			"both maps",
+			"name: siege\naliens: 2\nmap:\n  path: map.txt\n  inline: Foo\n",
+			"map, expected either the path or the inline map, not both",
+		},
+		{
+			"invalid map format",
+			"name: siege\naliens: 2\nmap:\n  inline: Foo\n  format: mermaid\n",
+			"map.format, mermaid is not an input format",
+		},
+		{
+			"invalid aliens",
+			"name: siege\naliens: 0\nmap:\n  inline: Foo\n",
+			"aliens, 0, expected a positive number",
+		},
+		{
+			"unknown strategy",
+			"name: siege\naliens: 2\nstrategy: teleport\nmap:\n  inline: Foo\n",
+			"strategy, unknown movement strategy",
+		},
+		{
+			"unknown collision strategy",
+			"name: siege\naliens: 2\ncollision: truce\nmap:\n  inline: Foo\n",
+			"collision, unknown collision strategy",
+		},
+		{
+			"invalid expectation",
+			"name: siege\naliens: 2\nmap:\n  inline: Foo\nexpect:\n  cities-destroyed: -1\n",
+			"expect.cities-destroyed, -1",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(writeTempScenario(t, testCase.contents))
+
+			assert.ErrorIs(t, err, ErrInvalidScenario)
+			assert.ErrorContains(t, err, testCase.expectedCause)
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Load(writeTempScenario(t, "name: siege\naliens: 2\nwaves: 3\nmap:\n  inline: Foo\n"))
+
+		assert.ErrorContains(t, err, "field waves not found")
+	})
+}
+
+// TestScenario_Verify makes sure outcomes that don't
+// match the expectations are reported
+func TestScenario_Verify(t *testing.T) {
+	t.Parallel()
+
+	citiesDestroyed := 1
+
+	s := &Scenario{
+		Expect: Expectations{
+			CitiesDestroyed: &citiesDestroyed,
+			SurvivingCities: []string{"Foo", "Bar"},
+		},
+	}
+
+	assert.NoError(t, s.Verify(game.SimulationResult{CitiesDestroyed: 1}, []string{"Bar", "Foo"}))
+
+	err := s.Verify(game.SimulationResult{CitiesDestroyed: 2}, []string{"Bar", "Foo"})
+	assert.ErrorIs(t, err, ErrExpectationNotMet)
+	assert.ErrorContains(t, err, "2 cities destroyed, expected 1")
+
+	err = s.Verify(game.SimulationResult{CitiesDestroyed: 1}, []string{"Foo"})
+	assert.ErrorIs(t, err, ErrExpectationNotMet)
+	assert.ErrorContains(t, err, "surviving cities Foo, expected Bar, Foo")
+}
@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/zivkovicmilos/alien-invasion/game"
+)
+
+// Define the simulation statuses
+const (
+	statusRunning   = "running"
+	statusCompleted = "completed"
+	statusCancelled = "cancelled"
+	statusFailed    = "failed"
+)
+
+var (
+	errSimulationNotFound = errors.New("simulation not found")
+	errInvalidAlienNumber = errors.New("invalid number of aliens provided")
+)
+
+// simulationRequest is the body of the simulation start request
+type simulationRequest struct {
+	Aliens int    `json:"aliens"`
+	Seed   *int64 `json:"seed,omitempty"`
+}
+
+// simulationStatus is the status of a single simulation
+type simulationStatus struct {
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	Aliens          int    `json:"aliens"`
+	Seed            int64  `json:"seed"`
+	CitiesDestroyed int    `json:"citiesDestroyed"`
+	Error           string `json:"error,omitempty"`
+}
+
+// simulation is a single invasion simulation run by the server
+type simulation struct {
+	sync.RWMutex
+
+	id       string
+	aliens   int
+	earthMap *game.EarthMap // the independent copy of the map the simulation runs on
+
+	cancelFn context.CancelFunc
+	doneCh   chan struct{}
+
+	status string                // the current simulation status
+	result game.SimulationResult // the simulation outcome, once it's done
+	err    error                 // the simulation error, if any
+}
+
+// getStatus returns the current status of the simulation
+func (s *simulation) getStatus() simulationStatus {
+	s.RLock()
+	defer s.RUnlock()
+
+	status := simulationStatus{
+		ID:              s.id,
+		Status:          s.status,
+		Aliens:          s.aliens,
+		Seed:            s.earthMap.Seed(),
+		CitiesDestroyed: s.result.CitiesDestroyed,
+	}
+
+	// The progress of a running simulation is read from the map
+	if s.status == statusRunning {
+		status.CitiesDestroyed = s.earthMap.DestroyedCount()
+	}
+
+	if s.err != nil {
+		status.Error = s.err.Error()
+	}
+
+	return status
+}
+
+// Server exposes the invasion simulations over a JSON HTTP API.
+// Every simulation runs on an independent copy of the loaded map
+type Server struct {
+	log      hclog.Logger
+	earthMap *game.EarthMap
+
+	mux         sync.RWMutex
+	simulations map[string]*simulation
+	nextID      int
+
+	wg sync.WaitGroup
+}
+
+// NewServer creates a new instance of the simulation server,
+// for the given initialized map
+func NewServer(log hclog.Logger, earthMap *game.EarthMap) *Server {
+	return &Server{
+		log:         log.Named("server"),
+		earthMap:    earthMap,
+		simulations: make(map[string]*simulation),
+	}
+}
+
+// Handler returns the HTTP handler of the simulation API:
+//   - POST /simulations starts a new simulation
+//   - GET /simulations/{id} returns the simulation status
+//   - GET /simulations/{id}/map returns the simulation map
+//   - DELETE /simulations/{id} cancels the simulation
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/simulations", s.handleSimulations)
+	mux.HandleFunc("/simulations/", s.handleSimulation)
+
+	return mux
+}
+
+// Close cancels all running simulations, and waits for them to finish
+func (s *Server) Close() {
+	s.mux.RLock()
+
+	for _, sim := range s.simulations {
+		sim.cancelFn()
+	}
+
+	s.mux.RUnlock()
+
+	s.wg.Wait()
+}
+
+// handleSimulations handles the simulation collection requests
+func (s *Server) handleSimulations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+
+		return
+	}
+
+	var request simulationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unable to decode the request, %w", err))
+
+		return
+	}
+
+	if request.Aliens <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %d", errInvalidAlienNumber, request.Aliens))
+
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.startSimulation(request).getStatus())
+}
+
+// handleSimulation handles the single simulation requests
+func (s *Server) handleSimulation(w http.ResponseWriter, r *http.Request) {
+	var (
+		path     = strings.TrimPrefix(r.URL.Path, "/simulations/")
+		id, rest = path, ""
+	)
+
+	if index := strings.Index(path, "/"); index >= 0 {
+		id, rest = path[:index], path[index+1:]
+	}
+
+	sim := s.getSimulation(id)
+	if sim == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", errSimulationNotFound, id))
+
+		return
+	}
+
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, sim.getStatus())
+	case rest == "" && r.Method == http.MethodDelete:
+		sim.cancelFn()
+		<-sim.doneCh
+
+		writeJSON(w, http.StatusOK, sim.getStatus())
+	case rest == "map" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, sim.earthMap)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("route %s %s not found", r.Method, r.URL.Path))
+	}
+}
+
+// startSimulation starts a new simulation in the background
+func (s *Server) startSimulation(request simulationRequest) *simulation {
+	earthMap := s.earthMap.Clone()
+
+	// The clone is seeded with the seed of the loaded map, so a simulation
+	// without an explicit seed gets a fresh one, instead of a rerun
+	seed := time.Now().UnixNano()
+	if request.Seed != nil {
+		seed = *request.Seed
+	}
+
+	game.WithSeed(seed)(earthMap)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+
+	s.mux.Lock()
+
+	s.nextID++
+
+	sim := &simulation{
+		id:       strconv.Itoa(s.nextID),
+		aliens:   request.Aliens,
+		earthMap: earthMap,
+		cancelFn: cancelFn,
+		doneCh:   make(chan struct{}),
+		status:   statusRunning,
+	}
+
+	s.simulations[sim.id] = sim
+
+	s.mux.Unlock()
+
+	s.log.Info(fmt.Sprintf("Starting simulation %s with %d aliens", sim.id, sim.aliens))
+
+	s.wg.Add(1)
+
+	go func() {
+		defer func() {
+			cancelFn()
+			close(sim.doneCh)
+			s.wg.Done()
+		}()
+
+		result, err := earthMap.SimulateInvasion(ctx, request.Aliens)
+
+		sim.Lock()
+		defer sim.Unlock()
+
+		sim.result = result
+		sim.err = err
+
+		switch {
+		case err != nil:
+			sim.status = statusFailed
+		case result.Interrupted:
+			sim.status = statusCancelled
+		default:
+			sim.status = statusCompleted
+		}
+	}()
+
+	return sim
+}
+
+// getSimulation fetches the simulation with the given ID, if any
+func (s *Server) getSimulation(id string) *simulation {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return s.simulations[id]
+}
+
+// writeJSON writes out the JSON response with the given status code
+func writeJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// writeError writes out the JSON error response with the given status code
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	writeJSON(w, statusCode, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zivkovicmilos/alien-invasion/game"
+	"github.com/zivkovicmilos/alien-invasion/stream"
+)
+
+// newTestServer creates a simulation server for the given map lines
+func newTestServer(t *testing.T, lines []string, opts ...game.Option) *httptest.Server {
+	t.Helper()
+
+	earthMap := game.NewEarthMap(hclog.NewNullLogger(), opts...)
+
+	reader := stream.NewScannerReader(strings.NewReader(strings.Join(lines, "\n")))
+	if err := earthMap.InitMap(reader); err != nil {
+		t.Fatalf("unable to initialize the map, %v", err)
+	}
+
+	simulationServer := NewServer(hclog.NewNullLogger(), earthMap)
+	httpServer := httptest.NewServer(simulationServer.Handler())
+
+	t.Cleanup(func() {
+		httpServer.Close()
+		simulationServer.Close()
+	})
+
+	return httpServer
+}
+
+// doRequest executes the request, and decodes the JSON response
+func doRequest(t *testing.T, method, url, body string, response interface{}) int {
+	t.Helper()
+
+	request, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("unable to create the request, %v", err)
+	}
+
+	httpResponse, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("unable to execute the request, %v", err)
+	}
+
+	defer httpResponse.Body.Close()
+
+	if response != nil {
+		if err := json.NewDecoder(httpResponse.Body).Decode(response); err != nil {
+			t.Fatalf("unable to decode the response, %v", err)
+		}
+	}
+
+	return httpResponse.StatusCode
+}
+
+// waitForStatus polls the simulation until it is no longer running
+func waitForStatus(t *testing.T, url string) simulationStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		var status simulationStatus
+
+		doRequest(t, http.MethodGet, url, "", &status)
+
+		if status.Status != statusRunning {
+			return status
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("simulation did not finish in time")
+
+	return simulationStatus{}
+}
+
+// TestServer_Simulation makes sure a simulation can be started,
+// inspected, and its map fetched
+func TestServer_Simulation(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestServer(t, []string{"Foo north=Bar", "Bar east=Baz"})
+
+	// Start the simulation
+	var started simulationStatus
+
+	statusCode := doRequest(
+		t,
+		http.MethodPost,
+		httpServer.URL+"/simulations",
+		`{"aliens": 2, "seed": 42}`,
+		&started,
+	)
+
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, "1", started.ID)
+	assert.Equal(t, 2, started.Aliens)
+	assert.Equal(t, int64(42), started.Seed)
+
+	// Wait for the simulation to complete
+	status := waitForStatus(t, httpServer.URL+"/simulations/1")
+
+	assert.Equal(t, statusCompleted, status.Status)
+
+	// Fetch the final map
+	earthMap := game.NewEarthMap(hclog.NewNullLogger())
+
+	statusCode = doRequest(t, http.MethodGet, httpServer.URL+"/simulations/1/map", "", earthMap)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, earthMap.Cities(), 3-status.CitiesDestroyed)
+}
+
+// TestServer_GeneratedSeed makes sure the simulations
+// without a seed are each given a fresh one
+func TestServer_GeneratedSeed(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestServer(t, []string{"Foo north=Bar"}, game.WithSeed(42))
+
+	seeds := make([]int64, 0, 2)
+
+	for i := 0; i < 2; i++ {
+		var started simulationStatus
+
+		statusCode := doRequest(t, http.MethodPost, httpServer.URL+"/simulations", `{"aliens": 1}`, &started)
+		assert.Equal(t, http.StatusCreated, statusCode)
+
+		seeds = append(seeds, started.Seed)
+	}
+
+	assert.NotEqual(t, int64(42), seeds[0])
+	assert.NotEqual(t, seeds[0], seeds[1])
+}
+
+// TestServer_Cancel makes sure running simulations can be cancelled
+func TestServer_Cancel(t *testing.T) {
+	t.Parallel()
+
+	// The alien never stops moving on its own
+	httpServer := newTestServer(t, []string{"Foo north=Bar"}, game.WithMaxMoves(1<<62))
+
+	doRequest(t, http.MethodPost, httpServer.URL+"/simulations", `{"aliens": 1}`, nil)
+
+	var status simulationStatus
+
+	statusCode := doRequest(t, http.MethodDelete, httpServer.URL+"/simulations/1", "", &status)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, statusCancelled, status.Status)
+}
+
+// TestServer_InvalidRequests makes sure invalid
+// requests are properly rejected
+func TestServer_InvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestServer(t, []string{"Foo north=Bar"})
+
+	testTable := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+
+		expectedStatusCode int
+	}{
+		{
+			"invalid alien number",
+			http.MethodPost,
+			"/simulations",
+			`{"aliens": 0}`,
+			http.StatusBadRequest,
+		},
+		{
+			"malformed body",
+			http.MethodPost,
+			"/simulations",
+			`{"aliens":`,
+			http.StatusBadRequest,
+		},
+		{
+			"unsupported method",
+			http.MethodGet,
+			"/simulations",
+			"",
+			http.StatusMethodNotAllowed,
+		},
+		{
+			"unknown simulation",
+			http.MethodGet,
+			"/simulations/42",
+			"",
+			http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var response map[string]string
+
+			statusCode := doRequest(t, testCase.method, httpServer.URL+testCase.path, testCase.body, &response)
+
+			assert.Equal(t, testCase.expectedStatusCode, statusCode)
+			assert.NotEmpty(t, response["error"])
+		})
+	}
+}
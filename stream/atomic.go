@@ -0,0 +1,131 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+var errIncompleteOutput = errors.New("output was not written completely")
+
+// maxTempFileAttempts is the number of temporary file names
+// tried, before giving up on creating the temporary file
+const maxTempFileAttempts = 10000
+
+// AtomicFileWriter implements the map writer interface for writing the map
+// to an output file with all-or-nothing semantics. The output is written
+// to a temporary file, which is renamed into place on Close, only if
+// every write succeeded. Otherwise, the temporary file is discarded
+type AtomicFileWriter struct {
+	filePath       string
+	tempFile       *os.File
+	bufferedWriter *bufio.Writer
+
+	failed bool // flag indicating if any write to the temporary file failed
+}
+
+// NewAtomicFileWriter creates a new instance of the atomic file writer.
+// The temporary file is created in the same directory as the output file,
+// so the rename stays on the same file system. The output file ends up with
+// the permissions of the file it replaces, or 0666 (before the umask) if it's new,
+// like with the file writer
+func NewAtomicFileWriter(filePath string) (OutputWriter, error) {
+	tempFile, err := createTempFile(filepath.Dir(filePath), fmt.Sprintf(".%s", filepath.Base(filePath)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary file, %w", err)
+	}
+
+	// Keep the permissions of the replaced output file
+	if info, statErr := os.Stat(filePath); statErr == nil {
+		if err := tempFile.Chmod(info.Mode().Perm()); err != nil {
+			_ = tempFile.Close()
+			_ = os.Remove(tempFile.Name())
+
+			return nil, fmt.Errorf("unable to set temporary file permissions, %w", err)
+		}
+	}
+
+	return &AtomicFileWriter{
+		filePath:       filePath,
+		tempFile:       tempFile,
+		bufferedWriter: bufio.NewWriter(tempFile),
+	}, nil
+}
+
+func (aw *AtomicFileWriter) Write(s string) error {
+	if _, err := aw.bufferedWriter.WriteString(s); err != nil {
+		aw.failed = true
+
+		return err
+	}
+
+	return nil
+}
+
+func (aw *AtomicFileWriter) Flush() error {
+	if err := aw.bufferedWriter.Flush(); err != nil {
+		aw.failed = true
+
+		return err
+	}
+
+	return nil
+}
+
+// Close finalizes the temporary file, and renames it into place.
+// If any of the writes failed, the temporary file is removed instead
+func (aw *AtomicFileWriter) Close() error {
+	// Make sure the temporary file is gone,
+	// in case it can't be renamed into place
+	defer func() {
+		_ = os.Remove(aw.tempFile.Name())
+	}()
+
+	if err := aw.Flush(); err != nil {
+		_ = aw.tempFile.Close()
+
+		return fmt.Errorf("unable to flush temporary file, %w", err)
+	}
+
+	if err := aw.tempFile.Sync(); err != nil {
+		_ = aw.tempFile.Close()
+
+		return fmt.Errorf("unable to sync temporary file, %w", err)
+	}
+
+	if err := aw.tempFile.Close(); err != nil {
+		return fmt.Errorf("unable to close temporary file, %w", err)
+	}
+
+	if aw.failed {
+		return errIncompleteOutput
+	}
+
+	if err := os.Rename(aw.tempFile.Name(), aw.filePath); err != nil {
+		return fmt.Errorf("unable to rename temporary file, %w", err)
+	}
+
+	return nil
+}
+
+// createTempFile creates a new temporary file in the directory, with the given name prefix.
+// Unlike os.CreateTemp, which restricts the file to 0600, the file is created
+// with 0666 permissions, so the umask applies to it like to any other new file
+func createTempFile(dir, prefix string) (*os.File, error) {
+	for attempt := 0; attempt < maxTempFileAttempts; attempt++ {
+		//nolint:gosec
+		name := filepath.Join(dir, fmt.Sprintf("%s.%d.tmp", prefix, rand.Uint32()))
+
+		tempFile, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+
+		return tempFile, err
+	}
+
+	return nil, fmt.Errorf("%w: no free temporary file name in %s", os.ErrExist, dir)
+}
@@ -0,0 +1,162 @@
+package stream
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAtomicFileWriter_Close makes sure the output file
+// only appears once the writer is closed
+func TestAtomicFileWriter_Close(t *testing.T) {
+	t.Parallel()
+
+	var (
+		outputDir  = t.TempDir()
+		outputPath = filepath.Join(outputDir, "output.txt")
+	)
+
+	writer, err := NewAtomicFileWriter(outputPath)
+	if err != nil {
+		t.Fatalf("unable to create atomic file writer, %v", err)
+	}
+
+	assert.NoError(t, writer.Write("Foo north=Bar\n"))
+	assert.NoError(t, writer.Write("Bar south=Foo\n"))
+	assert.NoError(t, writer.Flush())
+
+	// Make sure the output file doesn't exist before closing
+	assert.NoFileExists(t, outputPath)
+
+	assert.NoError(t, writer.Close())
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	assert.Equal(t, "Foo north=Bar\nBar south=Foo\n", string(output))
+
+	// Make sure the temporary file is gone
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("unable to read output directory, %v", err)
+	}
+
+	assert.Len(t, entries, 1)
+}
+
+// TestAtomicFileWriter_Failed makes sure the output file
+// is not created if a write failed
+func TestAtomicFileWriter_Failed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		outputDir  = t.TempDir()
+		outputPath = filepath.Join(outputDir, "output.txt")
+	)
+
+	// Create an existing output, which should be left untouched
+	if err := os.WriteFile(outputPath, []byte("previous\n"), 0o600); err != nil {
+		t.Fatalf("unable to write output file, %v", err)
+	}
+
+	writer, err := NewAtomicFileWriter(outputPath)
+	if err != nil {
+		t.Fatalf("unable to create atomic file writer, %v", err)
+	}
+
+	assert.NoError(t, writer.Write("Foo north=Bar\n"))
+
+	// Simulate a failed write
+	writer.(*AtomicFileWriter).failed = true
+
+	assert.ErrorIs(t, writer.Close(), errIncompleteOutput)
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	assert.Equal(t, "previous\n", string(output))
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("unable to read output directory, %v", err)
+	}
+
+	assert.Len(t, entries, 1)
+}
+
+// TestAtomicFileWriter_Mode makes sure the output file gets the same
+// permissions as with the file writer, instead of the temporary file ones
+func TestAtomicFileWriter_Mode(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on Windows")
+	}
+
+	// writeAtomic writes the output file using the atomic file writer
+	writeAtomic := func(t *testing.T, outputPath string) os.FileMode {
+		t.Helper()
+
+		writer, err := NewAtomicFileWriter(outputPath)
+		if err != nil {
+			t.Fatalf("unable to create atomic file writer, %v", err)
+		}
+
+		assert.NoError(t, writer.Write("Foo north=Bar\n"))
+		assert.NoError(t, writer.Close())
+
+		info, err := os.Stat(outputPath)
+		if err != nil {
+			t.Fatalf("unable to stat output file, %v", err)
+		}
+
+		return info.Mode().Perm()
+	}
+
+	t.Run("new file", func(t *testing.T) {
+		t.Parallel()
+
+		outputDir := t.TempDir()
+
+		// The file writer creates the file with 0666, before the umask
+		referencePath := filepath.Join(outputDir, "reference.txt")
+
+		writer, err := NewFileWriter(referencePath)
+		if err != nil {
+			t.Fatalf("unable to create file writer, %v", err)
+		}
+
+		assert.NoError(t, writer.Close())
+
+		info, err := os.Stat(referencePath)
+		if err != nil {
+			t.Fatalf("unable to stat reference file, %v", err)
+		}
+
+		assert.Equal(t, info.Mode().Perm(), writeAtomic(t, filepath.Join(outputDir, "output.txt")))
+	})
+
+	t.Run("existing file", func(t *testing.T) {
+		t.Parallel()
+
+		outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+		if err := os.WriteFile(outputPath, []byte("previous\n"), 0o600); err != nil {
+			t.Fatalf("unable to write output file, %v", err)
+		}
+
+		// Set the permissions explicitly, regardless of the umask
+		if err := os.Chmod(outputPath, 0o640); err != nil {
+			t.Fatalf("unable to set output file permissions, %v", err)
+		}
+
+		assert.Equal(t, os.FileMode(0o640), writeAtomic(t, outputPath))
+	})
+}
@@ -0,0 +1,38 @@
+package stream
+
+import (
+	"bufio"
+	"io"
+)
+
+// utf8BOM is the byte order mark that some editors (and spreadsheet exports)
+// put at the start of the UTF-8 files. It would otherwise end up in the first city name
+const utf8BOM = "\uFEFF"
+
+// bomReader reads the source without the leading UTF-8 byte order mark, if any.
+// The source is only checked on the first read, so creating the reader doesn't block
+type bomReader struct {
+	reader  *bufio.Reader
+	checked bool
+}
+
+// skipBOM wraps the source, so the leading UTF-8 byte order mark is skipped
+func skipBOM(source io.Reader) io.Reader {
+	return &bomReader{
+		reader: bufio.NewReader(source),
+	}
+}
+
+func (br *bomReader) Read(p []byte) (int, error) {
+	if !br.checked {
+		br.checked = true
+
+		// A short source can't start with the byte order mark,
+		// so the peek error is left to the read
+		if bom, err := br.reader.Peek(len(utf8BOM)); err == nil && string(bom) == utf8BOM {
+			_, _ = br.reader.Discard(len(utf8BOM))
+		}
+	}
+
+	return br.reader.Read(p)
+}
@@ -0,0 +1,96 @@
+package stream
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSkipBOM makes sure only the leading byte order mark is skipped,
+// and the short sources are read as they are
+func TestSkipBOM(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			"leading byte order mark",
+			utf8BOM + "Foo north=Bar",
+			"Foo north=Bar",
+		},
+		{
+			"byte order mark in the middle",
+			"Foo" + utf8BOM + " north=Bar",
+			"Foo" + utf8BOM + " north=Bar",
+		},
+		{
+			"no byte order mark",
+			"Foo north=Bar",
+			"Foo north=Bar",
+		},
+		{
+			"short source",
+			"A",
+			"A",
+		},
+		{
+			"empty source",
+			"",
+			"",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := io.ReadAll(skipBOM(strings.NewReader(testCase.source)))
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, string(data))
+		})
+	}
+}
+
+// TestFormatReaders_BOM makes sure the byte order mark is skipped
+// by the map readers of every input format, and the map header
+func TestFormatReaders_BOM(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		format Format
+		source string
+	}{
+		{FormatText, "Foo north=Bar\r\nBar south=Foo\r\n"},
+		{FormatJSON, `{"cities":[{"name":"Foo","neighbors":{"north":"Bar"}},{"name":"Bar","neighbors":{"south":"Foo"}}]}`},
+		{FormatCSV, "city,north,south\r\nFoo,Bar,\r\nBar,,Foo\r\n"},
+		{FormatDOT, "graph {\r\n\"Foo\" -- \"Bar\" [label=\"north\"];\r\n}\r\n"},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(string(testCase.format), func(t *testing.T) {
+			t.Parallel()
+
+			reader, err := NewFormatReader(strings.NewReader(utf8BOM+testCase.source), testCase.format)
+			if err != nil {
+				t.Fatalf("unable to create the %s reader, %v", testCase.format, err)
+			}
+
+			assert.Equal(t, []string{"Foo north=Bar", "Bar south=Foo"}, readAll(reader))
+		})
+	}
+
+	header, err := ReadHeader(strings.NewReader(utf8BOM + "# aliens: 10\r\nFoo\r\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"aliens": "10"}, header)
+}
@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+)
+
+// ChecksumWriter wraps a map writer, and computes the SHA-256 checksum
+// of the bytes written through it. The errors of the wrapped writer are
+// returned as they are, and the bytes of failed writes are not checksummed
+type ChecksumWriter struct {
+	inner OutputWriter
+	hash  hash.Hash
+}
+
+// NewChecksumWriter creates a new instance of the checksum writer,
+// which writes the output to the given writer
+func NewChecksumWriter(inner OutputWriter) *ChecksumWriter {
+	return &ChecksumWriter{
+		inner: inner,
+		hash:  sha256.New(),
+	}
+}
+
+func (cw *ChecksumWriter) Write(s string) error {
+	if err := cw.inner.Write(s); err != nil {
+		return err
+	}
+
+	// Writing to the hash never fails
+	_, _ = cw.hash.Write([]byte(s))
+
+	return nil
+}
+
+func (cw *ChecksumWriter) Flush() error {
+	return cw.inner.Flush()
+}
+
+func (cw *ChecksumWriter) Close() error {
+	return cw.inner.Close()
+}
+
+// Sum returns the hex encoded SHA-256 checksum of the bytes written so far.
+// The checksum covers the whole output once the writer is flushed or closed
+func (cw *ChecksumWriter) Sum() string {
+	return hex.EncodeToString(cw.hash.Sum(nil))
+}
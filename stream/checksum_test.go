@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChecksumWriter_Sum makes sure the checksum
+// covers the exact bytes written to the output
+func TestChecksumWriter_Sum(t *testing.T) {
+	t.Parallel()
+
+	var (
+		inner  = NewSliceWriter()
+		writer = NewChecksumWriter(inner)
+	)
+
+	// The checksum of no output is the checksum of empty input
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", writer.Sum())
+
+	assert.NoError(t, writer.Write("Foo north=Bar\n"))
+	assert.NoError(t, writer.Write("Bar south=Foo\n"))
+	assert.NoError(t, writer.Flush())
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, []string{"Foo north=Bar\n", "Bar south=Foo\n"}, inner.Lines())
+	assert.Equal(t, "b07854ee988e6ecda707b4ddd18b42c065febadca7dacfe0eb938abd31edcae7", writer.Sum())
+}
+
+// failingOutputWriter is an output writer
+// that fails every operation with the same error
+type failingOutputWriter struct {
+	err error
+}
+
+func (w *failingOutputWriter) Write(string) error {
+	return w.err
+}
+
+func (w *failingOutputWriter) Flush() error {
+	return w.err
+}
+
+func (w *failingOutputWriter) Close() error {
+	return w.err
+}
+
+// TestChecksumWriter_Errors makes sure the errors of the wrapped writer
+// are returned as they are, and the failed writes are not checksummed
+func TestChecksumWriter_Errors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errWrite = errors.New("write failed")
+		writer   = NewChecksumWriter(&failingOutputWriter{err: errWrite})
+		emptySum = writer.Sum()
+	)
+
+	assert.Equal(t, errWrite, writer.Write("Foo north=Bar\n"))
+	assert.Equal(t, errWrite, writer.Flush())
+	assert.Equal(t, errWrite, writer.Close())
+
+	assert.Equal(t, emptySum, writer.Sum())
+}
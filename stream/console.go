@@ -1,27 +1,27 @@
 package stream
 
 import (
-	"fmt"
+	"io"
+	"os"
 )
 
 // ConsoleWriter outputs the data to standard output (console)
 type ConsoleWriter struct {
+	*Writer
 }
 
+// NewConsoleWriter creates a console writer that outputs the data to standard output.
+// The lines are buffered until the writer is flushed or closed, so the large maps
+// are written out in chunks, instead of line by line
 func NewConsoleWriter() OutputWriter {
-	return &ConsoleWriter{}
+	return NewConsoleWriterTo(os.Stdout)
 }
 
-func (cw *ConsoleWriter) Write(s string) error {
-	fmt.Print(s)
-
-	return nil
-}
-
-func (cw *ConsoleWriter) Close() error {
-	return nil
-}
-
-func (cw *ConsoleWriter) Flush() error {
-	return nil
+// NewConsoleWriterTo creates a console writer that outputs
+// the data to the given console stream, instead of standard output.
+// The console stream is left open when the writer is closed
+func NewConsoleWriterTo(output io.Writer) OutputWriter {
+	return &ConsoleWriter{
+		Writer: newWriter(output, nil),
+	}
 }
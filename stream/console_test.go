@@ -0,0 +1,127 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConsoleWriter_Write makes sure the output lines
+// are written to the console stream
+func TestConsoleWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	writer := NewConsoleWriterTo(&output)
+
+	assert.NoError(t, writer.Write("Foo north=Bar\n"))
+	assert.NoError(t, writer.Write("Bar south=Foo\n"))
+	assert.NoError(t, writer.Flush())
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, "Foo north=Bar\nBar south=Foo\n", output.String())
+}
+
+// TestConsoleWriter_Close makes sure the console
+// stream is flushed, but left open on close
+func TestConsoleWriter_Close(t *testing.T) {
+	t.Parallel()
+
+	output := &eventWriter{}
+	writer := NewConsoleWriterTo(output)
+
+	assert.NoError(t, writer.Write("Foo\n"))
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, []string{"write Foo\n"}, output.events)
+}
+
+// countingWriter counts the writes to the destination
+type countingWriter struct {
+	bytes.Buffer
+
+	writes int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	cw.writes++
+
+	return cw.Buffer.Write(p)
+}
+
+// TestConsoleWriter_SingleFlush makes sure no line is lost when the console writer
+// is only flushed once at the end, and the lines are written out in chunks
+func TestConsoleWriter_SingleFlush(t *testing.T) {
+	t.Parallel()
+
+	var (
+		output   = &countingWriter{}
+		writer   = NewConsoleWriterTo(output)
+		expected strings.Builder
+	)
+
+	for i := 0; i < 100000; i++ {
+		line := fmt.Sprintf("City_%d east=City_%d\n", i, i+1)
+
+		assert.NoError(t, writer.Write(line))
+		expected.WriteString(line)
+	}
+
+	assert.NoError(t, writer.Flush())
+
+	assert.Equal(t, expected.String(), output.String())
+	assert.Less(t, output.writes, 100000/10)
+}
+
+// BenchmarkConsoleWriter_Write writes 1M map lines to a file through the console writer,
+// and line by line without the buffering, like the console output used to be written
+func BenchmarkConsoleWriter_Write(b *testing.B) {
+	const numLines = 1000000
+
+	// openOutput opens a file in place of the standard output, so the
+	// benchmark pays for the system calls, without flooding the console
+	openOutput := func(b *testing.B) *os.File {
+		b.Helper()
+
+		file, err := os.Create(filepath.Join(b.TempDir(), "output.txt"))
+		if err != nil {
+			b.Fatalf("unable to create the output file, %v", err)
+		}
+
+		b.Cleanup(func() {
+			_ = file.Close()
+		})
+
+		return file
+	}
+
+	b.Run("buffered", func(b *testing.B) {
+		output := openOutput(b)
+
+		for i := 0; i < b.N; i++ {
+			writer := NewConsoleWriterTo(output)
+
+			for line := 0; line < numLines; line++ {
+				_ = writer.Write("Foo north=Bar west=Baz south=Qu-ux\n")
+			}
+
+			_ = writer.Flush()
+		}
+	})
+
+	b.Run("unbuffered", func(b *testing.B) {
+		output := openOutput(b)
+
+		for i := 0; i < b.N; i++ {
+			for line := 0; line < numLines; line++ {
+				_, _ = fmt.Fprint(output, "Foo north=Bar west=Baz south=Qu-ux\n")
+			}
+		}
+	})
+}
@@ -0,0 +1,321 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrMissingColumn  = errors.New("missing column")
+	ErrInvalidDOTLine = errors.New("invalid DOT line")
+	ErrInvalidCost    = errors.New("invalid travel cost")
+)
+
+// InputFormats returns the supported input formats
+func InputFormats() []Format {
+	return []Format{
+		FormatText,
+		FormatJSON,
+		FormatDOT,
+		FormatCSV,
+	}
+}
+
+// DetectInputFormat detects the input format from the file extension,
+// ignoring the gzip extension of compressed files (ex. map.json.gz).
+// Returns the text format if the extension is unknown, or belongs
+// to a format that can't be read
+func DetectInputFormat(path string) Format {
+	format := FormatFromPath(trimGzipExtension(path))
+
+	for _, inputFormat := range InputFormats() {
+		if format == inputFormat {
+			return format
+		}
+	}
+
+	return FormatText
+}
+
+// NewFormatReader creates a map reader that decodes the map in the given
+// format from the source. If the source is also an io.Closer,
+// it is closed along with the map reader
+func NewFormatReader(source io.Reader, format Format) (InputReader, error) {
+	switch format {
+	case FormatText:
+		return NewScannerReader(source), nil
+	case FormatJSON:
+		return NewJSONReader(source)
+	case FormatDOT:
+		return NewDOTReader(source)
+	case FormatCSV:
+		return NewCSVReader(source)
+	default:
+		return nil, fmt.Errorf("%w: %s is not an input format", ErrUnsupportedFormat, format)
+	}
+}
+
+// LinesReader implements the map reader interface for
+// reading the map from city lines decoded up front
+type LinesReader struct {
+	source io.Reader
+	lines  []string
+	next   int // the index of the next line to read
+}
+
+// newLinesReader creates a new instance of the lines reader.
+// If the source is also an io.Closer, it is closed along with the map reader
+func newLinesReader(source io.Reader, lines []string) *LinesReader {
+	return &LinesReader{
+		source: source,
+		lines:  lines,
+		next:   -1,
+	}
+}
+
+func (lr *LinesReader) HasMoreCities() bool {
+	if lr.next+1 >= len(lr.lines) {
+		return false
+	}
+
+	lr.next++
+
+	return true
+}
+
+func (lr *LinesReader) ReadCity() (string, error) {
+	return lr.lines[lr.next], nil
+}
+
+// Err always returns nil, since the lines are decoded up front,
+// and the decoding errors are returned when the reader is created
+func (lr *LinesReader) Err() error {
+	return nil
+}
+
+func (lr *LinesReader) Close() error {
+	if closer, ok := lr.source.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// formatCityLine formats the city in the text map format (Foo north=Bar:3 ...),
+// with the directions in the map output order, and the travel costs, if any
+func formatCityLine(name string, neighbors map[string]string, costs map[string]int) string {
+	var sb strings.Builder
+
+	sb.WriteString(name)
+
+	for _, direction := range directionNames {
+		neighbor := neighbors[direction]
+		if neighbor == "" {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf(" %s=%s", direction, neighbor))
+
+		if cost, ok := costs[direction]; ok {
+			sb.WriteString(fmt.Sprintf(":%d", cost))
+		}
+	}
+
+	return sb.String()
+}
+
+// NewJSONReader creates a map reader for the JSON map format,
+// the same one the JSON output format produces. Only the fields
+// of the map schema that make up the city lines are decoded
+func NewJSONReader(source io.Reader) (InputReader, error) {
+	var jsonMap struct {
+		Cities []struct {
+			Name      string            `json:"name"`
+			Neighbors map[string]string `json:"neighbors"`
+			Costs     map[string]int    `json:"costs"`
+		} `json:"cities"`
+	}
+
+	if err := json.NewDecoder(skipBOM(source)).Decode(&jsonMap); err != nil {
+		return nil, fmt.Errorf("unable to decode the JSON map, %w", err)
+	}
+
+	lines := make([]string, 0, len(jsonMap.Cities))
+
+	for _, city := range jsonMap.Cities {
+		lines = append(lines, formatCityLine(city.Name, city.Neighbors, city.Costs))
+	}
+
+	return newLinesReader(source, lines), nil
+}
+
+// NewCSVReader creates a map reader for the CSV map format, the same one
+// the CSV output format produces. The header row names the city column,
+// the direction columns, and the optional travel cost columns, which can be in any order
+func NewCSVReader(source io.Reader) (InputReader, error) {
+	records, err := csv.NewReader(skipBOM(source)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the CSV map, %w", err)
+	}
+
+	if len(records) == 0 {
+		return newLinesReader(source, nil), nil
+	}
+
+	// Find the columns from the header
+	columns := make(map[string]int, len(records[0]))
+	for index, column := range records[0] {
+		columns[strings.TrimSpace(column)] = index
+	}
+
+	cityColumn, ok := columns["city"]
+	if !ok {
+		return nil, fmt.Errorf("%w: city", ErrMissingColumn)
+	}
+
+	lines := make([]string, 0, len(records)-1)
+
+	for _, record := range records[1:] {
+		neighbors := make(map[string]string, len(directionNames))
+
+		costs := make(map[string]int)
+
+		for _, direction := range directionNames {
+			if column, ok := columns[direction]; ok {
+				neighbors[direction] = record[column]
+			}
+
+			column, ok := columns[costColumn(direction)]
+			if !ok || record[column] == "" {
+				continue
+			}
+
+			cost, err := strconv.Atoi(record[column])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s of %s, %v", ErrInvalidCost, costColumn(direction), record[cityColumn], err)
+			}
+
+			costs[direction] = cost
+		}
+
+		lines = append(lines, formatCityLine(record[cityColumn], neighbors, costs))
+	}
+
+	return newLinesReader(source, lines), nil
+}
+
+var (
+	// dotEdgeRegex matches a labeled road between two cities, with an optional
+	// travel cost ("Foo" -- "Bar" [label="north"]; or "Foo" -- "Bar" [label="north", cost=3];)
+	dotEdgeRegex = regexp.MustCompile(
+		`^"((?:[^"\\]|\\.)*)"\s*--\s*"((?:[^"\\]|\\.)*)"\s*\[label="(\w+)"(?:\s*,\s*cost=(\d+))?\]\s*;?$`,
+	)
+
+	// dotNodeRegex matches a city without roads ("Foo";)
+	dotNodeRegex = regexp.MustCompile(`^"((?:[^"\\]|\\.)*)"\s*;?$`)
+)
+
+// oppositeDirections maps the direction names to their opposites
+var oppositeDirections = map[string]string{
+	"north": "south",
+	"south": "north",
+	"east":  "west",
+	"west":  "east",
+}
+
+// NewDOTReader creates a map reader for the Graphviz graph format, the same one
+// the DOT output format produces. Each road is an edge labeled with its direction,
+// from the perspective of the first city
+func NewDOTReader(source io.Reader) (InputReader, error) {
+	var (
+		names     = make([]string, 0)
+		neighbors = make(map[string]map[string]string)
+		costs     = make(map[string]map[string]int)
+		scanner   = bufio.NewScanner(skipBOM(source))
+		lineNum   = 0
+	)
+
+	// addCity adds the city in the order of appearance, if it's not present
+	addCity := func(name string) {
+		if _, ok := neighbors[name]; !ok {
+			names = append(names, name)
+			neighbors[name] = make(map[string]string)
+			costs[name] = make(map[string]int)
+		}
+	}
+
+	// DOT lines hold the city names, so they get the same max size as the text map lines
+	scanner.Buffer(make([]byte, 0, initialLineBufferSize), DefaultMaxLineSize)
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip the graph declaration and blank lines
+		if line == "" || line == "}" || strings.HasPrefix(line, "graph ") {
+			continue
+		}
+
+		if match := dotEdgeRegex.FindStringSubmatch(line); match != nil {
+			city, cityErr := strconv.Unquote(`"` + match[1] + `"`)
+			neighbor, neighborErr := strconv.Unquote(`"` + match[2] + `"`)
+			opposite, ok := oppositeDirections[match[3]]
+
+			if cityErr != nil || neighborErr != nil || !ok {
+				return nil, fmt.Errorf("%w: line %d, %s", ErrInvalidDOTLine, lineNum, line)
+			}
+
+			addCity(city)
+			addCity(neighbor)
+
+			neighbors[city][match[3]] = neighbor
+			neighbors[neighbor][opposite] = city
+
+			// The travel cost applies to the road in both directions
+			if match[4] != "" {
+				cost, err := strconv.Atoi(match[4])
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %d, %v", ErrInvalidCost, lineNum, err)
+				}
+
+				costs[city][match[3]] = cost
+				costs[neighbor][opposite] = cost
+			}
+
+			continue
+		}
+
+		if match := dotNodeRegex.FindStringSubmatch(line); match != nil {
+			city, err := strconv.Unquote(`"` + match[1] + `"`)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d, %s", ErrInvalidDOTLine, lineNum, line)
+			}
+
+			addCity(city)
+
+			continue
+		}
+
+		return nil, fmt.Errorf("%w: line %d, %s", ErrInvalidDOTLine, lineNum, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to decode the DOT map, %w", err)
+	}
+
+	lines := make([]string, 0, len(names))
+
+	for _, name := range names {
+		lines = append(lines, formatCityLine(name, neighbors[name], costs[name]))
+	}
+
+	return newLinesReader(source, lines), nil
+}
@@ -0,0 +1,231 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDecodeReaders makes sure the JSON and CSV maps
+// are decoded into the text map lines
+func TestDecodeReaders(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name          string
+		newReader     func(string) (InputReader, error)
+		input         string
+		expectedLines []string
+	}{
+		{
+			"json",
+			func(input string) (InputReader, error) {
+				return NewJSONReader(strings.NewReader(input))
+			},
+			`{"cities": [
+				{"name": "Foo", "neighbors": {"west": "Baz", "north": "Bar"}},
+				{"name": "Lone"}
+			]}`,
+			[]string{"Foo north=Bar west=Baz", "Lone"},
+		},
+		{
+			"csv",
+			func(input string) (InputReader, error) {
+				return NewCSVReader(strings.NewReader(input))
+			},
+			"city,north,south,east,west\nFoo,Bar,,,Baz\nLone,,,,\n",
+			[]string{"Foo north=Bar west=Baz", "Lone"},
+		},
+		{
+			"csv with reordered columns",
+			func(input string) (InputReader, error) {
+				return NewCSVReader(strings.NewReader(input))
+			},
+			"west,city,north\nBaz,Foo,Bar\n",
+			[]string{"Foo north=Bar west=Baz"},
+		},
+		{
+			"empty csv",
+			func(input string) (InputReader, error) {
+				return NewCSVReader(strings.NewReader(input))
+			},
+			"",
+			[]string{},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			reader, err := testCase.newReader(testCase.input)
+			if err != nil {
+				t.Fatalf("unable to create the reader, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedLines, readAll(reader))
+			assert.NoError(t, reader.Close())
+		})
+	}
+}
+
+// TestDecodeReaders_Invalid makes sure malformed
+// maps are reported
+func TestDecodeReaders_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewJSONReader(strings.NewReader(`{"cities": [`))
+	assert.ErrorContains(t, err, "unable to decode the JSON map")
+
+	_, err = NewCSVReader(strings.NewReader("name,north\nFoo,Bar\n"))
+	assert.ErrorIs(t, err, ErrMissingColumn)
+
+	_, err = NewCSVReader(strings.NewReader("city,north\nFoo,Bar,Baz\n"))
+	assert.ErrorContains(t, err, "unable to decode the CSV map")
+
+	_, err = NewCSVReader(strings.NewReader("city,north,north_cost\nFoo,Bar,far\n"))
+	assert.ErrorIs(t, err, ErrInvalidCost)
+}
+
+// TestDecodeReaders_RoundTrip makes sure the maps encoded
+// in a graph format can be read back in the same format, along with the travel costs
+func TestDecodeReaders_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cities := []MapCity{
+		{Name: "Bar", Neighbors: map[string]string{"south": "Foo", "west": "Bee"}, Costs: map[string]int{"west": 3}},
+		{Name: "Bee", Neighbors: map[string]string{"east": "Bar"}, Costs: map[string]int{"east": 3}},
+		{Name: "Foo", Neighbors: map[string]string{"north": "Bar"}},
+	}
+
+	lines := []string{"Bar south=Foo west=Bee:3", "Bee east=Bar:3", "Foo north=Bar"}
+
+	for format, newReader := range map[Format]func(*bytes.Buffer) (InputReader, error){
+		FormatCSV: func(b *bytes.Buffer) (InputReader, error) { return NewCSVReader(b) },
+		FormatDOT: func(b *bytes.Buffer) (InputReader, error) { return NewDOTReader(b) },
+	} {
+		encoded, err := EncodeMap(cities, format)
+		if err != nil {
+			t.Fatalf("unable to encode the %s map, %v", format, err)
+		}
+
+		reader, err := newReader(bytes.NewBufferString(encoded))
+		if err != nil {
+			t.Fatalf("unable to create the %s reader, %v", format, err)
+		}
+
+		// The DOT graph lists the cities in the order of the roads
+		assert.ElementsMatch(t, lines, readAll(reader), format)
+	}
+}
+
+// TestNewFormatReader makes sure each input format
+// is decoded from its fixture into the same map
+func TestNewFormatReader(t *testing.T) {
+	t.Parallel()
+
+	expectedLines := []string{
+		"Bar south=Foo west=Bee",
+		"Bee east=Bar",
+		"Foo north=Bar",
+		"Lone",
+	}
+
+	for _, format := range InputFormats() {
+		format := format
+
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			fixturePath := filepath.Join("testdata", "map."+string(format))
+
+			if format == FormatText {
+				fixturePath = filepath.Join("testdata", "map.txt")
+			}
+
+			// The format is detected from the fixture extension
+			assert.Equal(t, format, DetectInputFormat(fixturePath))
+
+			fixture, err := os.Open(fixturePath)
+			if err != nil {
+				t.Fatalf("unable to open the fixture, %v", err)
+			}
+
+			reader, err := NewFormatReader(fixture, format)
+			if err != nil {
+				t.Fatalf("unable to create the %s reader, %v", format, err)
+			}
+
+			assert.Equal(t, expectedLines, readAll(reader))
+			assert.NoError(t, reader.Close())
+		})
+	}
+}
+
+// TestNewFormatReader_Unsupported makes sure formats
+// that can't be read are rejected
+func TestNewFormatReader_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFormatReader(strings.NewReader(""), FormatMermaid)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	// Unreadable formats are not detected
+	assert.Equal(t, FormatText, DetectInputFormat("map.mmd"))
+	assert.Equal(t, FormatText, DetectInputFormat("map.dat"))
+}
+
+// TestNewDOTReader_Invalid makes sure malformed
+// DOT maps are reported
+func TestNewDOTReader_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		input string
+	}{
+		{
+			"unknown statement",
+			"graph earth {\n  Foo -> Bar;\n}\n",
+		},
+		{
+			"unknown direction",
+			"graph earth {\n  \"Foo\" -- \"Bar\" [label=\"up\"];\n}\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewDOTReader(strings.NewReader(testCase.input))
+			assert.ErrorIs(t, err, ErrInvalidDOTLine)
+		})
+	}
+}
+
+// TestNewDOTReader_LongLine makes sure DOT lines longer
+// than bufio.MaxScanTokenSize are decoded
+func TestNewDOTReader_LongLine(t *testing.T) {
+	t.Parallel()
+
+	longName := strings.Repeat("B", 2*bufio.MaxScanTokenSize)
+
+	reader, err := NewDOTReader(strings.NewReader(
+		"graph earth {\n  \"Foo\" -- \"" + longName + "\" [label=\"north\"];\n}\n",
+	))
+	if err != nil {
+		t.Fatalf("unable to decode the DOT map, %v", err)
+	}
+
+	assert.Equal(t, []string{"Foo north=" + longName, longName + " south=Foo"}, readAll(reader))
+}
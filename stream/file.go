@@ -1,7 +1,7 @@
 package stream
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
 )
@@ -9,68 +9,108 @@ import (
 // FileReader implements the map reader interface for
 // reading the map from an input file
 type FileReader struct {
-	mapFile     *os.File
-	fileScanner *bufio.Scanner
+	*ScannerReader
 }
 
 // NewFileReader creates a new instance of the file reader
 func NewFileReader(filePath string) (InputReader, error) {
-	mapFile, err := os.Open(filePath)
-
+	fileReader, err := NewFileReaderConcrete(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("unable to open file, %w", err)
+		return nil, err
 	}
 
-	fileScanner := bufio.NewScanner(mapFile)
-	fileScanner.Split(bufio.ScanLines)
+	return fileReader, nil
+}
+
+// NewFileReaderConcrete creates a new instance of the file reader, which
+// transparently decompresses gzip compressed map files, returning the concrete type, so the reader can be configured
+// before reading (ex. the max line size for very long city lines)
+func NewFileReaderConcrete(filePath string) (*FileReader, error) {
+	mapFile, err := OpenMapFile(filePath)
+	if err != nil {
+		return nil, err
+	}
 
+	// The file reader is a thin wrapper over
+	// the scanner reader, which closes the map file on Close
 	return &FileReader{
-		mapFile:     mapFile,
-		fileScanner: fileScanner,
+		ScannerReader: newScannerReader(mapFile, mapFile),
 	}, nil
 }
 
-func (fr *FileReader) HasMoreCities() bool {
-	return fr.fileScanner.Scan()
+// FileWriter implements the map writer interface for
+// writing the map to an output file
+type FileWriter struct {
+	*Writer
 }
 
-func (fr *FileReader) ReadCity() string {
-	return fr.fileScanner.Text()
+// ErrConflictingFileOptions is returned when the file writer is set
+// to both append to the file and refuse to write to an existing file
+var ErrConflictingFileOptions = errors.New("the append and no-clobber file options conflict")
+
+// FileWriterOptions are the options of the file writer
+type FileWriterOptions struct {
+	Append    bool        // flag indicating if the output is appended to the end of the file, instead of replacing it
+	NoClobber bool        // flag indicating if an existing file is an error, instead of being replaced
+	Mode      os.FileMode // the permissions of a newly created file (before the umask), 0666 if omitted
 }
 
-func (fr *FileReader) Close() error {
-	return fr.mapFile.Close()
+// NewFileWriter creates a new instance of the file writer,
+// which replaces the file if it exists
+func NewFileWriter(filePath string) (OutputWriter, error) {
+	return NewFileWriterOptions(filePath, FileWriterOptions{})
 }
 
-type FileWriter struct {
-	outputFile     *os.File
-	bufferedWriter *bufio.Writer
+// NewFileWriterAppend creates a new instance of the file writer, which appends
+// the output to the end of the file, instead of truncating it.
+// The file is created if it doesn't exist
+func NewFileWriterAppend(filePath string) (OutputWriter, error) {
+	return NewFileWriterOptions(filePath, FileWriterOptions{
+		Append: true,
+		Mode:   0o644,
+	})
 }
 
-func NewFileWriter(filePath string) (OutputWriter, error) {
-	file, err := os.Create(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create file, %w", err)
+// NewFileWriterOptions creates a new instance of the file writer with the given options.
+// With no-clobber set, the error for an existing file matches fs.ErrExist, so
+// the output of a previous run is never overwritten by accident
+func NewFileWriterOptions(filePath string, opts FileWriterOptions) (OutputWriter, error) {
+	if opts.Append && opts.NoClobber {
+		return nil, ErrConflictingFileOptions
 	}
 
-	bw := bufio.NewWriter(file)
+	mode := opts.Mode
+	if mode == 0 {
+		mode = 0o666
+	}
 
-	return &FileWriter{
-		outputFile:     file,
-		bufferedWriter: bw,
-	}, nil
-}
+	flag := os.O_CREATE | os.O_WRONLY
 
-func (fw *FileWriter) Write(s string) error {
-	_, err := fw.bufferedWriter.WriteString(s)
+	switch {
+	case opts.Append:
+		flag |= os.O_APPEND
+	case opts.NoClobber:
+		flag |= os.O_EXCL
+	default:
+		flag |= os.O_TRUNC
+	}
 
-	return err
-}
+	file, err := os.OpenFile(filePath, flag, mode)
+	if err != nil {
+		if opts.Append {
+			return nil, fmt.Errorf("unable to open file for appending, %w", err)
+		}
+
+		return nil, fmt.Errorf("unable to create file, %w", err)
+	}
 
-func (fw *FileWriter) Close() error {
-	return fw.outputFile.Close()
+	return newFileWriter(file), nil
 }
 
-func (fw *FileWriter) Flush() error {
-	return fw.bufferedWriter.Flush()
+// newFileWriter creates a buffered file writer for the opened file,
+// which is closed along with the writer
+func newFileWriter(file *os.File) *FileWriter {
+	return &FileWriter{
+		Writer: newWriter(file, file),
+	}
 }
@@ -0,0 +1,235 @@
+package stream
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFileReader_ReadCities makes sure the city lines
+// are properly read from the map file
+func TestFileReader_ReadCities(t *testing.T) {
+	t.Parallel()
+
+	mapPath := filepath.Join(t.TempDir(), "map.txt")
+
+	if err := os.WriteFile(mapPath, []byte("Foo north=Bar\nBar south=Foo\n"), 0o600); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	reader, err := NewFileReader(mapPath)
+	if err != nil {
+		t.Fatalf("unable to create file reader, %v", err)
+	}
+
+	assert.Equal(t, []string{"Foo north=Bar", "Bar south=Foo"}, readAll(reader))
+
+	// The file reader keeps track of the line numbers
+	if positioned, ok := reader.(PositionedReader); assert.True(t, ok) {
+		assert.Equal(t, 2, positioned.LineNumber())
+	}
+
+	assert.NoError(t, reader.Close())
+
+	// Make sure the file was closed
+	assert.Error(t, reader.Close())
+}
+
+// TestFileReader_MissingFile makes sure missing
+// map files are reported
+func TestFileReader_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFileReader(filepath.Join(t.TempDir(), "missing.txt"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// TestFileReader_MaxLineSize makes sure city lines longer than
+// bufio.MaxScanTokenSize are read by default, and lines longer than
+// the max line size are reported instead of silently truncating the map
+func TestFileReader_MaxLineSize(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mapPath  = filepath.Join(t.TempDir(), "map.txt")
+		longLine = "Foo north=" + strings.Repeat("B", 2*bufio.MaxScanTokenSize)
+	)
+
+	if err := os.WriteFile(mapPath, []byte(longLine+"\nBar south=Foo\n"), 0o600); err != nil {
+		t.Fatalf("unable to write map file, %v", err)
+	}
+
+	t.Run("default max line size", func(t *testing.T) {
+		t.Parallel()
+
+		reader, err := NewFileReaderConcrete(mapPath)
+		if err != nil {
+			t.Fatalf("unable to create file reader, %v", err)
+		}
+
+		defer reader.Close()
+
+		assert.Equal(t, []string{longLine, "Bar south=Foo"}, readAll(reader))
+		assert.NoError(t, reader.Err())
+	})
+
+	t.Run("lowered max line size", func(t *testing.T) {
+		t.Parallel()
+
+		reader, err := NewFileReaderConcrete(mapPath)
+		if err != nil {
+			t.Fatalf("unable to create file reader, %v", err)
+		}
+
+		defer reader.Close()
+
+		reader.SetMaxLineSize(bufio.MaxScanTokenSize)
+
+		assert.Empty(t, readAll(reader))
+		assert.ErrorIs(t, reader.Err(), bufio.ErrTooLong)
+	})
+}
+
+// TestFileWriterAppend_Append makes sure the appending file writer
+// keeps the existing output, and creates missing files
+func TestFileWriterAppend_Append(t *testing.T) {
+	t.Parallel()
+
+	outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+	for _, line := range []string{"Foo north=Bar\n", "Bar south=Foo\n"} {
+		writer, err := NewFileWriterAppend(outputPath)
+		if err != nil {
+			t.Fatalf("unable to create file writer, %v", err)
+		}
+
+		assert.NoError(t, writer.Write(line))
+		assert.NoError(t, writer.Flush())
+		assert.NoError(t, writer.Close())
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("unable to read output file, %v", err)
+	}
+
+	assert.Equal(t, "Foo north=Bar\nBar south=Foo\n", string(output))
+}
+
+// TestFileWriterOptions makes sure the file writer options
+// decide what happens to an existing output file
+func TestFileWriterOptions(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name           string
+		opts           FileWriterOptions
+		existing       bool
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			"replace the existing file",
+			FileWriterOptions{},
+			true,
+			"Bar south=Foo\n",
+			nil,
+		},
+		{
+			"append to the existing file",
+			FileWriterOptions{Append: true},
+			true,
+			"Foo north=Bar\nBar south=Foo\n",
+			nil,
+		},
+		{
+			"no-clobber with a new file",
+			FileWriterOptions{NoClobber: true},
+			false,
+			"Bar south=Foo\n",
+			nil,
+		},
+		{
+			"no-clobber with the existing file",
+			FileWriterOptions{NoClobber: true},
+			true,
+			"Foo north=Bar\n",
+			fs.ErrExist,
+		},
+		{
+			"append and no-clobber",
+			FileWriterOptions{Append: true, NoClobber: true},
+			true,
+			"Foo north=Bar\n",
+			ErrConflictingFileOptions,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+			if testCase.existing {
+				if err := os.WriteFile(outputPath, []byte("Foo north=Bar\n"), 0o600); err != nil {
+					t.Fatalf("unable to write output file, %v", err)
+				}
+			}
+
+			writer, err := NewFileWriterOptions(outputPath, testCase.opts)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+			} else {
+				if err != nil {
+					t.Fatalf("unable to create file writer, %v", err)
+				}
+
+				assert.NoError(t, writer.Write("Bar south=Foo\n"))
+				assert.NoError(t, writer.Close())
+			}
+
+			output, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("unable to read output file, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedOutput, string(output))
+		})
+	}
+}
+
+// TestFileWriterOptions_Mode makes sure the newly
+// created file has the given permissions
+func TestFileWriterOptions_Mode(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on Windows")
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := NewFileWriterOptions(outputPath, FileWriterOptions{Mode: 0o600})
+	if err != nil {
+		t.Fatalf("unable to create file writer, %v", err)
+	}
+
+	assert.NoError(t, writer.Close())
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		t.Fatalf("unable to stat output file, %v", err)
+	}
+
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
@@ -0,0 +1,250 @@
+package stream
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format defines the serialization format of the map output
+type Format string
+
+// Define the supported output formats
+const (
+	FormatText    Format = "text"
+	FormatJSON    Format = "json"
+	FormatDOT     Format = "dot"
+	FormatCSV     Format = "csv"
+	FormatMermaid Format = "mermaid"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// OutputFormats returns the supported output formats
+func OutputFormats() []Format {
+	return []Format{
+		FormatText,
+		FormatJSON,
+		FormatDOT,
+		FormatCSV,
+		FormatMermaid,
+	}
+}
+
+// formatExtensions maps the output file extensions to their formats
+var formatExtensions = map[string]Format{
+	".txt":     FormatText,
+	".json":    FormatJSON,
+	".dot":     FormatDOT,
+	".gv":      FormatDOT,
+	".csv":     FormatCSV,
+	".mmd":     FormatMermaid,
+	".mermaid": FormatMermaid,
+}
+
+// FormatFromPath infers the output format from the file extension.
+// Returns the text format if the extension is unknown
+func FormatFromPath(path string) Format {
+	if format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+
+	return FormatText
+}
+
+// directionNames are the direction names in the map output order
+var directionNames = []string{"north", "south", "east", "west"}
+
+// MapCity is a single city of the map, in the form the map formats encode
+type MapCity struct {
+	Name      string            // the name of the city
+	Neighbors map[string]string // the neighbor names, keyed by the direction name
+	Costs     map[string]int    // the travel costs of the roads with a non-default cost, keyed by the direction name
+}
+
+// EncodeMap encodes the map cities into the given graph format (dot, csv or mermaid).
+// The text and JSON formats are encoded by the map itself, since they follow its own schema
+func EncodeMap(cities []MapCity, format Format) (string, error) {
+	switch format {
+	case FormatDOT:
+		return encodeDOT(cities)
+	case FormatCSV:
+		return encodeCSV(cities)
+	case FormatMermaid:
+		return encodeMermaid(cities)
+	default:
+		return "", fmt.Errorf("%w: %s is not a graph format", ErrUnsupportedFormat, format)
+	}
+}
+
+// costColumn returns the name of the CSV column that holds
+// the travel cost of the road in the direction
+func costColumn(direction string) string {
+	return direction + "_cost"
+}
+
+// hasCosts checks if any of the roads has a non-default travel cost
+func hasCosts(cities []MapCity) bool {
+	for _, city := range cities {
+		if len(city.Costs) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encodeDOT encodes the cities into an undirected Graphviz graph,
+// where each road is a single edge labeled with its direction,
+// along with its travel cost, if it differs from the default
+func encodeDOT(cities []MapCity) (string, error) {
+	var (
+		sb    strings.Builder
+		roads = make(map[[2]string]struct{})
+	)
+
+	sb.WriteString("graph earth {\n")
+
+	for _, city := range cities {
+		if len(city.Neighbors) == 0 {
+			sb.WriteString(fmt.Sprintf("  %q;\n", city.Name))
+
+			continue
+		}
+
+		for _, direction := range directionNames {
+			neighbor, ok := city.Neighbors[direction]
+			if !ok {
+				continue
+			}
+
+			// The road is listed on both cities, but drawn only once
+			if _, drawn := roads[[2]string{neighbor, city.Name}]; drawn {
+				continue
+			}
+
+			roads[[2]string{city.Name, neighbor}] = struct{}{}
+
+			if cost, ok := city.Costs[direction]; ok {
+				sb.WriteString(fmt.Sprintf("  %q -- %q [label=%q, cost=%d];\n", city.Name, neighbor, direction, cost))
+
+				continue
+			}
+
+			sb.WriteString(fmt.Sprintf("  %q -- %q [label=%q];\n", city.Name, neighbor, direction))
+		}
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String(), nil
+}
+
+// encodeCSV encodes the cities into CSV rows, with a column for the neighbor in each direction.
+// If any road has a non-default travel cost, the costs get a column for each direction as well
+func encodeCSV(cities []MapCity) (string, error) {
+	var (
+		sb        strings.Builder
+		writer    = csv.NewWriter(&sb)
+		withCosts = hasCosts(cities)
+	)
+
+	header := append([]string{"city"}, directionNames...)
+
+	if withCosts {
+		for _, direction := range directionNames {
+			header = append(header, costColumn(direction))
+		}
+	}
+
+	if err := writer.Write(header); err != nil {
+		return "", err
+	}
+
+	for _, city := range cities {
+		record := []string{city.Name}
+
+		for _, direction := range directionNames {
+			record = append(record, city.Neighbors[direction])
+		}
+
+		if withCosts {
+			for _, direction := range directionNames {
+				cost := ""
+				if value, ok := city.Costs[direction]; ok {
+					cost = strconv.Itoa(value)
+				}
+
+				record = append(record, cost)
+			}
+		}
+
+		if err := writer.Write(record); err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+
+	return sb.String(), writer.Error()
+}
+
+// encodeMermaid encodes the cities into a Mermaid flowchart, where each road
+// is a single link labeled with its direction, along with its travel cost, if it differs
+// from the default. The city names are used as node labels, since they can contain
+// characters invalid in node IDs
+func encodeMermaid(cities []MapCity) (string, error) {
+	var (
+		sb    strings.Builder
+		ids   = make(map[string]string, len(cities))
+		roads = make(map[[2]string]struct{})
+	)
+
+	// nodeID returns the node ID of the city, declaring the node if needed
+	nodeID := func(name string) string {
+		if id, ok := ids[name]; ok {
+			return id
+		}
+
+		id := fmt.Sprintf("city%d", len(ids))
+		ids[name] = id
+
+		sb.WriteString(fmt.Sprintf("  %s[%q]\n", id, name))
+
+		return id
+	}
+
+	sb.WriteString("graph TD\n")
+
+	for _, city := range cities {
+		nodeID(city.Name)
+	}
+
+	for _, city := range cities {
+		for _, direction := range directionNames {
+			neighbor, ok := city.Neighbors[direction]
+			if !ok {
+				continue
+			}
+
+			// The road is listed on both cities, but drawn only once
+			if _, drawn := roads[[2]string{neighbor, city.Name}]; drawn {
+				continue
+			}
+
+			roads[[2]string{city.Name, neighbor}] = struct{}{}
+
+			label := direction
+			if cost, ok := city.Costs[direction]; ok {
+				label = fmt.Sprintf("%s, cost %d", direction, cost)
+			}
+
+			sb.WriteString(fmt.Sprintf("  %s ---|%s| %s\n", nodeID(city.Name), label, nodeID(neighbor)))
+		}
+	}
+
+	return sb.String(), nil
+}
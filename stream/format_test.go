@@ -0,0 +1,189 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMapCities returns the map cities used by the encoding tests
+func testMapCities() []MapCity {
+	return []MapCity{
+		{Name: "Bar", Neighbors: map[string]string{"south": "Foo", "west": "Bee"}},
+		{Name: "Bee", Neighbors: map[string]string{"east": "Bar"}},
+		{Name: "Foo", Neighbors: map[string]string{"north": "Bar"}},
+		{Name: "Lone"},
+	}
+}
+
+// TestEncodeMap_Formats makes sure the map cities
+// are encoded into each graph format
+func TestEncodeMap_Formats(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name           string
+		format         Format
+		expectedOutput string
+	}{
+		{
+			"dot",
+			FormatDOT,
+			"graph earth {\n" +
+				"  \"Bar\" -- \"Foo\" [label=\"south\"];\n" +
+				"  \"Bar\" -- \"Bee\" [label=\"west\"];\n" +
+				"  \"Lone\";\n" +
+				"}\n",
+		},
+		{
+			"csv",
+			FormatCSV,
+			"city,north,south,east,west\n" +
+				"Bar,,Foo,,Bee\n" +
+				"Bee,,,Bar,\n" +
+				"Foo,Bar,,,\n" +
+				"Lone,,,,\n",
+		},
+		{
+			"mermaid",
+			FormatMermaid,
+			"graph TD\n" +
+				"  city0[\"Bar\"]\n" +
+				"  city1[\"Bee\"]\n" +
+				"  city2[\"Foo\"]\n" +
+				"  city3[\"Lone\"]\n" +
+				"  city0 ---|south| city2\n" +
+				"  city0 ---|west| city1\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			output, err := EncodeMap(testMapCities(), testCase.format)
+			if err != nil {
+				t.Fatalf("unable to encode the map, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedOutput, output)
+		})
+	}
+}
+
+// TestEncodeMap_Costs makes sure the travel costs are encoded
+// in their own field, apart from the neighbor names
+func TestEncodeMap_Costs(t *testing.T) {
+	t.Parallel()
+
+	cities := []MapCity{
+		{
+			Name:      "Bar",
+			Neighbors: map[string]string{"south": "Foo"},
+			Costs:     map[string]int{"south": 3},
+		},
+		{
+			Name:      "Foo",
+			Neighbors: map[string]string{"north": "Bar", "east": "Bee"},
+			Costs:     map[string]int{"north": 3},
+		},
+		{
+			Name:      "Bee",
+			Neighbors: map[string]string{"west": "Foo"},
+		},
+	}
+
+	testTable := []struct {
+		name           string
+		format         Format
+		expectedOutput string
+	}{
+		{
+			"dot",
+			FormatDOT,
+			"graph earth {\n" +
+				"  \"Bar\" -- \"Foo\" [label=\"south\", cost=3];\n" +
+				"  \"Foo\" -- \"Bee\" [label=\"east\"];\n" +
+				"}\n",
+		},
+		{
+			"csv",
+			FormatCSV,
+			"city,north,south,east,west,north_cost,south_cost,east_cost,west_cost\n" +
+				"Bar,,Foo,,,,3,,\n" +
+				"Foo,Bar,,Bee,,3,,,\n" +
+				"Bee,,,,Foo,,,,\n",
+		},
+		{
+			"mermaid",
+			FormatMermaid,
+			"graph TD\n" +
+				"  city0[\"Bar\"]\n" +
+				"  city1[\"Foo\"]\n" +
+				"  city2[\"Bee\"]\n" +
+				"  city0 ---|south, cost 3| city1\n" +
+				"  city1 ---|east| city2\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			output, err := EncodeMap(cities, testCase.format)
+			if err != nil {
+				t.Fatalf("unable to encode the map, %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedOutput, output)
+		})
+	}
+}
+
+// TestEncodeMap_Unsupported makes sure the formats
+// that are not graph formats are rejected
+func TestEncodeMap_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []Format{FormatText, FormatJSON, Format("xml")} {
+		_, err := EncodeMap(testMapCities(), format)
+
+		assert.ErrorIs(t, err, ErrUnsupportedFormat, format)
+	}
+}
+
+// TestFormatFromPath makes sure the output format
+// is inferred from the file extension
+func TestFormatFromPath(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		path           string
+		expectedFormat Format
+	}{
+		{"map.txt", FormatText},
+		{"map.json", FormatJSON},
+		{"map.JSON", FormatJSON},
+		{"map.dot", FormatDOT},
+		{"map.gv", FormatDOT},
+		{"map.csv", FormatCSV},
+		{"map.mmd", FormatMermaid},
+		{"out/map.mermaid", FormatMermaid},
+		{"map", FormatText},
+		{"map.dat", FormatText},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.path, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expectedFormat, FormatFromPath(testCase.path))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package stream
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// NewFSReader creates a map reader that reads the city lines from the map file
+// in any file system, such as the maps embedded in the binary with go:embed.
+// Gzip compressed map files are decompressed on the fly, like with NewFileReader,
+// and the map file is closed along with the map reader
+func NewFSReader(fsys fs.FS, path string) (InputReader, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file, %w", err)
+	}
+
+	mapFile, err := openMapSource(file, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newScannerReader(mapFile, mapFile), nil
+}
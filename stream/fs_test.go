@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"embed"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed testdata
+var testdataFS embed.FS
+
+// TestFSReader_ReadCities makes sure the city lines are read from the map file
+// in the file system, decompressed if the map file is gzip compressed
+func TestFSReader_ReadCities(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"maps/map.txt":    {Data: []byte(testMap)},
+		"maps/map.txt.gz": {Data: gzipData(t, testMap)},
+	}
+
+	for _, path := range []string{"maps/map.txt", "maps/map.txt.gz"} {
+		reader, err := NewFSReader(fsys, path)
+		if err != nil {
+			t.Fatalf("unable to create the fs reader, %v", err)
+		}
+
+		assert.Equal(t, []string{"Foo north=Bar", "Bar south=Foo"}, readAll(reader))
+		assert.NoError(t, reader.Close())
+	}
+}
+
+// TestFSReader_Embedded makes sure the embedded map files
+// are read like the same map files on disk
+func TestFSReader_Embedded(t *testing.T) {
+	t.Parallel()
+
+	reader, err := NewFSReader(testdataFS, "testdata/map.txt")
+	if err != nil {
+		t.Fatalf("unable to create the fs reader, %v", err)
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	fileReader, err := NewFileReader(filepath.Join("testdata", "map.txt"))
+	if err != nil {
+		t.Fatalf("unable to create the file reader, %v", err)
+	}
+
+	defer func() {
+		_ = fileReader.Close()
+	}()
+
+	assert.Equal(t, readAll(fileReader), readAll(reader))
+}
+
+// TestFSReader_Errors makes sure the missing map
+// files and the corrupt gzip streams are reported
+func TestFSReader_Errors(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"corrupt.txt.gz": {Data: []byte{0x1f, 0x8b, 0x00}},
+	}
+
+	_, err := NewFSReader(fsys, "missing.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = NewFSReader(fsys, "corrupt.txt.gz")
+	assert.ErrorContains(t, err, "unable to decompress file corrupt.txt.gz")
+}
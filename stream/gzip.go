@@ -0,0 +1,113 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gzipExtension is the extension of the gzip compressed map files
+const gzipExtension = ".gz"
+
+// gzipMagic are the leading bytes of every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// OpenMapFile opens the map file for reading. Gzip compressed map files are detected
+// by their leading bytes, regardless of the extension, and decompressed on the fly.
+// Closing the returned reader closes both the decompression and the file
+func OpenMapFile(filePath string) (io.ReadCloser, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file, %w", err)
+	}
+
+	return openMapSource(file, filePath)
+}
+
+// openMapSource prepares the opened map file for reading, decompressing it
+// if it's gzip compressed. The file is closed if the decompression can't start
+func openMapSource(file io.ReadCloser, filePath string) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(file)
+
+	// A short (or empty) file can't be gzip compressed,
+	// so the peek error is ignored
+	magic, _ := buffered.Peek(len(gzipMagic))
+	if !bytes.Equal(magic, gzipMagic) {
+		return &mapFile{
+			Reader: buffered,
+			file:   file,
+		}, nil
+	}
+
+	gzipReader, err := gzip.NewReader(buffered)
+	if err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("unable to decompress file %s, %w", filePath, err)
+	}
+
+	return &mapFile{
+		Reader: &annotatedReader{
+			reader:  gzipReader,
+			message: fmt.Sprintf("unable to decompress file %s", filePath),
+		},
+		file:       file,
+		gzipReader: gzipReader,
+	}, nil
+}
+
+// mapFile is an opened map file, decompressed if needed
+type mapFile struct {
+	io.Reader
+
+	file       io.Closer
+	gzipReader *gzip.Reader // the decompression of the file, if compressed
+}
+
+// Close closes the decompression, if any, and the file
+func (mf *mapFile) Close() error {
+	var gzipErr error
+
+	if mf.gzipReader != nil {
+		gzipErr = mf.gzipReader.Close()
+	}
+
+	if err := mf.file.Close(); err != nil {
+		return err
+	}
+
+	return gzipErr
+}
+
+// annotatedReader prefixes the read errors with the message, so they identify
+// the map source (ex. the file of a corrupt gzip stream, which surfaces only once
+// the stream is read). The end of the input is not an error, and is passed through
+type annotatedReader struct {
+	reader  io.Reader
+	message string
+}
+
+func (ar *annotatedReader) Read(p []byte) (int, error) {
+	n, err := ar.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, fmt.Errorf("%s, %w", ar.message, err)
+	}
+
+	return n, err
+}
+
+// trimGzipExtension removes the gzip extension from the path,
+// so the format is detected from the extension of the compressed file
+func trimGzipExtension(path string) string {
+	if strings.EqualFold(filepath.Ext(path), gzipExtension) {
+		return strings.TrimSuffix(path, filepath.Ext(path))
+	}
+
+	return path
+}
@@ -0,0 +1,134 @@
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMap is the map written out plain and gzip compressed
+const testMap = "Foo north=Bar\nBar south=Foo\n"
+
+// gzipData compresses the data
+func gzipData(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatalf("unable to compress the data, %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unable to compress the data, %v", err)
+	}
+
+	return buffer.Bytes()
+}
+
+// writeFile writes the data to a file in a temporary directory
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("unable to write the file, %v", err)
+	}
+
+	return path
+}
+
+// TestFileReader_Gzip makes sure the gzip compressed map files are
+// decompressed, detected by their contents instead of the extension
+func TestFileReader_Gzip(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name string
+		path string
+	}{
+		{
+			"plain map",
+			writeFile(t, "map.txt", []byte(testMap)),
+		},
+		{
+			"compressed map",
+			writeFile(t, "map.txt.gz", gzipData(t, testMap)),
+		},
+		{
+			"compressed map without the extension",
+			writeFile(t, "map.txt", gzipData(t, testMap)),
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			reader, err := NewFileReaderConcrete(testCase.path)
+			if err != nil {
+				t.Fatalf("unable to create file reader, %v", err)
+			}
+
+			assert.Equal(t, []string{"Foo north=Bar", "Bar south=Foo"}, readAll(reader))
+			assert.NoError(t, reader.Err())
+			assert.NoError(t, reader.Close())
+
+			// Make sure the file was closed
+			assert.Error(t, reader.Close())
+		})
+	}
+}
+
+// TestFileReader_CorruptGzip makes sure corrupt gzip
+// streams are reported, identifying the map file
+func TestFileReader_CorruptGzip(t *testing.T) {
+	t.Parallel()
+
+	t.Run("corrupt header", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "map.gz", append([]byte{0x1f, 0x8b}, []byte("not gzip")...))
+
+		_, err := NewFileReader(path)
+
+		assert.ErrorContains(t, err, "unable to decompress file "+path)
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		t.Parallel()
+
+		compressed := gzipData(t, testMap)
+		path := writeFile(t, "map.gz", compressed[:len(compressed)-4])
+
+		reader, err := NewFileReaderConcrete(path)
+		if err != nil {
+			t.Fatalf("unable to create file reader, %v", err)
+		}
+
+		defer reader.Close()
+
+		readAll(reader)
+
+		assert.ErrorContains(t, reader.Err(), "unable to decompress file "+path)
+	})
+}
+
+// TestDetectInputFormat_Gzip makes sure the format of the compressed
+// map files is detected from the extension of the compressed file
+func TestDetectInputFormat_Gzip(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, FormatJSON, DetectInputFormat("map.json.gz"))
+	assert.Equal(t, FormatCSV, DetectInputFormat("map.CSV.GZ"))
+	assert.Equal(t, FormatText, DetectInputFormat("map.gz"))
+}
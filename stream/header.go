@@ -0,0 +1,51 @@
+package stream
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// HeaderPrefix is the prefix of the comment lines in the text map,
+// which are skipped when the map is read
+const HeaderPrefix = "#"
+
+// headerEntryRegex matches the "# key: value" metadata lines of the map header
+var headerEntryRegex = regexp.MustCompile(`^#\s*([A-Za-z][\w-]*)\s*:\s*(.*?)\s*$`)
+
+// IsComment checks if the map line is a comment line
+func IsComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), HeaderPrefix)
+}
+
+// ReadHeader reads the metadata header of the text map, which is made up of the
+// comment lines before the first city line (ex. "# aliens: 10"). The metadata keys
+// are lowercased, and the comment lines that are not "# key: value" lines are ignored.
+// The header ends at the first line that is not a comment, including a blank line
+func ReadHeader(r io.Reader) (map[string]string, error) {
+	var (
+		header  = make(map[string]string)
+		scanner = bufio.NewScanner(skipBOM(r))
+	)
+
+	scanner.Buffer(make([]byte, 0, initialLineBufferSize), DefaultMaxLineSize)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !IsComment(line) {
+			break
+		}
+
+		if match := headerEntryRegex.FindStringSubmatch(line); match != nil {
+			header[strings.ToLower(match[1])] = match[2]
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read the map header, %w", err)
+	}
+
+	return header, nil
+}
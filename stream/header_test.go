@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReadHeader makes sure the metadata is read
+// from the comment lines before the first city line
+func TestReadHeader(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		input string
+
+		expectedHeader map[string]string
+	}{
+		{
+			"metadata header",
+			"# aliens: 10\n#Seed:42\n# generated by hand\nFoo north=Bar\n",
+			map[string]string{
+				"aliens": "10",
+				"seed":   "42",
+			},
+		},
+		{
+			"no header",
+			"Foo north=Bar\n# aliens: 10\n",
+			map[string]string{},
+		},
+		{
+			"header ended by a blank line",
+			"# aliens: 10\n\n# seed: 42\nFoo north=Bar\n",
+			map[string]string{
+				"aliens": "10",
+			},
+		},
+		{
+			"empty input",
+			"",
+			map[string]string{},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			header, err := ReadHeader(strings.NewReader(testCase.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedHeader, header)
+		})
+	}
+}
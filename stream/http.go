@@ -0,0 +1,211 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultHTTPTimeout is the default max duration of a single output POST request
+const DefaultHTTPTimeout = 30 * time.Second
+
+var (
+	ErrInvalidURL       = errors.New("invalid URL")
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+)
+
+// HTTPWriter implements the map writer interface for pushing
+// the map to a collector service. The output lines are buffered,
+// and POSTed as a single request body on Flush or Close
+type HTTPWriter struct {
+	url         string
+	ctx         context.Context
+	client      *http.Client
+	contentType string
+
+	buffer bytes.Buffer
+	posted bool // flag indicating if the output was POSTed at least once
+}
+
+// HTTPWriterOption is an option for the HTTP output writer
+type HTTPWriterOption func(*HTTPWriter)
+
+// WithHTTPContext sets the context of the POST requests,
+// so the output can be cancelled
+func WithHTTPContext(ctx context.Context) HTTPWriterOption {
+	return func(hw *HTTPWriter) {
+		hw.ctx = ctx
+	}
+}
+
+// WithHTTPTimeout sets the max duration of a single POST request
+func WithHTTPTimeout(timeout time.Duration) HTTPWriterOption {
+	return func(hw *HTTPWriter) {
+		hw.client.Timeout = timeout
+	}
+}
+
+// WithHTTPContentType sets the content type of the POSTed output
+func WithHTTPContentType(contentType string) HTTPWriterOption {
+	return func(hw *HTTPWriter) {
+		hw.contentType = contentType
+	}
+}
+
+// NewHTTPWriter creates a new instance of the HTTP writer,
+// which POSTs the output to the given http(s) URL
+func NewHTTPWriter(rawURL string, opts ...HTTPWriterOption) (OutputWriter, error) {
+	if err := validateHTTPURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	hw := &HTTPWriter{
+		url:         rawURL,
+		ctx:         context.Background(),
+		client:      &http.Client{Timeout: DefaultHTTPTimeout},
+		contentType: "text/plain; charset=utf-8",
+	}
+
+	for _, opt := range opts {
+		opt(hw)
+	}
+
+	return hw, nil
+}
+
+// IsHTTPURL checks if the map or output path is an http(s) URL
+func IsHTTPURL(path string) bool {
+	lowerPath := strings.ToLower(path)
+
+	return strings.HasPrefix(lowerPath, "http://") || strings.HasPrefix(lowerPath, "https://")
+}
+
+// validateHTTPURL makes sure the URL is a valid http(s) URL
+func validateHTTPURL(rawURL string) error {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s, %v", ErrInvalidURL, rawURL, err)
+	}
+
+	if !IsHTTPURL(rawURL) || parsedURL.Host == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidURL, rawURL)
+	}
+
+	return nil
+}
+
+func (hw *HTTPWriter) Write(s string) error {
+	_, err := hw.buffer.WriteString(s)
+
+	return err
+}
+
+// Flush POSTs the lines written since the previous flush.
+// The output is always POSTed at least once, even if it's empty
+func (hw *HTTPWriter) Flush() error {
+	if hw.posted && hw.buffer.Len() == 0 {
+		return nil
+	}
+
+	request, err := http.NewRequestWithContext(
+		hw.ctx,
+		http.MethodPost,
+		hw.url,
+		bytes.NewReader(hw.buffer.Bytes()),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create the output request, %w", err)
+	}
+
+	request.Header.Set("Content-Type", hw.contentType)
+
+	response, err := hw.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to post the output, %w", err)
+	}
+
+	defer func() {
+		// Drain the body, so the connection can be reused
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, response.Status)
+	}
+
+	// The output was received, so it's not sent again
+	hw.buffer.Reset()
+	hw.posted = true
+
+	return nil
+}
+
+// Close POSTs any output lines that weren't flushed
+func (hw *HTTPWriter) Close() error {
+	return hw.Flush()
+}
+
+// OpenURL GETs the http(s) URL, and returns the response body for reading.
+// The request, including the body reading, times out after DefaultHTTPTimeout.
+// The redirects and the gzip content encoding are handled by the HTTP client
+func OpenURL(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	if err := validateHTTPURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the map request, %w", err)
+	}
+
+	client := &http.Client{Timeout: DefaultHTTPTimeout}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the map, %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		_ = response.Body.Close()
+
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, response.Status)
+	}
+
+	return &responseBody{
+		Reader: &annotatedReader{
+			reader:  response.Body,
+			message: fmt.Sprintf("unable to read %s", rawURL),
+		},
+		body: response.Body,
+	}, nil
+}
+
+// NewHTTPReader creates a map reader that reads the city lines from the
+// http(s) URL as they are received, and closes the response body on Close
+func NewHTTPReader(ctx context.Context, rawURL string) (InputReader, error) {
+	body, err := OpenURL(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReadCloser(body), nil
+}
+
+// responseBody is the response body of the map request,
+// with the read errors identifying the URL
+type responseBody struct {
+	io.Reader
+
+	body io.ReadCloser
+}
+
+func (rb *responseBody) Close() error {
+	return rb.body.Close()
+}
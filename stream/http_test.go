@@ -0,0 +1,318 @@
+package stream
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// postCollector is a collector service that
+// captures the bodies of the POST requests
+type postCollector struct {
+	sync.Mutex
+
+	bodies       []string
+	contentTypes []string
+}
+
+func (pc *postCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	pc.Lock()
+	defer pc.Unlock()
+
+	pc.bodies = append(pc.bodies, string(body))
+	pc.contentTypes = append(pc.contentTypes, r.Header.Get("Content-Type"))
+}
+
+// TestHTTPWriter_Post makes sure the buffered map lines
+// are POSTed once on flush, and again only if new lines are written
+func TestHTTPWriter_Post(t *testing.T) {
+	t.Parallel()
+
+	collector := &postCollector{}
+
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	writer, err := NewHTTPWriter(server.URL)
+	if err != nil {
+		t.Fatalf("unable to create HTTP writer, %v", err)
+	}
+
+	for _, line := range []string{"Foo north=Bar\n", "Bar south=Foo\n"} {
+		assert.NoError(t, writer.Write(line))
+	}
+
+	// Nothing is POSTed before the flush
+	assert.Empty(t, collector.bodies)
+
+	assert.NoError(t, writer.Flush())
+	assert.NoError(t, writer.Flush())
+
+	assert.Equal(t, []string{"Foo north=Bar\nBar south=Foo\n"}, collector.bodies)
+	assert.Equal(t, []string{"text/plain; charset=utf-8"}, collector.contentTypes)
+
+	// Only the lines written after the flush are POSTed on close
+	assert.NoError(t, writer.Write("Baz\n"))
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, []string{"Foo north=Bar\nBar south=Foo\n", "Baz\n"}, collector.bodies)
+}
+
+// TestHTTPWriter_EmptyOutput makes sure an empty
+// output is still POSTed, so the collector is notified
+func TestHTTPWriter_EmptyOutput(t *testing.T) {
+	t.Parallel()
+
+	collector := &postCollector{}
+
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	writer, err := NewHTTPWriter(server.URL)
+	if err != nil {
+		t.Fatalf("unable to create HTTP writer, %v", err)
+	}
+
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, []string{""}, collector.bodies)
+}
+
+// TestHTTPWriter_UnexpectedStatus makes sure
+// non-2xx responses are reported, and the output is kept
+func TestHTTPWriter_UnexpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fail      = true
+		collector = &postCollector{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		collector.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	writer, err := NewHTTPWriter(server.URL)
+	if err != nil {
+		t.Fatalf("unable to create HTTP writer, %v", err)
+	}
+
+	assert.NoError(t, writer.Write("Foo north=Bar\n"))
+
+	err = writer.Flush()
+
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+	assert.ErrorContains(t, err, "503")
+
+	// The output can be POSTed again, once the collector recovers
+	fail = false
+
+	assert.NoError(t, writer.Flush())
+	assert.Equal(t, []string{"Foo north=Bar\n"}, collector.bodies)
+}
+
+// TestHTTPWriter_Cancelled makes sure the POST request
+// respects the writer context and timeout
+func TestHTTPWriter_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	// The collector never responds, until the request is abandoned
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		writer, err := NewHTTPWriter(server.URL, WithHTTPContext(ctx))
+		if err != nil {
+			t.Fatalf("unable to create HTTP writer, %v", err)
+		}
+
+		assert.ErrorIs(t, writer.Flush(), context.Canceled)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		writer, err := NewHTTPWriter(server.URL, WithHTTPTimeout(50*time.Millisecond))
+		if err != nil {
+			t.Fatalf("unable to create HTTP writer, %v", err)
+		}
+
+		err = writer.Flush()
+
+		assert.ErrorContains(t, err, "unable to post the output")
+		assert.ErrorContains(t, err, "Client.Timeout exceeded")
+	})
+}
+
+// TestNewHTTPWriter_InvalidURL makes sure only
+// http(s) URLs are accepted as the output destination
+func TestNewHTTPWriter_InvalidURL(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name string
+		url  string
+	}{
+		{
+			"file path",
+			"output.txt",
+		},
+		{
+			"unsupported scheme",
+			"ftp://collector/map",
+		},
+		{
+			"missing host",
+			"http://",
+		},
+		{
+			"malformed URL",
+			"http://collector:port/map",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewHTTPWriter(testCase.url)
+
+			assert.ErrorIs(t, err, ErrInvalidURL)
+		})
+	}
+}
+
+// TestHTTPReader_Read makes sure the map lines are read from the URL,
+// following the redirects and decoding the gzip content encoding
+func TestHTTPReader_Read(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = http.NewServeMux()
+		compressed = gzipData(t, testMap)
+	)
+
+	mux.HandleFunc("/map.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, testMap)
+	})
+
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/map.txt", http.StatusFound)
+	})
+
+	mux.HandleFunc("/compressed", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.WriteHeader(http.StatusNotAcceptable)
+
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compressed)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, path := range []string{"/map.txt", "/redirect", "/compressed"} {
+		reader, err := NewHTTPReader(context.Background(), server.URL+path)
+		if err != nil {
+			t.Fatalf("unable to create the HTTP reader for %s, %v", path, err)
+		}
+
+		assert.Equal(t, []string{"Foo north=Bar", "Bar south=Foo"}, readAll(reader), path)
+		assert.NoError(t, reader.Close())
+	}
+}
+
+// TestHTTPReader_NotFound makes sure the unsuccessful
+// responses are reported instead of being read as the map
+func TestHTTPReader_NotFound(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := NewHTTPReader(context.Background(), server.URL+"/missing.txt")
+
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+	assert.ErrorContains(t, err, "404")
+}
+
+// TestHTTPReader_ConnectionClosed makes sure the connection closed midway
+// through the body is reported after the lines received before it
+func TestHTTPReader_ConnectionClosed(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Promise more than is sent, so the body is cut short
+		w.Header().Set("Content-Length", "1000")
+		_, _ = io.WriteString(w, "Foo north=Bar\n")
+
+		w.(http.Flusher).Flush()
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	url := server.URL + "/map.txt"
+
+	reader, err := NewHTTPReader(context.Background(), url)
+	if err != nil {
+		t.Fatalf("unable to create the HTTP reader, %v", err)
+	}
+
+	defer reader.Close()
+
+	assert.Equal(t, []string{"Foo north=Bar"}, readAll(reader))
+
+	assert.ErrorIs(t, reader.Err(), io.ErrUnexpectedEOF)
+	assert.ErrorContains(t, reader.Err(), "unable to read "+url)
+}
+
+// TestNewHTTPReader_InvalidURL makes sure
+// only the http(s) URLs are accepted
+func TestNewHTTPReader_InvalidURL(t *testing.T) {
+	t.Parallel()
+
+	for _, rawURL := range []string{"ftp://maps/world.txt", "http://", "world.txt"} {
+		_, err := NewHTTPReader(context.Background(), rawURL)
+
+		assert.ErrorIs(t, err, ErrInvalidURL, rawURL)
+	}
+}
@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"bufio"
+	"io"
+)
+
+// initialLineBufferSize is the initial size of the line buffer,
+// which grows up to the max line size
+const initialLineBufferSize = 4096
+
+// DefaultMaxLineSize is the default max size of a single map line in bytes.
+// It is well above bufio.MaxScanTokenSize (64KB), so generated maps with
+// very long city names are read whole
+const DefaultMaxLineSize = 1024 * 1024
+
+// ScannerReader implements the map reader interface for
+// reading the map line by line from any io.Reader
+type ScannerReader struct {
+	closer  io.Closer // the source closed along with the map reader, if any
+	scanner *bufio.Scanner
+	line    int // the number of the line last read
+}
+
+// NewReader creates a map reader that reads the city lines from any io.Reader,
+// such as an in-memory buffer. The last line doesn't need to end with a newline.
+// The source is left open when the map reader is closed
+func NewReader(r io.Reader) InputReader {
+	return newScannerReader(r, nil)
+}
+
+// NewReadCloser creates a map reader that reads the city lines from the source,
+// such as an HTTP response body, and closes the source when the map reader is closed
+func NewReadCloser(rc io.ReadCloser) InputReader {
+	return newScannerReader(rc, rc)
+}
+
+// NewScannerReader creates a new instance of the scanner reader.
+// If the given reader is also an io.Closer, it is closed along with the map reader
+func NewScannerReader(r io.Reader) InputReader {
+	if rc, ok := r.(io.ReadCloser); ok {
+		return NewReadCloser(rc)
+	}
+
+	return NewReader(r)
+}
+
+// newScannerReader creates a new instance of the scanner reader,
+// which closes the given closer (if any) on Close. The leading byte order mark
+// is skipped, and the Windows line endings (\r\n) are dropped by the line scanning
+func newScannerReader(r io.Reader, closer io.Closer) *ScannerReader {
+	scanner := bufio.NewScanner(skipBOM(r))
+	scanner.Split(bufio.ScanLines)
+	scanner.Buffer(make([]byte, 0, initialLineBufferSize), DefaultMaxLineSize)
+
+	return &ScannerReader{
+		closer:  closer,
+		scanner: scanner,
+	}
+}
+
+// SetMaxLineSize sets the max size of a single map line in bytes,
+// which is DefaultMaxLineSize by default. Longer lines stop the reading,
+// and are reported by Err. The size needs to be set before the first line is read
+func (sr *ScannerReader) SetMaxLineSize(size int) {
+	sr.scanner.Buffer(make([]byte, 0, initialLineBufferSize), size)
+}
+
+// Err returns the error that stopped the reading, if any.
+// Reaching the end of the input is not an error
+func (sr *ScannerReader) Err() error {
+	return sr.scanner.Err()
+}
+
+// HasMoreCities returns false at the end of the input, and when the
+// reading fails (ex. on a line that is too long), so Err needs to be
+// checked to tell a truncated map from a complete one
+func (sr *ScannerReader) HasMoreCities() bool {
+	if !sr.scanner.Scan() {
+		return false
+	}
+
+	sr.line++
+
+	return true
+}
+
+// LineNumber returns the number of the line last read, including
+// the blank and comment lines, so it matches the line in the map source
+func (sr *ScannerReader) LineNumber() int {
+	return sr.line
+}
+
+// ReadCity returns the scanned city line. The scanning
+// errors stop the reading, so they are reported by Err
+func (sr *ScannerReader) ReadCity() (string, error) {
+	return sr.scanner.Text(), nil
+}
+
+func (sr *ScannerReader) Close() error {
+	if sr.closer == nil {
+		return nil
+	}
+
+	return sr.closer.Close()
+}
@@ -0,0 +1,209 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// closeTracker is a string reader that tracks if it was closed
+type closeTracker struct {
+	*strings.Reader
+
+	closed bool
+}
+
+func (ct *closeTracker) Close() error {
+	ct.closed = true
+
+	return nil
+}
+
+// readAll reads all the city lines from the input reader
+func readAll(reader InputReader) []string {
+	lines := make([]string, 0)
+
+	for reader.HasMoreCities() {
+		line, err := reader.ReadCity()
+		if err != nil {
+			break
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// TestScannerReader_ReadCities makes sure the city lines
+// are properly read from the underlying reader
+func TestScannerReader_ReadCities(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		input string
+
+		expectedLines []string
+	}{
+		{
+			"empty input",
+			"",
+			[]string{},
+		},
+		{
+			"single line without newline",
+			"Foo north=Bar",
+			[]string{"Foo north=Bar"},
+		},
+		{
+			"multiple lines",
+			"Foo north=Bar\nBar south=Foo\n",
+			[]string{"Foo north=Bar", "Bar south=Foo"},
+		},
+		{
+			"windows line endings",
+			"Foo north=Bar\r\nBar south=Foo\r\n",
+			[]string{"Foo north=Bar", "Bar south=Foo"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := NewScannerReader(strings.NewReader(testCase.input))
+
+			assert.Equal(t, testCase.expectedLines, readAll(reader))
+			assert.NoError(t, reader.Close())
+		})
+	}
+}
+
+// TestScannerReader_Close makes sure the underlying
+// reader is closed, if it is closable
+func TestScannerReader_Close(t *testing.T) {
+	t.Parallel()
+
+	source := &closeTracker{
+		Reader: strings.NewReader("Foo"),
+	}
+
+	reader := NewScannerReader(source)
+
+	assert.Equal(t, []string{"Foo"}, readAll(reader))
+	assert.False(t, source.closed)
+
+	assert.NoError(t, reader.Close())
+	assert.True(t, source.closed)
+}
+
+// TestScannerReader_ReadError makes sure read errors stop the reading,
+// and are reported instead of passing for the end of the input
+func TestScannerReader_ReadError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errRead = errors.New("connection reset")
+		reader  = newScannerReader(io.MultiReader(
+			strings.NewReader("Foo north=Bar\n"),
+			iotest.ErrReader(errRead),
+		), nil)
+	)
+
+	assert.Equal(t, []string{"Foo north=Bar"}, readAll(reader))
+	assert.ErrorIs(t, reader.Err(), errRead)
+
+	// A clean end of the input is not an error
+	reader = newScannerReader(strings.NewReader("Foo north=Bar\n"), nil)
+
+	assert.Equal(t, []string{"Foo north=Bar"}, readAll(reader))
+	assert.NoError(t, reader.Err())
+}
+
+// TestScannerReader_LineNumber makes sure the line numbers match
+// the lines in the source, including the blank and comment lines
+func TestScannerReader_LineNumber(t *testing.T) {
+	t.Parallel()
+
+	reader := NewReader(strings.NewReader("# seed: 42\n\nFoo north=Bar\n  \nBar south=Foo"))
+
+	positioned, ok := reader.(PositionedReader)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Equal(t, 0, positioned.LineNumber())
+
+	numbers := make(map[string]int)
+
+	for reader.HasMoreCities() {
+		line, err := reader.ReadCity()
+		assert.NoError(t, err)
+
+		numbers[line] = positioned.LineNumber()
+	}
+
+	assert.Equal(t, 3, numbers["Foo north=Bar"])
+	assert.Equal(t, 5, numbers["Bar south=Foo"])
+	assert.Equal(t, 5, positioned.LineNumber())
+}
+
+// TestNewReader makes sure the city lines are read from an in-memory
+// source, which is left open when the map reader is closed
+func TestNewReader(t *testing.T) {
+	t.Parallel()
+
+	source := &closeTracker{
+		Reader: strings.NewReader("Foo north=Bar\nBar south=Foo"),
+	}
+
+	reader := NewReader(source)
+
+	// The trailing line without a newline is read as well
+	assert.Equal(t, []string{"Foo north=Bar", "Bar south=Foo"}, readAll(reader))
+
+	assert.NoError(t, reader.Close())
+	assert.False(t, source.closed)
+}
+
+// TestNewReader_ReadError makes sure an error in the middle
+// of the source is reported after the lines read before it
+func TestNewReader_ReadError(t *testing.T) {
+	t.Parallel()
+
+	errRead := errors.New("unexpected EOF")
+
+	reader := NewReader(io.MultiReader(
+		strings.NewReader("Foo north=Bar\nBar south=Foo\n"),
+		iotest.ErrReader(errRead),
+	))
+
+	assert.Equal(t, []string{"Foo north=Bar", "Bar south=Foo"}, readAll(reader))
+
+	assert.ErrorIs(t, reader.Err(), errRead)
+}
+
+// TestNewReadCloser makes sure the source is
+// closed when the map reader is closed
+func TestNewReadCloser(t *testing.T) {
+	t.Parallel()
+
+	source := &closeTracker{
+		Reader: strings.NewReader("Foo"),
+	}
+
+	reader := NewReadCloser(source)
+
+	assert.Equal(t, []string{"Foo"}, readAll(reader))
+	assert.False(t, source.closed)
+
+	assert.NoError(t, reader.Close())
+	assert.True(t, source.closed)
+}
@@ -0,0 +1,44 @@
+package stream
+
+// NewSliceReader creates a map reader that reads the city lines from memory,
+// which is intended for tests, and for embedding the simulation
+// in programs that build the map themselves
+func NewSliceReader(lines []string) InputReader {
+	return newLinesReader(nil, lines)
+}
+
+// SliceWriter implements the map writer interface for
+// collecting the output lines in memory, which is intended for tests,
+// and for embedding the simulation in programs that process the map themselves
+type SliceWriter struct {
+	lines []string
+}
+
+// NewSliceWriter creates a new instance of the in-memory map writer
+func NewSliceWriter() *SliceWriter {
+	return &SliceWriter{
+		lines: make([]string, 0),
+	}
+}
+
+func (sw *SliceWriter) Write(s string) error {
+	sw.lines = append(sw.lines, s)
+
+	return nil
+}
+
+// Flush is a no-op, since the lines are not buffered
+func (sw *SliceWriter) Flush() error {
+	return nil
+}
+
+// Close is a no-op, and the lines can still be read once the writer is closed
+func (sw *SliceWriter) Close() error {
+	return nil
+}
+
+// Lines returns a copy of the lines written so far, as they were written,
+// including the line endings
+func (sw *SliceWriter) Lines() []string {
+	return append([]string(nil), sw.lines...)
+}
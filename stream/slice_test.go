@@ -0,0 +1,83 @@
+package stream
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSliceReader makes sure the lines are read in order,
+// and an empty map has no cities
+func TestSliceReader(t *testing.T) {
+	t.Parallel()
+
+	reader := NewSliceReader([]string{"Foo north=Bar", "Bar south=Foo"})
+	lines := make([]string, 0)
+
+	for reader.HasMoreCities() {
+		line, err := reader.ReadCity()
+
+		assert.NoError(t, err)
+
+		lines = append(lines, line)
+	}
+
+	assert.NoError(t, reader.Err())
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, []string{"Foo north=Bar", "Bar south=Foo"}, lines)
+
+	assert.False(t, NewSliceReader(nil).HasMoreCities())
+}
+
+// TestSliceWriter makes sure the written lines are collected,
+// and the returned lines can't change the writer
+func TestSliceWriter(t *testing.T) {
+	t.Parallel()
+
+	writer := NewSliceWriter()
+
+	assert.Empty(t, writer.Lines())
+
+	assert.NoError(t, writer.Write("Foo north=Bar\n"))
+	assert.NoError(t, writer.Write("Bar south=Foo\n"))
+	assert.NoError(t, writer.Flush())
+	assert.NoError(t, writer.Close())
+
+	lines := writer.Lines()
+	lines[0] = "Baz\n"
+
+	assert.Equal(t, []string{"Foo north=Bar\n", "Bar south=Foo\n"}, writer.Lines())
+}
+
+func ExampleNewSliceReader() {
+	reader := NewSliceReader([]string{
+		"Foo north=Bar west=Baz",
+		"Bar south=Foo",
+	})
+
+	for reader.HasMoreCities() {
+		line, err := reader.ReadCity()
+		if err != nil {
+			break
+		}
+
+		fmt.Println(line)
+	}
+
+	// Output:
+	// Foo north=Bar west=Baz
+	// Bar south=Foo
+}
+
+func ExampleNewSliceWriter() {
+	writer := NewSliceWriter()
+
+	_ = writer.Write("Foo north=Bar\n")
+	_ = writer.Write("Bar south=Foo\n")
+
+	fmt.Printf("%q\n", writer.Lines())
+
+	// Output:
+	// ["Foo north=Bar\n" "Bar south=Foo\n"]
+}
@@ -1,18 +1,35 @@
 package stream
 
-// InputReader defines the base map reader interface
+// InputReader defines the base map reader interface.
+// Much like bufio.Scanner, the reading stops on the first error,
+// so Err needs to be checked once HasMoreCities returns false
 type InputReader interface {
 	// HasMoreCities returns a status indicating if there are more cities
-	// to parse
+	// to parse. It returns false at the end of the map, and when the reading fails
 	HasMoreCities() bool
 
-	// ReadCity reads a single city line from the map
-	ReadCity() string
+	// ReadCity reads a single city line from the map,
+	// or returns the error of reading the line
+	ReadCity() (string, error)
+
+	// Err returns the error that stopped the reading, if any.
+	// Reaching the end of the map is not an error
+	Err() error
 
 	// Close closes the map reader
 	Close() error
 }
 
+// PositionedReader is a map reader that keeps track of the
+// position of the city lines in the map source, for the diagnostics
+type PositionedReader interface {
+	InputReader
+
+	// LineNumber returns the number of the line last read from the map source,
+	// starting from 1, or 0 if no line has been read yet
+	LineNumber() int
+}
+
 // OutputWriter defines the base map writer interface
 type OutputWriter interface {
 	// Write writes a single output line to the output stream
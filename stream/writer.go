@@ -0,0 +1,62 @@
+package stream
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Writer implements the map writer interface for writing the output
+// lines to any io.Writer. The lines are buffered until the writer is flushed
+type Writer struct {
+	closer   io.Closer // the destination closed along with the map writer, if any
+	buffered *bufio.Writer
+}
+
+// NewWriter creates a map writer that outputs the lines to any io.Writer,
+// such as an HTTP response or an in-memory buffer. If the given writer is also
+// an io.Closer, it is closed along with the map writer, after the lines are flushed
+func NewWriter(w io.Writer) OutputWriter {
+	closer, _ := w.(io.Closer)
+
+	return newWriter(w, closer)
+}
+
+// newWriter creates a new instance of the buffered writer,
+// which closes the given closer (if any) on Close
+func newWriter(w io.Writer, closer io.Closer) *Writer {
+	return &Writer{
+		closer:   closer,
+		buffered: bufio.NewWriter(w),
+	}
+}
+
+func (w *Writer) Write(s string) error {
+	if _, err := w.buffered.WriteString(s); err != nil {
+		return fmt.Errorf("unable to write the output, %w", err)
+	}
+
+	return nil
+}
+
+func (w *Writer) Flush() error {
+	if err := w.buffered.Flush(); err != nil {
+		return fmt.Errorf("unable to flush the output, %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes the buffered lines, and closes the destination, if it's closable.
+// The destination is closed even if the flush fails, and the flush error is returned
+func (w *Writer) Close() error {
+	flushErr := w.Flush()
+
+	if w.closer != nil {
+		if err := w.closer.Close(); err != nil && flushErr == nil {
+			return err
+		}
+	}
+
+	return flushErr
+}
@@ -0,0 +1,72 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// eventWriter is a closable writer that records the writes and the close,
+// and fails the writes with the set error
+type eventWriter struct {
+	events []string
+	err    error
+}
+
+func (ew *eventWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+
+	ew.events = append(ew.events, "write "+string(p))
+
+	return len(p), nil
+}
+
+func (ew *eventWriter) Close() error {
+	ew.events = append(ew.events, "close")
+
+	return nil
+}
+
+// TestWriter_FlushOnClose makes sure the lines are buffered until the flush,
+// and the buffered lines are flushed before the destination is closed
+func TestWriter_FlushOnClose(t *testing.T) {
+	t.Parallel()
+
+	destination := &eventWriter{}
+	writer := NewWriter(destination)
+
+	assert.NoError(t, writer.Write("Foo north=Bar\n"))
+	assert.NoError(t, writer.Write("Bar south=Foo\n"))
+	assert.Empty(t, destination.events)
+
+	assert.NoError(t, writer.Close())
+	assert.Equal(
+		t,
+		[]string{"write Foo north=Bar\nBar south=Foo\n", "close"},
+		destination.events,
+	)
+}
+
+// TestWriter_WriteError makes sure the errors of the destination
+// are reported, and the destination is closed regardless
+func TestWriter_WriteError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errWrite    = errors.New("broken pipe")
+		destination = &eventWriter{
+			err: errWrite,
+		}
+		writer = NewWriter(destination)
+	)
+
+	assert.NoError(t, writer.Write("Foo north=Bar\n"))
+	assert.ErrorIs(t, writer.Flush(), errWrite)
+
+	// The failed flush is sticky
+	assert.ErrorIs(t, writer.Close(), errWrite)
+	assert.Equal(t, []string{"close"}, destination.events)
+}
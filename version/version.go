@@ -0,0 +1,19 @@
+package version
+
+import "fmt"
+
+// The build information of the program, injected at build time using the linker flags:
+//
+//	go build -ldflags "-X github.com/zivkovicmilos/alien-invasion/version.Version=v1.0.0"
+//
+// Builds without the linker flags are marked as development builds
+var (
+	Version   = "dev" // the module version
+	Commit    = "dev" // the git commit the program was built from
+	BuildDate = "dev" // the date the program was built on
+)
+
+// String returns the human-readable build information
+func String() string {
+	return fmt.Sprintf("%s (commit %s, built %s)", Version, Commit, BuildDate)
+}
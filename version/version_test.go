@@ -0,0 +1,13 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVersion_String makes sure the build information
+// is properly formatted
+func TestVersion_String(t *testing.T) {
+	assert.Equal(t, "dev (commit dev, built dev)", String())
+}